@@ -56,11 +56,23 @@ type Diff struct {
 type BreakingChange struct {
 	Message                string
 	DocumentationReference string
+	// Severity is error for changes blocking the PR, or warning, info or
+	// skipped for changes that were downgraded by an override or rules that
+	// couldn't be checked.
+	Severity string
+	// Reason the severity was overridden.
+	Reason string
+}
+
+// Blocking returns whether the breaking change blocks the PR.
+func (bc BreakingChange) Blocking() bool {
+	return bc.Severity == breakingChangeSeverityError
 }
 
 type MissingTestInfo struct {
-	SuggestedTest string
-	Tests         []string
+	NotUpdatedFields []string
+	SuggestedTest    string
+	Tests            []string
 }
 
 type MissingDocInfo struct {
@@ -80,20 +92,31 @@ type Errors struct {
 }
 
 type diffCommentData struct {
-	Diffs                []Diff
-	BreakingChanges      []BreakingChange
-	MissingServiceLabels []string
-	MissingTests         map[string]*MissingTestInfo
-	MissingDocs          *MissingDocsSummary
-	AddedResources       []string
-	Errors               []Errors
+	Diffs                      []Diff
+	BreakingChanges            []BreakingChange
+	NonBlockingBreakingChanges []BreakingChange
+	MissingServiceLabels       []string
+	MissingTests               map[string]*MissingTestInfo
+	MissingDocs                *MissingDocsSummary
+	AddedResources             []string
+	RenamedFieldReleaseNotes   []string
+	Errors                     []Errors
 }
 
 type simpleSchemaDiff struct {
 	AddedResources, ModifiedResources, RemovedResources []string
+	// Deprecation release notes for renamed fields, without the product
+	RenamedFieldReleaseNotes []string
 }
 
 const allowBreakingChangesLabel = "override-breaking-change"
+
+// The severity overrides applied to breaking changes. They are read from the
+// base branch, so that a PR can't override its own breaking changes, and
+// written next to the diff processor.
+const breakingChangesConfigPath = "tools/diff-processor/breaking_changes.yaml"
+const baseBreakingChangesConfig = "base_breaking_changes.yaml"
+const breakingChangeSeverityError = "error"
 const allowMissingServiceLabelsLabel = "override-missing-service-labels"
 const allowMultipleResourcesLabel = "override-multiple-resources"
 
@@ -295,6 +318,7 @@ func execGenerateComment(prNumber int, ghTokenMagicModules, buildId, buildStep,
 	// The breaking changes are unique across both provider versions
 	uniqueAddedResources := map[string]struct{}{}
 	uniqueAffectedResources := map[string]struct{}{}
+	uniqueRenamedFieldReleaseNotes := map[string]struct{}{}
 	uniqueBreakingChanges := map[string]BreakingChange{}
 	diffProcessorPath := filepath.Join(mmLocalPath, "tools", "diff-processor")
 	diffProcessorEnv := map[string]string{
@@ -305,6 +329,10 @@ func execGenerateComment(prNumber int, ghTokenMagicModules, buildId, buildStep,
 		"GOPATH": os.Getenv("GOPATH"),
 		"HOME":   os.Getenv("HOME"),
 	}
+	breakingChangesConfig, err := writeBaseBreakingChangesConfig(diffProcessorPath, pullRequest.BaseRef, gh, rnr)
+	if err != nil {
+		fmt.Println("Not applying breaking change overrides: ", err)
+	}
 	for _, repo := range []source.Repo{tpgRepo, tpgbRepo} {
 		if !repo.Cloned {
 			fmt.Println("Skipping diff processor; repo failed to clone: ", repo.Name)
@@ -321,7 +349,7 @@ func execGenerateComment(prNumber int, ghTokenMagicModules, buildId, buildStep,
 			continue
 		}
 
-		breakingChanges, err := computeBreakingChanges(diffProcessorPath, rnr)
+		breakingChanges, err := computeBreakingChanges(diffProcessorPath, breakingChangesConfig, rnr)
 		if err != nil {
 			fmt.Println("computing breaking changes: ", err)
 			errors[repo.Title] = append(errors[repo.Title], "The diff processor crashed while computing breaking changes. This is usually due to the downstream provider failing to compile.")
@@ -359,12 +387,21 @@ func execGenerateComment(prNumber int, ghTokenMagicModules, buildId, buildStep,
 		for _, resource := range append(simpleDiff.ModifiedResources, simpleDiff.RemovedResources...) {
 			uniqueAffectedResources[resource] = struct{}{}
 		}
+		for _, note := range simpleDiff.RenamedFieldReleaseNotes {
+			uniqueRenamedFieldReleaseNotes[note] = struct{}{}
+		}
 	}
 	breakingChangesSlice := maps.Values(uniqueBreakingChanges)
 	sort.Slice(breakingChangesSlice, func(i, j int) bool {
 		return breakingChangesSlice[i].Message < breakingChangesSlice[j].Message
 	})
-	data.BreakingChanges = breakingChangesSlice
+	for _, breakingChange := range breakingChangesSlice {
+		if breakingChange.Blocking() {
+			data.BreakingChanges = append(data.BreakingChanges, breakingChange)
+		} else {
+			data.NonBlockingBreakingChanges = append(data.NonBlockingBreakingChanges, breakingChange)
+		}
+	}
 
 	// Check if multiple resources were added.
 	multipleResourcesState := "success"
@@ -384,6 +421,8 @@ func execGenerateComment(prNumber int, ghTokenMagicModules, buildId, buildStep,
 	}
 	data.AddedResources = maps.Keys(uniqueAddedResources)
 	slices.Sort(data.AddedResources)
+	data.RenamedFieldReleaseNotes = maps.Keys(uniqueRenamedFieldReleaseNotes)
+	slices.Sort(data.RenamedFieldReleaseNotes)
 
 	// Compute affected resources based on changed files
 	changedFilesAffectedResources := map[string]struct{}{}
@@ -439,7 +478,7 @@ func execGenerateComment(prNumber int, ghTokenMagicModules, buildId, buildStep,
 
 	// Update breaking changes status on PR
 	breakingState := "success"
-	if len(uniqueBreakingChanges) > 0 {
+	if len(data.BreakingChanges) > 0 {
 		breakingState = "failure"
 		// If fetching the PR failed, Labels will be empty
 		for _, label := range pullRequest.Labels {
@@ -539,11 +578,32 @@ func buildDiffProcessor(diffProcessorPath, providerLocalPath string, env map[str
 	return rnr.PopDir()
 }
 
-func computeBreakingChanges(diffProcessorPath string, rnr ExecRunner) ([]BreakingChange, error) {
+// Writes the breaking change overrides of the base branch next to the diff
+// processor, and returns their path relative to it.
+func writeBaseBreakingChangesConfig(diffProcessorPath, baseRef string, gh GithubClient, rnr ExecRunner) (string, error) {
+	if baseRef == "" {
+		return "", fmt.Errorf("unknown base branch")
+	}
+	config, err := gh.GetFileContent(breakingChangesConfigPath, baseRef)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s from %s: %w", breakingChangesConfigPath, baseRef, err)
+	}
+	if err := rnr.WriteFile(filepath.Join(diffProcessorPath, baseBreakingChangesConfig), config); err != nil {
+		return "", err
+	}
+	return baseBreakingChangesConfig, nil
+}
+
+// Computes the breaking changes, applying the overrides in config if set.
+func computeBreakingChanges(diffProcessorPath, config string, rnr ExecRunner) ([]BreakingChange, error) {
 	if err := rnr.PushDir(diffProcessorPath); err != nil {
 		return nil, err
 	}
-	output, err := rnr.Run("bin/diff-processor", []string{"breaking-changes"}, nil)
+	args := []string{"breaking-changes"}
+	if config != "" {
+		args = append(args, "--config", config)
+	}
+	output, err := rnr.Run("bin/diff-processor", args, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"testing"
 
+	"magician/github"
 	"magician/source"
 
 	"github.com/stretchr/testify/assert"
@@ -150,6 +151,88 @@ func TestExecGenerateComment(t *testing.T) {
 	}
 }
 
+func TestExecGenerateComment_breakingChangeSeverity(t *testing.T) {
+	cases := map[string]struct {
+		output             string
+		wantState          string
+		wantStrings        []string
+		notExpectedStrings []string
+	}{
+		"error blocks": {
+			output:      `[{"Message": "Field a became required", "DocumentationReference": "doc1", "Severity": "error"}]`,
+			wantState:   "failure",
+			wantStrings: []string{"## Breaking Change(s) Detected", "- Field a became required - [reference](doc1)"},
+			notExpectedStrings: []string{
+				"## Non-blocking Breaking Change(s)",
+			},
+		},
+		"overridden severities don't block": {
+			output: `[
+  {"Message": "Field a became ForceNew", "DocumentationReference": "doc1", "Severity": "warning", "Reason": "Never updatable."},
+  {"Message": "Field b default changed", "DocumentationReference": "doc2", "Severity": "info", "Reason": "Matches the API."}
+]`,
+			wantState: "success",
+			wantStrings: []string{
+				"## Non-blocking Breaking Change(s)",
+				"- info: Field b default changed - [reference](doc2) (Matches the API.)",
+				"- warning: Field a became ForceNew - [reference](doc1) (Never updatable.)",
+			},
+			notExpectedStrings: []string{
+				"## Breaking Change(s) Detected",
+			},
+		},
+	}
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			mr := NewMockRunner()
+			mr.(*mockRunner).cmdResults["/mock/dir/magic-modules/tools/diff-processor bin/diff-processor [breaking-changes --config base_breaking_changes.yaml] map[]"] = tc.output
+			gh := &mockGithub{
+				calledMethods: make(map[string][][]any),
+				pullRequest:   github.PullRequest{BaseRef: "main"},
+				fileContents: map[string]string{
+					"main:tools/diff-processor/breaking_changes.yaml": "overrides: []\n",
+				},
+			}
+			ctlr := source.NewController("/mock/dir/go", "modular-magician", "*******", mr)
+			for _, repo := range []string{
+				"terraform-provider-google",
+				"terraform-provider-google-beta",
+				"terraform-google-conversion",
+			} {
+				mr.WriteFile(fmt.Sprintf("/workspace/commitSHA_modular-magician_%s-old.txt", repo), "1a2a3a4a")
+				mr.WriteFile(fmt.Sprintf("/workspace/commitSHA_modular-magician_%s.txt", repo), "1a2a3a4b")
+			}
+			execGenerateComment(123456, "*******", "build1", "17", "project1", "sha1", gh, mr, ctlr)
+
+			// The overrides are read from the base branch, not the PR's checkout
+			if got, _ := mr.ReadFile("/mock/dir/magic-modules/tools/diff-processor/base_breaking_changes.yaml"); got != "overrides: []\n" {
+				t.Errorf("base breaking change overrides = %q, want the overrides of the base branch", got)
+			}
+
+			var state string
+			for _, call := range gh.calledMethods["PostBuildStatus"] {
+				if call[1] == "terraform-provider-breaking-change-test" {
+					state = call[2].(string)
+				}
+			}
+			if state != tc.wantState {
+				t.Errorf("breaking change status = %q, want %q", state, tc.wantState)
+			}
+			comments := gh.calledMethods["PostComment"]
+			if len(comments) != 1 {
+				t.Fatalf("got %d comments, want 1", len(comments))
+			}
+			comment := comments[0][1].(string)
+			for _, s := range tc.wantStrings {
+				assert.Contains(t, comment, s)
+			}
+			for _, s := range tc.notExpectedStrings {
+				assert.NotContains(t, comment, s)
+			}
+		})
+	}
+}
+
 func TestFormatDiffComment(t *testing.T) {
 	cases := map[string]struct {
 		data               diffCommentData
@@ -243,6 +326,21 @@ func TestFormatDiffComment(t *testing.T) {
 				"## Missing test report",
 			},
 		},
+		"renamed field release notes are displayed": {
+			data: diffCommentData{
+				RenamedFieldReleaseNotes: []string{
+					"deprecated `old_name` field in `google_redis_instance` resource. Use `new_name` instead.",
+				},
+			},
+			expectedStrings: []string{
+				"## Renamed fields",
+				"```release-note:deprecation\nPRODUCT: deprecated `old_name` field in `google_redis_instance` resource. Use `new_name` instead.\n```",
+			},
+			notExpectedStrings: []string{
+				"## Breaking Change(s) Detected",
+				"## Errors",
+			},
+		},
 		"multiple resources are displayed": {
 			data: diffCommentData{
 				AddedResources: []string{"google_redis_instance", "google_alloydb_cluster"},
@@ -281,6 +379,21 @@ func TestFormatDiffComment(t *testing.T) {
 				"## Errors",
 			},
 		},
+		"fields not updated by tests are displayed": {
+			data: diffCommentData{
+				MissingTests: map[string]*MissingTestInfo{
+					"resource": {
+						NotUpdatedFields: []string{"field-a", "field-b"},
+						Tests:            []string{"test-a"},
+						SuggestedTest:    "x",
+					},
+				},
+			},
+			expectedStrings: []string{
+				"## Missing test report",
+				"These fields are set by tests but never updated: `field-a`, `field-b`.",
+			},
+		},
 		"missing docs are displayed": {
 			data: diffCommentData{
 				MissingDocs: &MissingDocsSummary{
@@ -17,6 +17,7 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 
 	"magician/github"
 )
@@ -30,6 +31,7 @@ type mockGithub struct {
 	teamMembers         map[string][]github.User
 	calledMethods       map[string][][]any
 	commitMessage       string
+	fileContents        map[string]string
 }
 
 func (m *mockGithub) GetPullRequest(prNumber string) (github.PullRequest, error) {
@@ -67,6 +69,15 @@ func (m *mockGithub) GetCommitMessage(owner, repo, sha string) (string, error) {
 	return m.commitMessage, nil
 }
 
+func (m *mockGithub) GetFileContent(path, ref string) (string, error) {
+	m.calledMethods["GetFileContent"] = append(m.calledMethods["GetFileContent"], []any{path, ref})
+	content, ok := m.fileContents[ref+":"+path]
+	if !ok {
+		return "", fmt.Errorf("file %s not found at %s", path, ref)
+	}
+	return content, nil
+}
+
 func (m *mockGithub) GetTeamMembers(organization, team string) ([]github.User, error) {
 	m.calledMethods["GetTeamMembers"] = append(m.calledMethods["GetTeamMembers"], []any{organization, team})
 	if team == "" {
@@ -46,6 +46,7 @@ type PullRequest struct {
 	Labels         []Label `json:"labels"`
 	MergeCommitSha string  `json:"merge_commit_sha"`
 	Merged         bool    `json:"merged"`
+	BaseRef        string  `json:"base_ref"`
 }
 
 type PullRequestComment struct {
@@ -151,6 +152,19 @@ func (c *Client) GetCommitMessage(owner, repo, sha string) (string, error) {
 	return "", fmt.Errorf("no commit message found")
 }
 
+// GetFileContent gets the content of a file of the repository at a ref
+func (c *Client) GetFileContent(path, ref string) (string, error) {
+	file, _, _, err := c.gh.Repositories.GetContents(c.ctx, defaultOwner, defaultRepo, path, &gh.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		return "", err
+	}
+	if file == nil {
+		return "", fmt.Errorf("%s is not a file at %s", path, ref)
+	}
+
+	return file.GetContent()
+}
+
 // GetPullRequestComments gets all comments on a PR, handling pagination
 func (c *Client) GetPullRequestComments(prNumber string) ([]PullRequestComment, error) {
 	num, err := strconv.Atoi(prNumber)
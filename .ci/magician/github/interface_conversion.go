@@ -28,6 +28,7 @@ func convertGHPullRequest(pr *gh.PullRequest) PullRequest {
 		Labels:         labels,
 		MergeCommitSha: pr.GetMergeCommitSHA(),
 		Merged:         pr.GetMerged(),
+		BaseRef:        pr.GetBase().GetRef(),
 	}
 }
 
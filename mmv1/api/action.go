@@ -0,0 +1,238 @@
+// Copyright 2025 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+)
+
+// Describes a custom method on a resource, such as `:restart`, `:failover`
+// or `:rotateKey`. Each action is generated as a trigger-style Terraform
+// resource named after its parent resource and the action
+// (e.g. `google_sql_database_instance_restart`). The custom method is called
+// when the resource is created, and again whenever its arguments or
+// `triggers` change. Deleting the resource only removes it from state.
+type Action struct {
+	// [Required] The name of the action in lowerCamelCase, e.g. `rotateKey`.
+	Name string
+
+	// [Required] A description of the action that's surfaced in provider
+	// documentation.
+	Description string
+
+	// [Optional] The custom method appended to the resource's self link.
+	// Defaults to `:{{name}}`, e.g. `:rotateKey`.
+	Method string `yaml:"method,omitempty"`
+
+	// [Optional] The URL of the custom method, relative to the product base
+	// URL. Overrides `method`, and is only needed when the custom method is
+	// not called on the resource's self link.
+	Url string `yaml:"url,omitempty"`
+
+	// [Optional] The HTTP verb used to call the custom method. Defaults to POST.
+	Verb string `yaml:"verb,omitempty"`
+
+	// [Optional] The minimum API version this action is in. Defaults to the
+	// min_version of the resource.
+	MinVersion string `yaml:"min_version,omitempty"`
+
+	// [Optional] Set if the custom method returns a long-running operation
+	// that should be waited on. Only `OpAsync` is supported, and the product
+	// must provide an operation waiter (usually through `autogen_async`).
+	Async *Async `yaml:"async,omitempty"`
+
+	// [Optional] Fields sent in the request body of the custom method. They
+	// are exposed as arguments of the generated resource, and changing any of
+	// them calls the custom method again.
+	Properties []*Type `yaml:"properties,omitempty"`
+
+	// [Optional] Examples in documentation, backed by generated tests.
+	// Import tests are always skipped, as actions can't be imported.
+	Examples []resource.Examples `yaml:"examples,omitempty"`
+
+	Timeouts *Timeouts `yaml:"timeouts,omitempty"`
+
+	// The resource the custom method is called on.
+	ResourceMetadata *Resource `yaml:"-"`
+
+	// The generated resource for this action. It is built in SetDefault so
+	// that body fields get their own schema, expanders and documentation
+	// without clashing with the parent resource.
+	ActionResource *Resource `yaml:"-"`
+}
+
+func (a *Action) SetDefault(r *Resource) {
+	a.ResourceMetadata = r
+
+	if a.Verb == "" {
+		a.Verb = "POST"
+	}
+	if a.Method == "" {
+		a.Method = fmt.Sprintf(":%s", a.Name)
+	}
+	if a.MinVersion == "" {
+		a.MinVersion = r.MinVersion
+	}
+	if a.Timeouts == nil {
+		a.Timeouts = NewTimeouts()
+	}
+
+	examples := make([]resource.Examples, len(a.Examples))
+	for i, e := range a.Examples {
+		e.ExcludeImportTest = true
+		examples[i] = e
+	}
+
+	triggers := NewProperty("triggers", "triggers", []func(*Type){
+		propertyWithType("KeyValuePairs"),
+		propertyWithClientSide(true),
+		propertyWithImmutable(true),
+		propertyWithDescription("Arbitrary map of values that, when changed, will call the action again."),
+	})
+
+	ar := &Resource{
+		Name:                 fmt.Sprintf("%s%s", r.Name, google.Camelize(a.Name, "upper")),
+		ApiResourceTypeKind:  r.ApiResourceTypeKind,
+		Description:          a.Description,
+		References:           r.References,
+		BaseUrl:              r.BaseUrl,
+		SelfLink:             a.Uri(),
+		CreateUrl:            a.Uri(),
+		CreateVerb:           a.Verb,
+		MinVersion:           a.MinVersion,
+		LegacyName:           fmt.Sprintf("%s_%s", r.TerraformName(), google.Underscore(a.Name)),
+		Immutable:            true,
+		ExcludeImport:        true,
+		ExcludeDelete:        true,
+		ExcludeSweeper:       true,
+		ExcludeTgc:           true,
+		Async:                a.Async,
+		Timeouts:             a.Timeouts,
+		ErrorRetryPredicates: r.ErrorRetryPredicates,
+		ErrorAbortPredicates: r.ErrorAbortPredicates,
+		Examples:             examples,
+		Docs: resource.Docs{
+			Warning: "Deleting this resource only removes it from the Terraform state. The action itself is not reverted.",
+		},
+		VirtualFields:     []*Type{triggers},
+		Parameters:        a.urlParameters(r),
+		Properties:        a.Properties,
+		TargetVersionName: r.TargetVersionName,
+		SourceYamlFile:    r.SourceYamlFile,
+		ActionMetadata:    a,
+	}
+	if ar.ApiResourceTypeKind == "" {
+		ar.ApiResourceTypeKind = r.Name
+	}
+	ar.SetDefault(r.ProductMetadata)
+	a.ActionResource = ar
+}
+
+func (a *Action) Validate(rName string) {
+	if a.Name == "" {
+		log.Fatalf("Missing `name` for action in resource %s", rName)
+	}
+
+	if a.Description == "" {
+		log.Fatalf("Missing `description` for action %s in resource %s", a.Name, rName)
+	}
+
+	allowed := []string{"GET", "POST", "PUT", "PATCH"}
+	if !slices.Contains(allowed, a.Verb) {
+		log.Fatalf("Value on `verb` for action %s should be one of %#v in resource %s", a.Name, allowed, rName)
+	}
+
+	if a.Async != nil {
+		if !a.Async.IsA("OpAsync") {
+			log.Fatalf("Only `OpAsync` is supported for action %s in resource %s", a.Name, rName)
+		}
+		a.Async.Validate()
+	}
+
+	for _, p := range a.Properties {
+		if p.Name == "triggers" {
+			log.Fatalf("Property `triggers` is reserved for action %s in resource %s", a.Name, rName)
+		}
+		p.Validate(rName)
+	}
+
+	for _, e := range a.Examples {
+		e.Validate(rName)
+	}
+}
+
+// Returns the URL of the custom method, relative to the product base URL.
+// For example: "projects/{{project}}/instances/{{name}}:restart"
+func (a Action) Uri() string {
+	if a.Url != "" {
+		return a.Url
+	}
+
+	return fmt.Sprintf("%s%s", a.ResourceMetadata.SelfLinkUri(), a.Method)
+}
+
+// Returns true if the operation waiter of the product takes a project. The
+// waiter is generated from the first resource of the product with
+// `autogen_async`, so it decides the waiter's signature. Otherwise, the waiter
+// is expected to take a project if the action's URL or async settings use one.
+func (a Action) IncludeProjectForOperation() bool {
+	if a.ResourceMetadata.ProductMetadata != nil {
+		for _, o := range a.ResourceMetadata.ProductMetadata.Objects {
+			if o.AutogenAsync {
+				return o.IncludeProjectForOperation()
+			}
+		}
+	}
+	return strings.Contains(a.Uri(), "{{project}}") || (a.Async != nil && a.Async.IncludeProject)
+}
+
+func (a Action) TerraformName() string {
+	return a.ActionResource.TerraformName()
+}
+
+// Builds a required, immutable parameter for every value in the custom
+// method URL, reusing the description from the parent resource where one
+// exists. `project` is left out as the generated resource handles it like
+// any other resource does.
+func (a Action) urlParameters(r *Resource) []*Type {
+	var params []*Type
+	for _, id := range r.ExtractIdentifiers(a.Uri()) {
+		if id == "project" {
+			continue
+		}
+
+		description := fmt.Sprintf("The `%s` of the %s to call the action on.", id, r.Name)
+		for _, p := range r.AllUserProperties() {
+			if google.Underscore(p.Name) == id && p.Description != "" {
+				description = p.Description
+				break
+			}
+		}
+
+		params = append(params, NewProperty(google.Camelize(id, "lower"), google.Camelize(id, "lower"), []func(*Type){
+			propertyWithType("String"),
+			propertyWithRequired(true),
+			propertyWithImmutable(true),
+			propertyWithUrlParamOnly(true),
+			propertyWithDescription(description),
+		}))
+	}
+	return params
+}
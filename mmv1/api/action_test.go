@@ -0,0 +1,183 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestActionSetDefault(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description   string
+		action        Action
+		resource      Resource
+		expectedUri   string
+		expectedName  string
+		expectedVerb  string
+		expectedParam []string
+	}{
+		{
+			description: "method defaults to the action name on the self link",
+			action: Action{
+				Name: "rotateKey",
+			},
+			resource: Resource{
+				Name:    "CryptoKey",
+				BaseUrl: "{{key_ring}}/cryptoKeys",
+			},
+			expectedUri:   "{{key_ring}}/cryptoKeys/{{name}}:rotateKey",
+			expectedName:  "google_test_crypto_key_rotate_key",
+			expectedVerb:  "POST",
+			expectedParam: []string{"keyRing", "name"},
+		},
+		{
+			description: "project is not a parameter",
+			action: Action{
+				Name:   "restart",
+				Method: ":restartInstance",
+				Verb:   "PUT",
+			},
+			resource: Resource{
+				Name:     "Instance",
+				SelfLink: "projects/{{project}}/instances/{{instance}}",
+			},
+			expectedUri:   "projects/{{project}}/instances/{{instance}}:restartInstance",
+			expectedName:  "google_test_instance_restart",
+			expectedVerb:  "PUT",
+			expectedParam: []string{"instance"},
+		},
+		{
+			description: "url overrides method",
+			action: Action{
+				Name: "failover",
+				Url:  "projects/{{project}}/failover/{{name}}",
+			},
+			resource: Resource{
+				Name:    "Cluster",
+				BaseUrl: "projects/{{project}}/clusters",
+			},
+			expectedUri:   "projects/{{project}}/failover/{{name}}",
+			expectedName:  "google_test_cluster_failover",
+			expectedVerb:  "POST",
+			expectedParam: []string{"name"},
+		},
+		{
+			description: "legacy resource name is used as prefix",
+			action: Action{
+				Name: "export",
+			},
+			resource: Resource{
+				Name:       "Bucket",
+				LegacyName: "google_storage_bucket",
+				BaseUrl:    "b",
+			},
+			expectedUri:   "b/{{name}}:export",
+			expectedName:  "google_storage_bucket_export",
+			expectedVerb:  "POST",
+			expectedParam: []string{"name"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := tc.resource
+			r.Actions = []*Action{&tc.action}
+			r.SetDefault(&Product{Name: "Test"})
+
+			if got, want := tc.action.Uri(), tc.expectedUri; got != want {
+				t.Errorf("expected uri %q to be %q", got, want)
+			}
+			if got, want := tc.action.TerraformName(), tc.expectedName; got != want {
+				t.Errorf("expected name %q to be %q", got, want)
+			}
+
+			ar := tc.action.ActionResource
+			if got, want := ar.CreateVerb, tc.expectedVerb; got != want {
+				t.Errorf("expected verb %q to be %q", got, want)
+			}
+			if !ar.Immutable || !ar.ExcludeImport || !ar.ExcludeDelete {
+				t.Errorf("expected action resource to be immutable and excluded from import and delete")
+			}
+
+			var params []string
+			for _, p := range ar.Parameters {
+				if !p.Required || !p.UrlParamOnly {
+					t.Errorf("expected parameter %q to be a required url parameter", p.Name)
+				}
+				params = append(params, p.Name)
+			}
+			if got, want := params, tc.expectedParam; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected parameters %v to be %v", got, want)
+			}
+
+			if len(ar.VirtualFields) != 1 || ar.VirtualFields[0].Name != "triggers" {
+				t.Errorf("expected a single `triggers` virtual field, got %v", ar.VirtualFields)
+			}
+		})
+	}
+}
+
+func TestActionIncludeProjectForOperation(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		asyncUrl    string
+		actionAsync *Async
+		expected    bool
+	}{
+		{
+			description: "follows the waiter of the product with a project",
+			asyncUrl:    "projects/{{project}}/things",
+			actionAsync: &Async{Type: "OpAsync"},
+			expected:    true,
+		},
+		{
+			description: "follows the waiter of the product without a project",
+			asyncUrl:    "things",
+			actionAsync: &Async{Type: "OpAsync", OpAsync: OpAsync{IncludeProject: true}},
+			expected:    false,
+		},
+		{
+			description: "follows the action without a generated waiter",
+			actionAsync: &Async{Type: "OpAsync", OpAsync: OpAsync{IncludeProject: true}},
+			expected:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			a := &Action{Name: "restart", Async: tc.actionAsync}
+			r := &Resource{
+				Name:    "Instance",
+				BaseUrl: "projects/{{project}}/instances",
+				Actions: []*Action{a},
+			}
+			p := &Product{Name: "Test", Objects: []*Resource{r}}
+			if tc.asyncUrl != "" {
+				p.Objects = append([]*Resource{{
+					Name:         "Thing",
+					BaseUrl:      tc.asyncUrl,
+					AutogenAsync: true,
+					Async:        &Async{Type: "OpAsync"},
+				}}, p.Objects...)
+			}
+			for _, o := range p.Objects {
+				o.SetDefault(p)
+			}
+
+			if got := a.IncludeProjectForOperation(); got != tc.expected {
+				t.Errorf("expected IncludeProjectForOperation() to be %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
@@ -74,6 +74,13 @@ type OpAsync struct {
 	// If true, include project as an argument to OperationWaitTime.
 	// It is intended for resources that calculate project/region from a selflink field
 	IncludeProject bool `yaml:"include_project"`
+
+	// If true, the name of an operation that Terraform stopped waiting for,
+	// e.g. because of a timeout or an interrupt, is kept in the resource's
+	// private state. The resource isn't read until the operation finishes, and
+	// updates and deletes wait for it. The operation must be retrievable from
+	// its name alone.
+	Resumable bool `yaml:"resumable,omitempty"`
 }
 
 type OpAsyncOperation struct {
@@ -138,5 +145,10 @@ func (a *Async) Validate() {
 				log.Fatalf("`base_url` and `full_url` cannot be set at the same time in OpAsync operation.")
 			}
 		}
+		if a.Resumable && a.IncludeProject {
+			log.Fatalf("`resumable` cannot be set with `include_project` in OpAsync.")
+		}
+	} else if a.Resumable {
+		log.Fatalf("`resumable` is only supported for OpAsync.")
 	}
 }
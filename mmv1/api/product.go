@@ -118,6 +118,24 @@ func (p *Product) Validate() {
 	if p.Async != nil {
 		p.Async.Validate()
 	}
+
+	p.validateBatchers()
+}
+
+// Resources sharing a batcher share its configuration, so they must agree on
+// its defaults.
+func (p *Product) validateBatchers() {
+	sendAfter := make(map[string]string)
+	for _, r := range p.Objects {
+		if r.Batching == nil {
+			continue
+		}
+		name := r.BatcherName()
+		if v, ok := sendAfter[name]; ok && v != r.Batching.SendAfter {
+			log.Fatalf("Conflicting `send_after` values %q and %q for batcher %s in product %s", v, r.Batching.SendAfter, name, p.Name)
+		}
+		sendAfter[name] = r.Batching.SendAfter
+	}
 }
 
 // ====================
@@ -216,6 +216,19 @@ type Resource struct {
 	// resource.
 	Mutex string `yaml:"mutex,omitempty"`
 
+	// [Optional] Named request batcher used by the resource's custom code to
+	// combine concurrent requests, see resource.Batching.
+	Batching *resource.Batching `yaml:"batching,omitempty"`
+
+	// [Optional] Sends the resource's etag or fingerprint with generated update
+	// and delete requests, so that they fail rather than overwrite concurrent
+	// changes. See resource.ConcurrencyControl.
+	ConcurrencyControl *resource.ConcurrencyControl `yaml:"concurrency_control,omitempty"`
+
+	// Custom methods on the resource, such as `:restart` or `:rotateKey`.
+	// Each action is generated as a separate trigger-style resource.
+	Actions []*Action `yaml:"actions,omitempty"`
+
 	// Examples in documentation. Backed by generated tests, and have
 	// corresponding OiCS walkthroughs.
 	Examples []resource.Examples
@@ -257,6 +270,12 @@ type Resource struct {
 
 	StateUpgraders bool `yaml:"state_upgraders,omitempty"`
 
+	// Declarative state migrations, one per schema version below
+	// schema_version. When set, the state upgraders are generated instead of
+	// being read from mmv1/templates/terraform/state_migrations/, and
+	// schema_version defaults to the version after the last migration.
+	StateMigrations []*StateMigration `yaml:"state_migrations,omitempty"`
+
 	// Do not apply the default attribution label
 	ExcludeAttributionLabel bool `yaml:"exclude_attribution_label,omitempty"`
 
@@ -334,6 +353,9 @@ type Resource struct {
 
 	ProductMetadata *Product `yaml:"-"`
 
+	// The action this resource is generated for, if it's an action's resource.
+	ActionMetadata *Action `yaml:"-"`
+
 	// The version name provided by the user through CI
 	TargetVersionName string `yaml:"-"`
 
@@ -407,13 +429,51 @@ func (r *Resource) SetDefault(product *Product) {
 	for _, vf := range r.VirtualFields {
 		vf.SetDefault(r)
 	}
+	r.setRenamedPropertyDefaults()
 	if r.IamPolicy != nil && r.IamPolicy.MinVersion == "" {
 		r.IamPolicy.MinVersion = r.MinVersion
 	}
+	if r.IamPolicy != nil {
+		for _, k := range r.IamPolicy.PolicyKinds {
+			if k.MinVersion == "" {
+				k.MinVersion = r.IamPolicy.MinVersion
+			}
+		}
+	}
 	if r.Timeouts == nil {
 		r.Timeouts = NewTimeouts()
 	}
+	if len(r.StateMigrations) > 0 {
+		r.StateUpgraders = true
+		for _, m := range r.StateMigrations {
+			if m.Version >= r.SchemaVersion {
+				r.SchemaVersion = m.Version + 1
+			}
+		}
+	}
+	for _, a := range r.Actions {
+		a.SetDefault(r)
+	}
+}
 
+// Adds the deprecated aliases of renamed properties to the exactly_one_of and
+// at_least_one_of groups the properties are in, as setting the alias is
+// equivalent to setting the property.
+func (r *Resource) setRenamedPropertyDefaults() {
+	for _, renamed := range r.AllProperties() {
+		if renamed.RenamedFrom == "" {
+			continue
+		}
+		name, alias := google.Underscore(renamed.Name), google.Underscore(renamed.RenamedFrom)
+		for _, p := range r.AllProperties() {
+			if slices.Contains(p.ExactlyOneOf, name) && !slices.Contains(p.ExactlyOneOf, alias) {
+				p.ExactlyOneOf = append(p.ExactlyOneOf, alias)
+			}
+			if slices.Contains(p.AtLeastOneOf, name) && !slices.Contains(p.AtLeastOneOf, alias) {
+				p.AtLeastOneOf = append(p.AtLeastOneOf, alias)
+			}
+		}
+	}
 }
 
 func (r *Resource) Validate() {
@@ -477,6 +537,32 @@ func (r *Resource) Validate() {
 		r.NestedQuery.Validate(r.Name)
 	}
 
+	if r.Batching != nil {
+		r.Batching.Validate(r.Name)
+		// A lock held for the whole request would keep requests from being
+		// combined, and a precondition only applies to one of them.
+		if r.Mutex != "" {
+			log.Fatalf("`batching` is not supported with `mutex` in resource %s", r.Name)
+		}
+		if r.ConcurrencyControl != nil {
+			log.Fatalf("`batching` is not supported with `concurrency_control` in resource %s", r.Name)
+		}
+	}
+
+	if r.ConcurrencyControl != nil {
+		r.ConcurrencyControl.Validate(r.Name)
+		if r.NestedQuery != nil {
+			log.Fatalf("`concurrency_control` is not supported with `nested_query` in resource %s", r.Name)
+		}
+		p := r.ConcurrencyControlProperty()
+		if p == nil {
+			log.Fatalf("Missing top-level property %q for `concurrency_control` in resource %s", r.ConcurrencyControl.Field, r.Name)
+		}
+		if !p.IsA("String") && !p.IsA("Fingerprint") {
+			log.Fatalf("Property %q for `concurrency_control` in resource %s must be a String or Fingerprint", p.Name, r.Name)
+		}
+	}
+
 	for _, example := range r.Examples {
 		example.Validate(r.Name)
 	}
@@ -484,6 +570,29 @@ func (r *Resource) Validate() {
 	if r.Async != nil {
 		r.Async.Validate()
 	}
+
+	for _, a := range r.Actions {
+		a.Validate(r.Name)
+	}
+
+	if len(r.StateMigrations) > 0 {
+		for _, v := range r.StateUpgradersCount() {
+			if r.StateMigration(v) == nil {
+				log.Fatalf("Missing state migration from version %d in resource %s", v, r.Name)
+			}
+		}
+		versions := make(map[int]bool)
+		for _, m := range r.StateMigrations {
+			if versions[m.Version] {
+				log.Fatalf("Duplicate state migration from version %d in resource %s", m.Version, r.Name)
+			}
+			versions[m.Version] = true
+			if m.Version < r.StateUpgradeBaseSchemaVersion || m.Version >= r.SchemaVersion {
+				log.Fatalf("State migration from version %d is outside of the upgraded schema versions in resource %s", m.Version, r.Name)
+			}
+			m.Validate(*r)
+		}
+	}
 }
 
 // ====================
@@ -557,6 +666,33 @@ func (r Resource) RequiredProperties() []*Type {
 	})
 }
 
+// Returns the user-facing properties that were renamed, and so also have a
+// deprecated alias under their previous name
+func (r Resource) RenamedProperties() []*Type {
+	return google.Select(r.AllUserProperties(), func(p *Type) bool {
+		return p.RenamedFrom != ""
+	})
+}
+
+// Returns the renamed properties that were required, which now require
+// either the property or its deprecated alias to be set
+func (r Resource) RequiredRenamedProperties() []*Type {
+	return google.Select(r.RenamedProperties(), func(p *Type) bool {
+		return p.Required
+	})
+}
+
+// Returns the deprecated alias of the renamed property with the given
+// Terraform name, or "" if it wasn't renamed
+func (r Resource) RenamedFieldAlias(name string) string {
+	for _, p := range r.RenamedProperties() {
+		if google.Underscore(p.Name) == name {
+			return google.Underscore(p.RenamedFrom)
+		}
+	}
+	return ""
+}
+
 func (r Resource) AllNestedProperties(props []*Type) []*Type {
 	nested := props
 	for _, prop := range props {
@@ -688,6 +824,13 @@ func (r Resource) GetAsync() *Async {
 	return r.ProductMetadata.Async
 }
 
+// ResumableOperations returns whether the resource keeps operations it stopped
+// waiting for in its private state, to wait for them later.
+func (r Resource) ResumableOperations() bool {
+	a := r.GetAsync()
+	return a != nil && a.IsA("OpAsync") && a.Resumable
+}
+
 // Return the resource-specific identity properties, or a best guess of the
 // `name` value for the resource.
 func (r Resource) GetIdentity() []*Type {
@@ -890,6 +1033,10 @@ func (r *Resource) ExcludeIfNotInVersion(version *product.Version) {
 			p.ExcludeIfNotInVersion(version)
 		}
 	}
+
+	for _, a := range r.Actions {
+		a.ActionResource.ExcludeIfNotInVersion(version)
+	}
 }
 
 // ====================
@@ -1398,6 +1545,52 @@ func (r Resource) IamTerraformName() string {
 	return fmt.Sprintf("%s_iam", r.TerraformName())
 }
 
+// Returns the additional IAM policy kinds available at the target version.
+func (r Resource) IamPolicyKinds() []*resource.IamPolicyKind {
+	if r.IamPolicy == nil {
+		return nil
+	}
+	return google.Reject(r.IamPolicy.PolicyKinds, func(k *resource.IamPolicyKind) bool {
+		return k.MinVersion != "" && slices.Index(product.ORDER, r.TargetVersionName) < slices.Index(product.ORDER, k.MinVersion)
+	})
+}
+
+// Returns the name the resource's batcher is registered by in the provider,
+// prefixed by the product so that batcher names are unique across products.
+func (r Resource) BatcherName() string {
+	if r.Batching == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s_%s", google.Underscore(r.ProductMetadata.Name), r.Batching.Batcher)
+}
+
+// Returns the property holding the etag or fingerprint sent by
+// `concurrency_control`, or nil if there is none.
+func (r Resource) ConcurrencyControlProperty() *Type {
+	if r.ConcurrencyControl == nil {
+		return nil
+	}
+	for _, p := range r.AllUserProperties() {
+		if p.Name == r.ConcurrencyControl.Field {
+			return p
+		}
+	}
+	return nil
+}
+
+// IamPolicyKindResource pairs a resource with one of its additional IAM policy
+// kinds, and is used to render templates generated once per kind.
+type IamPolicyKindResource struct {
+	*Resource
+	Kind *resource.IamPolicyKind
+}
+
+// The Terraform name of the resource and data source for the policy kind,
+// e.g. `google_pubsub_topic_iam_deny_policy`
+func (r IamPolicyKindResource) KindTerraformName() string {
+	return fmt.Sprintf("%s_%s_policy", r.IamTerraformName(), r.Kind.Name)
+}
+
 func (r Resource) IamSelfLinkIdentifiers() []string {
 	var selfLink string
 	if r.IamPolicy != nil {
@@ -1765,10 +1958,15 @@ func (r Resource) CustomUpdatePropertiesByKey(properties []*Type, updateUrl stri
 	})
 }
 
+// Returns the Terraform names of the properties, followed by the deprecated
+// alias of each renamed property, as changing the alias changes the property.
 func (r Resource) PropertyNamesToStrings(properties []*Type) []string {
 	var propertyNames []string
 	for _, prop := range properties {
 		propertyNames = append(propertyNames, google.Underscore(prop.Name))
+		if prop.RenamedFrom != "" {
+			propertyNames = append(propertyNames, google.Underscore(prop.RenamedFrom))
+		}
 	}
 	return propertyNames
 }
@@ -0,0 +1,64 @@
+// Copyright 2024 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"log"
+	"regexp"
+	"time"
+)
+
+var batcherNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Declares a named request batcher used by a resource, for APIs where
+// concurrent requests to a shared parent contend with each other. The
+// create, update and delete requests of the resource are sent through the
+// batcher, which combines the requests sent to the same URL with the same
+// method into one. Batchers are registered per product: resources of a
+// product declaring the same batcher share it at runtime, and custom code
+// can send requests through it with config.RequestBatcher(name).
+type Batching struct {
+	// Name of the batcher within the product, e.g. `firewall`. The batcher
+	// is registered in the provider as `<product>_<batcher>`, which is also
+	// the name users configure it by in the provider `batching` block.
+	Batcher string `yaml:"batcher"`
+
+	// Default time to wait for more requests before sending a batch, as a
+	// duration string such as "3s". A `send_after` set by users at the
+	// provider level takes precedence, and the provider-level default is
+	// used if unset.
+	SendAfter string `yaml:"send_after,omitempty"`
+
+	// Top-level list field of the request bodies, e.g. `resourcePolicies`.
+	// Combined requests concatenate the items of this field, and keep the
+	// other fields of the first request.
+	CombineField string `yaml:"combine_field"`
+}
+
+func (b *Batching) Validate(rName string) {
+	if b.Batcher == "" {
+		log.Fatalf("Missing `batcher` for `batching` in resource %s", rName)
+	}
+	if !batcherNameRegex.MatchString(b.Batcher) {
+		log.Fatalf("Invalid `batcher` %q for `batching` in resource %s: must be lowercase snake case", b.Batcher, rName)
+	}
+	if b.CombineField == "" {
+		log.Fatalf("Missing `combine_field` for `batching` in resource %s", rName)
+	}
+	if b.SendAfter != "" {
+		if d, err := time.ParseDuration(b.SendAfter); err != nil || d < 0 {
+			log.Fatalf("Invalid `send_after` %q for `batching` in resource %s: must be a non-negative duration", b.SendAfter, rName)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"log"
+	"slices"
+)
+
+// Makes a resource's generated update and delete requests conditional on the
+// resource not having changed since Terraform last read it, for APIs that
+// support optimistic concurrency control with an etag or fingerprint.
+//
+// The last read value is sent with each request. If the API rejects it as
+// stale with a 412, or a 409 with an ABORTED status, the resource is read
+// again and the request rebased on it and retried, up to `max_retries` times.
+// Requests fail with a conflict error instead of overwriting concurrent
+// changes to the fields they change.
+type ConcurrencyControl struct {
+	// Name of the top-level property holding the etag or fingerprint, e.g.
+	// `etag` or `fingerprint`. Its API name is used in requests.
+	Field string `yaml:"field"`
+
+	// Where the value is sent with update requests: `body` (the default),
+	// `query` or `header` (as If-Match).
+	UpdatePrecondition string `yaml:"update_precondition,omitempty"`
+
+	// Where the value is sent with delete requests: `query` or `header` (as
+	// If-Match). Delete requests are unconditional if unset.
+	DeletePrecondition string `yaml:"delete_precondition,omitempty"`
+
+	// Number of times a conflicting request is retried. Defaults to 3.
+	MaxRetries int `yaml:"max_retries"`
+}
+
+func (c *ConcurrencyControl) UnmarshalYAML(unmarshal func(any) error) error {
+	c.UpdatePrecondition = "body"
+	c.MaxRetries = 3
+
+	type concurrencyControlAlias ConcurrencyControl
+	aliasObj := (*concurrencyControlAlias)(c)
+
+	err := unmarshal(aliasObj)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *ConcurrencyControl) Validate(rName string) {
+	if c.Field == "" {
+		log.Fatalf("Missing `field` for `concurrency_control` in resource %s", rName)
+	}
+	if allowed := []string{"body", "query", "header"}; !slices.Contains(allowed, c.UpdatePrecondition) {
+		log.Fatalf("Invalid `update_precondition` %q for `concurrency_control` in resource %s: should be one of %#v", c.UpdatePrecondition, rName, allowed)
+	}
+	if allowed := []string{"", "query", "header"}; !slices.Contains(allowed, c.DeletePrecondition) {
+		log.Fatalf("Invalid `delete_precondition` %q for `concurrency_control` in resource %s: should be one of %#v", c.DeletePrecondition, rName, allowed)
+	}
+	if c.MaxRetries < 0 {
+		log.Fatalf("Invalid `max_retries` %d for `concurrency_control` in resource %s: must not be negative", c.MaxRetries, rName)
+	}
+}
@@ -15,7 +15,11 @@ package resource
 
 import (
 	"log"
+	"regexp"
 	"slices"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
 )
 
 // Information about the IAM policy for this resource
@@ -114,6 +118,55 @@ type IamPolicy struct {
 	// [Optional] Check to see if zone value should be replaced with GOOGLE_ZONE in iam tests
 	// Defaults to true
 	SubstituteZoneValue bool `yaml:"substitute_zone_value"`
+
+	// [Optional] Policy kinds attached to the resource in addition to its allow
+	// policy, such as deny policies. Each kind generates its own
+	// `_iam_<name>_policy` resource and data source.
+	PolicyKinds []*IamPolicyKind `yaml:"policy_kinds"`
+}
+
+// A kind of policy other than the allow policy (e.g. a deny policy) that is
+// attached to the resource through the same fetch/set shape, but with its own
+// methods and wire format. Policies of these kinds are managed as raw JSON.
+type IamPolicyKind struct {
+	// Name of the policy kind in snake_case, e.g. `deny`. Used to name the
+	// generated resource and data source, e.g. `google_pubsub_topic_iam_deny_policy`
+	Name string
+
+	// Some resources allow retrieving the policy with GET requests,
+	// others expect POST requests
+	FetchVerb string `yaml:"fetch_verb"`
+
+	// Last part of URL for fetching the policy, e.g. `getDenyPolicy`
+	FetchMethod string `yaml:"fetch_method"`
+
+	// Some resources allow setting the policy with POST requests,
+	// others expect PUT or PATCH requests
+	SetVerb string `yaml:"set_verb"`
+
+	// Last part of URL for setting the policy, e.g. `setDenyPolicy`
+	SetMethod string `yaml:"set_method"`
+
+	// [Optional] Field of the fetch response that contains the policy.
+	// If unset, the whole response is the policy.
+	ResponseField string `yaml:"response_field"`
+
+	// [Optional] Field of the set request body the policy is sent in.
+	// If unset, the policy is sent as the request body.
+	RequestField string `yaml:"request_field"`
+
+	// [Optional] Top-level fields of the policy managed through `policy_data`.
+	// Any other field returned by the API (such as output-only metadata) is
+	// ignored. If unset, every field is managed except `etag` and common
+	// output-only fields such as `name`, `uid` and `createTime`.
+	PolicyFields []string `yaml:"policy_fields"`
+
+	// JSON policy used as `policy_data` in generated tests and documentation
+	TestPolicyData string `yaml:"test_policy_data"`
+
+	// [Optional] Min version to make the resources for this kind available at
+	// If unset, defaults to the `min_version` of the IAM policy
+	MinVersion string `yaml:"min_version"`
 }
 
 func (p *IamPolicy) UnmarshalYAML(unmarshal func(any) error) error {
@@ -154,4 +207,65 @@ func (p *IamPolicy) Validate(rName string) {
 	if p.IamConditionsRequestType != "" && !slices.Contains(allowed, p.IamConditionsRequestType) {
 		log.Fatalf("Value on `iam_conditions_request_type` should be one of %#v in resource %s", allowed, rName)
 	}
+
+	var kindNames []string
+	for _, k := range p.PolicyKinds {
+		if slices.Contains(kindNames, k.Name) {
+			log.Fatalf("Duplicate `policy_kinds` name %s in resource %s", k.Name, rName)
+		}
+		kindNames = append(kindNames, k.Name)
+		k.Validate(rName)
+	}
+}
+
+func (k *IamPolicyKind) UnmarshalYAML(unmarshal func(any) error) error {
+	k.FetchVerb = "GET"
+	k.SetVerb = "POST"
+
+	type iamPolicyKindAlias IamPolicyKind
+	aliasObj := (*iamPolicyKindAlias)(k)
+
+	return unmarshal(aliasObj)
+}
+
+var iamPolicyKindNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+func (k *IamPolicyKind) Validate(rName string) {
+	if !iamPolicyKindNameRegex.MatchString(k.Name) {
+		log.Fatalf("Missing or invalid `name` %q on `policy_kinds` in resource %s, expected snake_case", k.Name, rName)
+	}
+	// The allow policy is already generated as `_iam_policy`, and `_iam_allow_policy`
+	// would only be a second way to manage the same policy.
+	if k.Name == "allow" {
+		log.Fatalf("Policy kind `allow` is reserved in resource %s", rName)
+	}
+	if k.FetchMethod == "" {
+		log.Fatalf("Missing `fetch_method` for policy kind %s in resource %s", k.Name, rName)
+	}
+	if k.SetMethod == "" {
+		log.Fatalf("Missing `set_method` for policy kind %s in resource %s", k.Name, rName)
+	}
+	if k.TestPolicyData == "" {
+		log.Fatalf("Missing `test_policy_data` for policy kind %s in resource %s", k.Name, rName)
+	}
+
+	allowed := []string{"GET", "POST"}
+	if !slices.Contains(allowed, k.FetchVerb) {
+		log.Fatalf("Value on `fetch_verb` for policy kind %s should be one of %#v in resource %s", k.Name, allowed, rName)
+	}
+
+	allowed = []string{"POST", "PUT", "PATCH"}
+	if !slices.Contains(allowed, k.SetVerb) {
+		log.Fatalf("Value on `set_verb` for policy kind %s should be one of %#v in resource %s", k.Name, allowed, rName)
+	}
+}
+
+// Name of the policy kind as used in Go identifiers, e.g. `Deny`
+func (k IamPolicyKind) CamelName() string {
+	return google.Camelize(k.Name, "upper")
+}
+
+// Name of the policy kind as used in documentation and messages, e.g. `principal access boundary`
+func (k IamPolicyKind) DisplayName() string {
+	return strings.ReplaceAll(k.Name, "_", " ")
 }
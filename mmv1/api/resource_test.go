@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
 )
 
 func TestResourceMinVersionObj(t *testing.T) {
@@ -503,3 +504,189 @@ func TestHasPostCreateComputedFields(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceIamPolicyKinds(t *testing.T) {
+	t.Parallel()
+
+	newResource := func(targetVersion string) Resource {
+		r := Resource{
+			Name:              "Thing",
+			BaseUrl:           "projects/{{project}}/things",
+			TargetVersionName: targetVersion,
+			IamPolicy: &resource.IamPolicy{
+				PolicyKinds: []*resource.IamPolicyKind{
+					{Name: "deny"},
+					{Name: "principal_access_boundary", MinVersion: "beta"},
+				},
+			},
+		}
+		r.SetDefault(&Product{Name: "Test"})
+		return r
+	}
+
+	cases := []struct {
+		name    string
+		version string
+		want    []string
+	}{
+		{
+			name:    "beta kinds are excluded from ga",
+			version: "ga",
+			want:    []string{"google_test_thing_iam_deny_policy"},
+		},
+		{
+			name:    "all kinds are included in beta",
+			version: "beta",
+			want:    []string{"google_test_thing_iam_deny_policy", "google_test_thing_iam_principal_access_boundary_policy"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newResource(tc.version)
+			var got []string
+			for _, k := range r.IamPolicyKinds() {
+				got = append(got, IamPolicyKindResource{Resource: &r, Kind: k}.KindTerraformName())
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("IamPolicyKinds(%q) returned unexpected value. got %v; want %v.", tc.version, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResourceBatcherName(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		batching *resource.Batching
+		want     string
+	}{
+		{
+			name: "no batching",
+			want: "",
+		},
+		{
+			name:     "batcher is prefixed by the product",
+			batching: &resource.Batching{Batcher: "firewall"},
+			want:     "compute_firewall",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := Resource{
+				Name:     "Firewall",
+				BaseUrl:  "projects/{{project}}/global/firewalls",
+				Batching: tc.batching,
+			}
+			r.SetDefault(&Product{Name: "Compute"})
+			if got := r.BatcherName(); got != tc.want {
+				t.Errorf("BatcherName() returned unexpected value. got %q; want %q.", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResourceResumableOperations(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name         string
+		productAsync *Async
+		async        *Async
+		want         bool
+	}{
+		{
+			name: "no async",
+			want: false,
+		},
+		{
+			name:  "not resumable",
+			async: &Async{Type: "OpAsync"},
+			want:  false,
+		},
+		{
+			name:  "resumable",
+			async: &Async{Type: "OpAsync", OpAsync: OpAsync{Resumable: true}},
+			want:  true,
+		},
+		{
+			name:         "resumable from product",
+			productAsync: &Async{Type: "OpAsync", OpAsync: OpAsync{Resumable: true}},
+			want:         true,
+		},
+		{
+			name:  "poll async",
+			async: &Async{Type: "PollAsync", OpAsync: OpAsync{Resumable: true}},
+			want:  false,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := Resource{
+				Async:           tc.async,
+				ProductMetadata: &Product{Async: tc.productAsync},
+			}
+			if got := r.ResumableOperations(); got != tc.want {
+				t.Errorf("ResumableOperations() returned unexpected value. got %v; want %v.", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResourceConcurrencyControlProperty(t *testing.T) {
+	t.Parallel()
+
+	etag := &Type{Name: "etag", Type: "String", Output: true}
+	cases := []struct {
+		name string
+		cc   *resource.ConcurrencyControl
+		want *Type
+	}{
+		{
+			name: "no concurrency control",
+			want: nil,
+		},
+		{
+			name: "property",
+			cc:   &resource.ConcurrencyControl{Field: "etag"},
+			want: etag,
+		},
+		{
+			name: "missing property",
+			cc:   &resource.ConcurrencyControl{Field: "fingerprint"},
+			want: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := Resource{
+				Properties:         []*Type{{Name: "name", Type: "String"}, etag},
+				ConcurrencyControl: tc.cc,
+			}
+			if got := r.ConcurrencyControlProperty(); got != tc.want {
+				t.Errorf("ConcurrencyControlProperty() returned unexpected value. got %v; want %v.", got, tc.want)
+			}
+		})
+	}
+}
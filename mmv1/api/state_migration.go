@@ -0,0 +1,349 @@
+// Copyright 2025 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+)
+
+// Describes how the state of a resource changed between a schema version
+// and the next one. The generator produces the state upgrader, a snapshot of
+// the previous schema and unit tests from it, so no template under
+// templates/terraform/state_migrations/ is needed.
+//
+// Field paths are dot-separated Terraform field names as they are in the
+// newer schema version, e.g. `settings.ip_configuration`.
+type StateMigration struct {
+	// [Required] The schema version this migration upgrades from. The state
+	// is upgraded to `version + 1`.
+	Version int
+
+	// [Optional] Fields that were renamed. `from` is the path of the field in
+	// the previous version, and `to` is its new name in the same block.
+	RenamedFields []StateMigrationRename `yaml:"renamed_fields,omitempty"`
+
+	// [Optional] Fields that changed from a single value to a list of values.
+	ScalarToListFields []string `yaml:"scalar_to_list_fields,omitempty"`
+
+	// [Optional] NestedObject fields that are now flattened into their parent
+	// with `flatten_object`.
+	FlattenedObjects []string `yaml:"flattened_objects,omitempty"`
+
+	// [Optional] Set if the id format of the resource changed. The id is
+	// rebuilt from the current `id_format` using values in the state.
+	IdFormatChanged bool `yaml:"id_format_changed,omitempty"`
+
+	// [Optional] Old state fixtures run through the upgrader by generated
+	// unit tests.
+	Tests []StateMigrationTest `yaml:"tests,omitempty"`
+}
+
+type StateMigrationRename struct {
+	From string
+	To   string
+}
+
+// Returns the path of the renamed field in the newer schema version.
+func (rename StateMigrationRename) toPath() string {
+	parent, _ := splitFieldPath(rename.From)
+	if parent == "" {
+		return rename.To
+	}
+	return fmt.Sprintf("%s.%s", parent, rename.To)
+}
+
+type StateMigrationTest struct {
+	Name string
+
+	OldState map[string]any `yaml:"old_state"`
+
+	NewState map[string]any `yaml:"new_state"`
+}
+
+// A field of a resource schema, reduced to what's needed to describe the
+// shape of the state in a previous schema version.
+type StateMigrationField struct {
+	Name string
+
+	// The schema type, e.g. `schema.TypeList`.
+	Type string
+
+	// The type of primitive elements for lists, sets and maps.
+	ElemType string
+
+	// The fields of nested blocks.
+	Fields []*StateMigrationField
+
+	// The name of the flattened NestedObject this field comes from, if any.
+	flattenedFrom string
+}
+
+// Validates the migration against the schema at `version + 1`, as built by
+// undoing the later migrations on the current schema.
+func (m StateMigration) Validate(r Resource) {
+	fields, err := r.StateMigrationSchema(m.Version + 1)
+	if err != nil {
+		log.Fatalf("%s in resource %s", err, r.Name)
+	}
+
+	for _, rename := range m.RenamedFields {
+		if rename.From == "" || rename.To == "" {
+			log.Fatalf("Missing `from` or `to` in renamed_fields of state migration from version %d in resource %s", m.Version, r.Name)
+		}
+		if findStateMigrationField(fields, rename.toPath()) == nil {
+			log.Fatalf("Field %s in renamed_fields of state migration from version %d does not exist in resource %s", rename.toPath(), m.Version, r.Name)
+		}
+	}
+
+	for _, path := range m.ScalarToListFields {
+		f := findStateMigrationField(fields, path)
+		if f == nil || f.ElemType == "" || f.Type == "schema.TypeMap" {
+			log.Fatalf("Field %s in scalar_to_list_fields of state migration from version %d is not a list of primitive values in resource %s", path, m.Version, r.Name)
+		}
+	}
+
+	for _, path := range m.FlattenedObjects {
+		parent, name := splitFieldPath(path)
+		siblings := fields
+		if parent != "" {
+			f := findStateMigrationField(fields, parent)
+			if f == nil {
+				log.Fatalf("Field %s in flattened_objects of state migration from version %d does not exist in resource %s", path, m.Version, r.Name)
+			}
+			siblings = f.Fields
+		}
+		if !slices.ContainsFunc(siblings, func(f *StateMigrationField) bool { return f.flattenedFrom == name }) {
+			log.Fatalf("Field %s in flattened_objects of state migration from version %d does not use flatten_object in resource %s", path, m.Version, r.Name)
+		}
+	}
+
+	for _, t := range m.Tests {
+		if t.Name == "" {
+			log.Fatalf("Missing `name` for test of state migration from version %d in resource %s", m.Version, r.Name)
+		}
+	}
+}
+
+// Returns the Go name of a state migration test, e.g. `RenamedField`.
+func (t StateMigrationTest) TestName() string {
+	return google.Camelize(t.Name, "upper")
+}
+
+func (t StateMigrationTest) OldStateJson() string {
+	return stateFixtureJson(t.OldState)
+}
+
+func (t StateMigrationTest) NewStateJson() string {
+	return stateFixtureJson(t.NewState)
+}
+
+// Returns the state migration that upgrades from the given version, or nil.
+func (r Resource) StateMigration(version int) *StateMigration {
+	for _, m := range r.StateMigrations {
+		if m.Version == version {
+			return m
+		}
+	}
+	return nil
+}
+
+// Returns the schema of the resource at the given schema version, built by
+// undoing the declared state migrations on the current schema. Each migration
+// is undone on the schema produced by the later ones, and fails if a field it
+// names doesn't exist there.
+func (r Resource) StateMigrationSchema(version int) ([]*StateMigrationField, error) {
+	fields := r.stateMigrationFields()
+
+	migrations := google.Select(r.StateMigrations, func(m *StateMigration) bool {
+		return m.Version >= version
+	})
+	slices.SortFunc(migrations, func(a, b *StateMigration) int {
+		return b.Version - a.Version
+	})
+
+	for _, m := range migrations {
+		for _, path := range m.ScalarToListFields {
+			f := findStateMigrationField(fields, path)
+			if f == nil {
+				return nil, fmt.Errorf("Field %s in scalar_to_list_fields of state migration from version %d does not exist in schema version %d", path, m.Version, m.Version+1)
+			}
+			f.Type = f.ElemType
+			f.ElemType = ""
+		}
+		for _, rename := range m.RenamedFields {
+			f := findStateMigrationField(fields, rename.toPath())
+			if f == nil {
+				return nil, fmt.Errorf("Field %s in renamed_fields of state migration from version %d does not exist in schema version %d", rename.toPath(), m.Version, m.Version+1)
+			}
+			_, f.Name = splitFieldPath(rename.From)
+		}
+		for _, path := range m.FlattenedObjects {
+			parent, name := splitFieldPath(path)
+			if parent == "" {
+				fields = nestFlattenedFields(fields, name)
+				continue
+			}
+			f := findStateMigrationField(fields, parent)
+			if f == nil {
+				return nil, fmt.Errorf("Field %s in flattened_objects of state migration from version %d does not exist in schema version %d", path, m.Version, m.Version+1)
+			}
+			f.Fields = nestFlattenedFields(f.Fields, name)
+		}
+	}
+
+	return fields, nil
+}
+
+func (r Resource) stateMigrationFields() []*StateMigrationField {
+	var fields []*StateMigrationField
+	for _, p := range r.OrderProperties(r.AllUserProperties()) {
+		fields = append(fields, newStateMigrationFields(p)...)
+	}
+	for _, p := range r.VirtualFields {
+		fields = append(fields, newStateMigrationFields(p)...)
+	}
+	if r.HasProject() {
+		fields = append(fields, &StateMigrationField{Name: "project", Type: "schema.TypeString"})
+	}
+	if r.HasSelfLink {
+		fields = append(fields, &StateMigrationField{Name: "self_link", Type: "schema.TypeString"})
+	}
+	return fields
+}
+
+func newStateMigrationFields(t *Type) []*StateMigrationField {
+	if t.FlattenObject {
+		var fields []*StateMigrationField
+		for _, p := range t.UserProperties() {
+			for _, f := range newStateMigrationFields(p) {
+				if f.flattenedFrom == "" {
+					f.flattenedFrom = google.Underscore(t.Name)
+				}
+				fields = append(fields, f)
+			}
+		}
+		return fields
+	}
+
+	f := &StateMigrationField{
+		Name: google.Underscore(t.Name),
+		Type: t.TFType(t.Type),
+	}
+
+	switch {
+	case t.IsA("NestedObject"):
+		f.Fields = nestedStateMigrationFields(t.UserProperties())
+	case t.IsA("Array"):
+		if t.IsSet {
+			f.Type = "schema.TypeSet"
+		}
+		if t.ItemType.IsA("NestedObject") {
+			f.Fields = nestedStateMigrationFields(t.ItemType.UserProperties())
+		} else {
+			f.ElemType = t.TFType(t.ItemType.Type)
+		}
+	case t.IsA("Map"):
+		f.Fields = append([]*StateMigrationField{{Name: t.KeyName, Type: "schema.TypeString"}}, nestedStateMigrationFields(t.ValueType.UserProperties())...)
+	case strings.HasPrefix(t.Type, "KeyValue"):
+		f.ElemType = "schema.TypeString"
+	}
+
+	return []*StateMigrationField{f}
+}
+
+func nestedStateMigrationFields(props []*Type) []*StateMigrationField {
+	var fields []*StateMigrationField
+	for _, p := range props {
+		fields = append(fields, newStateMigrationFields(p)...)
+	}
+	return fields
+}
+
+// Moves the fields that were flattened from the named NestedObject back into
+// a nested block.
+func nestFlattenedFields(fields []*StateMigrationField, name string) []*StateMigrationField {
+	nested := &StateMigrationField{Name: name, Type: "schema.TypeList"}
+	var rest []*StateMigrationField
+	for _, f := range fields {
+		if f.flattenedFrom == name {
+			f.flattenedFrom = ""
+			nested.Fields = append(nested.Fields, f)
+			continue
+		}
+		rest = append(rest, f)
+	}
+	return append(rest, nested)
+}
+
+func findStateMigrationField(fields []*StateMigrationField, path string) *StateMigrationField {
+	parts := strings.Split(path, ".")
+	for _, f := range fields {
+		if f.Name != parts[0] {
+			continue
+		}
+		if len(parts) == 1 {
+			return f
+		}
+		return findStateMigrationField(f.Fields, strings.Join(parts[1:], "."))
+	}
+	return nil
+}
+
+func splitFieldPath(path string) (string, string) {
+	i := strings.LastIndex(path, ".")
+	if i < 0 {
+		return "", path
+	}
+	return path[:i], path[i+1:]
+}
+
+// Converts a fixture read from YAML into JSON, as yaml.v2 decodes nested
+// maps with interface{} keys.
+func stateFixtureJson(state map[string]any) string {
+	b, err := json.Marshal(jsonCompatible(state))
+	if err != nil {
+		log.Fatalf("Error converting state fixture to JSON: %s", err)
+	}
+	return string(b)
+}
+
+func jsonCompatible(v any) any {
+	switch v := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[fmt.Sprintf("%v", k)] = jsonCompatible(e)
+		}
+		return m
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = jsonCompatible(e)
+		}
+		return m
+	case []any:
+		l := make([]any, len(v))
+		for i, e := range v {
+			l[i] = jsonCompatible(e)
+		}
+		return l
+	}
+	return v
+}
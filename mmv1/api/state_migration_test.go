@@ -0,0 +1,184 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResourceStateMigrationSchema(t *testing.T) {
+	t.Parallel()
+
+	newResource := func() Resource {
+		r := Resource{
+			Name:    "Thing",
+			BaseUrl: "projects/{{project}}/things",
+			Properties: []*Type{
+				{Name: "newName", Type: "String"},
+				{Name: "hosts", Type: "Array", ItemType: &Type{Type: "String"}},
+				{
+					Name:          "settings",
+					Type:          "NestedObject",
+					FlattenObject: true,
+					Properties: []*Type{
+						{Name: "size", Type: "Integer"},
+					},
+				},
+			},
+			StateMigrations: []*StateMigration{
+				{
+					Version: 0,
+					RenamedFields: []StateMigrationRename{
+						{From: "old_name", To: "new_name"},
+					},
+				},
+				{
+					Version:            1,
+					ScalarToListFields: []string{"hosts"},
+					FlattenedObjects:   []string{"settings"},
+				},
+			},
+		}
+		r.SetDefault(&Product{Name: "Test"})
+		return r
+	}
+
+	cases := []struct {
+		description string
+		version     int
+		expected    []*StateMigrationField
+	}{
+		{
+			description: "latest migration is undone",
+			version:     1,
+			expected: []*StateMigrationField{
+				{Name: "hosts", Type: "schema.TypeString"},
+				{Name: "new_name", Type: "schema.TypeString"},
+				{Name: "project", Type: "schema.TypeString"},
+				{Name: "settings", Type: "schema.TypeList", Fields: []*StateMigrationField{
+					{Name: "size", Type: "schema.TypeInt"},
+				}},
+			},
+		},
+		{
+			description: "all migrations are undone",
+			version:     0,
+			expected: []*StateMigrationField{
+				{Name: "hosts", Type: "schema.TypeString"},
+				{Name: "old_name", Type: "schema.TypeString"},
+				{Name: "project", Type: "schema.TypeString"},
+				{Name: "settings", Type: "schema.TypeList", Fields: []*StateMigrationField{
+					{Name: "size", Type: "schema.TypeInt"},
+				}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := newResource()
+			if got, want := r.SchemaVersion, 2; got != want {
+				t.Errorf("expected schema version %d to be %d", got, want)
+			}
+			if !r.StateUpgraders {
+				t.Errorf("expected state upgraders to be enabled")
+			}
+
+			got, err := r.StateMigrationSchema(tc.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %s to be %s", stateMigrationFieldNames(got), stateMigrationFieldNames(tc.expected))
+			}
+		})
+	}
+}
+
+func TestResourceStateMigrationSchemaChained(t *testing.T) {
+	t.Parallel()
+
+	newResource := func(migrations ...*StateMigration) Resource {
+		r := Resource{
+			Name:    "Thing",
+			BaseUrl: "things",
+			Properties: []*Type{
+				{Name: "name", Type: "String"},
+			},
+			StateMigrations: migrations,
+		}
+		r.SetDefault(&Product{Name: "Test"})
+		return r
+	}
+
+	r := newResource(
+		&StateMigration{
+			Version:       0,
+			RenamedFields: []StateMigrationRename{{From: "first_name", To: "middle_name"}},
+		},
+		&StateMigration{
+			Version:       1,
+			RenamedFields: []StateMigrationRename{{From: "middle_name", To: "name"}},
+		},
+	)
+	for _, m := range r.StateMigrations {
+		m.Validate(r)
+	}
+
+	for version, expected := range []string{"first_name", "middle_name", "name"} {
+		got, err := r.StateMigrationSchema(version)
+		if err != nil {
+			t.Fatalf("unexpected error building schema version %d: %s", version, err)
+		}
+		if len(got) != 1 || got[0].Name != expected {
+			t.Errorf("expected schema version %d to be [%s:schema.TypeString], got %s", version, expected, stateMigrationFieldNames(got))
+		}
+	}
+
+	// The later migration renamed middle_name away, so the earlier one can't
+	// refer to the current name of the field.
+	r = newResource(
+		&StateMigration{
+			Version:       0,
+			RenamedFields: []StateMigrationRename{{From: "first_name", To: "name"}},
+		},
+		&StateMigration{
+			Version:       1,
+			RenamedFields: []StateMigrationRename{{From: "middle_name", To: "name"}},
+		},
+	)
+	if _, err := r.StateMigrationSchema(0); err == nil {
+		t.Errorf("expected an error renaming a field missing from schema version 1")
+	}
+}
+
+func TestStateMigrationTestJson(t *testing.T) {
+	t.Parallel()
+
+	test := StateMigrationTest{
+		OldState: map[string]any{
+			"name": "foo",
+			"settings": []any{
+				map[any]any{"size": 10},
+			},
+		},
+	}
+
+	if got, want := test.OldStateJson(), `{"name":"foo","settings":[{"size":10}]}`; got != want {
+		t.Errorf("expected %s to be %s", got, want)
+	}
+}
+
+func stateMigrationFieldNames(fields []*StateMigrationField) []string {
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.Name+":"+f.Type)
+		for _, n := range stateMigrationFieldNames(f.Fields) {
+			names = append(names, f.Name+"."+n)
+		}
+	}
+	return names
+}
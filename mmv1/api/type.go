@@ -57,6 +57,17 @@ type Type struct {
 	// a different version.
 	RemovedMessage string `yaml:"removed_message,omitempty"`
 
+	// The previous name of a renamed field. A deprecated field with that name
+	// is generated alongside this one, and the resource reads and writes
+	// whichever of the two is configured, so existing configurations keep
+	// working until the alias is removed in a major release. Only supported
+	// on top-level mutable fields.
+	RenamedFrom string `yaml:"renamed_from,omitempty"`
+
+	// Set on the deprecated alias of a renamed field, to the name of the field
+	// that replaces it. We should never set this ourselves.
+	RenamedTo string `yaml:"-"`
+
 	// If set value will not be sent to server on sync.
 	// For nested fields, this also needs to be set on each descendant (ie. self,
 	// child, etc.).
@@ -373,6 +384,10 @@ func (t *Type) Validate(rName string) {
 
 	t.validateLabelsField()
 
+	if t.RenamedFrom != "" {
+		t.validateRenamedFrom(rName)
+	}
+
 	switch {
 	case t.IsA("Array"):
 		t.ItemType.Validate(rName)
@@ -386,6 +401,59 @@ func (t *Type) Validate(rName string) {
 	}
 }
 
+func (t *Type) validateRenamedFrom(rName string) {
+	if t.ParentMetadata != nil {
+		log.Fatalf("Property %s cannot use renamed_from as it is not a top-level field in resource %s", t.Lineage(), rName)
+	}
+
+	if t.RenamedFrom == t.Name {
+		log.Fatalf("Property %s cannot be renamed from itself in resource %s", t.Name, rName)
+	}
+
+	if t.Output || t.FlattenObject || t.UrlParamOnly {
+		log.Fatalf("Property %s cannot use renamed_from with output, flatten_object or url_param_only in resource %s", t.Name, rName)
+	}
+
+	if t.DefaultValue != nil {
+		log.Fatalf("Property %s cannot use renamed_from with default_value in resource %s", t.Name, rName)
+	}
+
+	// Moving a configuration from the alias to the field changes both of
+	// them, which would recreate the resource.
+	if t.IsForceNew() {
+		log.Fatalf("Property %s cannot use renamed_from as it is immutable in resource %s", t.Name, rName)
+	}
+
+	if t.IsA("Array") && t.IsSet && t.ItemType.IsA("NestedObject") {
+		log.Fatalf("Property %s cannot use renamed_from as it is a set of nested objects in resource %s", t.Name, rName)
+	}
+
+	for _, p := range t.ResourceMetadata.AllUserProperties() {
+		if p.Name == t.RenamedFrom {
+			log.Fatalf("Property %s is renamed from %s, which is still a property of resource %s", t.Name, t.RenamedFrom, rName)
+		}
+	}
+}
+
+// Returns the deprecated alias of a renamed field. It shares everything but
+// its name and requiredness with the field, and is only used to render its
+// schema and documentation; the field's own expanders and flatteners are used
+// with whichever of the two is configured.
+func (t Type) RenamedFromProperty() *Type {
+	name, alias := google.Underscore(t.Name), google.Underscore(t.RenamedFrom)
+
+	p := t
+	p.Name = t.RenamedFrom
+	p.RenamedFrom = ""
+	p.RenamedTo = t.Name
+	p.Required = false
+	p.DeprecationMessage = fmt.Sprintf("`%s` is deprecated and will be removed in a future major release. Use `%s` instead.", alias, name)
+	if !slices.Contains(t.ExactlyOneOf, alias) {
+		p.Conflicts = append(slices.Clone(t.Conflicts), name)
+	}
+	return &p
+}
+
 // TODO rewrite: add validations
 // check :description, required: true
 // check :update_verb, allowed: %i[POST PUT PATCH NONE],
@@ -962,6 +1030,18 @@ func propertyWithClientSide(clientSide bool) func(*Type) {
 	}
 }
 
+func propertyWithRequired(required bool) func(*Type) {
+	return func(p *Type) {
+		p.Required = required
+	}
+}
+
+func propertyWithUrlParamOnly(urlParamOnly bool) func(*Type) {
+	return func(p *Type) {
+		p.UrlParamOnly = urlParamOnly
+	}
+}
+
 func propertyWithIgnoreWrite(ignoreWrite bool) func(*Type) {
 	return func(p *Type) {
 		p.IgnoreWrite = ignoreWrite
@@ -1208,6 +1288,13 @@ func (t *Type) GetPropertySchemaPath(schemaPath string) string {
 			})
 		}
 
+		// or if it is the deprecated alias of a renamed field
+		if index == -1 && len(pathTkns) == 0 {
+			index = slices.IndexFunc(nestedProps, func(p *Type) bool {
+				return p.RenamedFrom != "" && p.RenamedFrom == camelPname
+			})
+		}
+
 		if index == -1 {
 			return ""
 		}
@@ -425,3 +425,104 @@ func TestProviderOnly(t *testing.T) {
 		})
 	}
 }
+
+func TestRenamedFromProperty(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description          string
+		obj                  Type
+		others               []*Type
+		expectedConflicts    []string
+		expectedExactlyOneOf []string
+		expectedAlias        Type
+	}{
+		{
+			description: "optional",
+			obj:         Type{Name: "newName", RenamedFrom: "oldName"},
+			expectedAlias: Type{
+				Name:               "oldName",
+				RenamedTo:          "newName",
+				Conflicts:          []string{"new_name"},
+				DeprecationMessage: "`old_name` is deprecated and will be removed in a future major release. Use `new_name` instead.",
+			},
+		},
+		{
+			description: "required",
+			obj:         Type{Name: "newName", RenamedFrom: "oldName", Required: true},
+			expectedAlias: Type{
+				Name:               "oldName",
+				RenamedTo:          "newName",
+				Conflicts:          []string{"new_name"},
+				DeprecationMessage: "`old_name` is deprecated and will be removed in a future major release. Use `new_name` instead.",
+			},
+		},
+		{
+			description: "in exactly_one_of",
+			obj:         Type{Name: "newName", RenamedFrom: "oldName", ExactlyOneOf: []string{"new_name", "other"}},
+			others: []*Type{
+				{Name: "other", ExactlyOneOf: []string{"new_name", "other"}},
+			},
+			expectedExactlyOneOf: []string{"new_name", "other", "old_name"},
+			expectedAlias: Type{
+				Name:               "oldName",
+				RenamedTo:          "newName",
+				ExactlyOneOf:       []string{"new_name", "other", "old_name"},
+				DeprecationMessage: "`old_name` is deprecated and will be removed in a future major release. Use `new_name` instead.",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			obj := tc.obj
+			r := Resource{
+				Name:       "Thing",
+				BaseUrl:    "things",
+				Properties: append([]*Type{&obj}, tc.others...),
+			}
+			r.SetDefault(&Product{Name: "Test"})
+
+			// Requiring the field or its alias is left to the resource's
+			// CustomizeDiff.
+			if obj.Required != tc.obj.Required {
+				t.Errorf("expected renamed field to keep required %t", tc.obj.Required)
+			}
+			if !reflect.DeepEqual(obj.Conflicts, tc.expectedConflicts) {
+				t.Errorf("expected conflicts %v to be %v", obj.Conflicts, tc.expectedConflicts)
+			}
+			if !reflect.DeepEqual(obj.ExactlyOneOf, tc.expectedExactlyOneOf) {
+				t.Errorf("expected exactly_one_of %v to be %v", obj.ExactlyOneOf, tc.expectedExactlyOneOf)
+			}
+			for _, o := range tc.others {
+				if !reflect.DeepEqual(o.ExactlyOneOf, tc.expectedExactlyOneOf) {
+					t.Errorf("expected exactly_one_of of %s %v to be %v", o.Name, o.ExactlyOneOf, tc.expectedExactlyOneOf)
+				}
+			}
+
+			alias := obj.RenamedFromProperty()
+			if alias.Name != tc.expectedAlias.Name || alias.RenamedTo != tc.expectedAlias.RenamedTo || alias.RenamedFrom != "" {
+				t.Errorf("expected alias name %s renamed to %s to be %s renamed to %s", alias.Name, alias.RenamedTo, tc.expectedAlias.Name, tc.expectedAlias.RenamedTo)
+			}
+			if alias.Required {
+				t.Errorf("expected alias to be optional")
+			}
+			if alias.DeprecationMessage != tc.expectedAlias.DeprecationMessage {
+				t.Errorf("expected deprecation message %q to be %q", alias.DeprecationMessage, tc.expectedAlias.DeprecationMessage)
+			}
+			if !reflect.DeepEqual(alias.Conflicts, tc.expectedAlias.Conflicts) {
+				t.Errorf("expected alias conflicts %v to be %v", alias.Conflicts, tc.expectedAlias.Conflicts)
+			}
+			if !reflect.DeepEqual(alias.ExactlyOneOf, tc.expectedAlias.ExactlyOneOf) {
+				t.Errorf("expected alias exactly_one_of %v to be %v", alias.ExactlyOneOf, tc.expectedAlias.ExactlyOneOf)
+			}
+			if got, want := obj.GetPropertySchemaPath("old_name"), "old_name"; got != want {
+				t.Errorf("expected schema path %q to be %q", got, want)
+			}
+		})
+	}
+}
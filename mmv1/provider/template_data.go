@@ -81,6 +81,19 @@ func (td *TemplateData) GenerateResourceFile(filePath string, resource api.Resou
 		"templates/terraform/update_mask.go.tmpl",
 		"templates/terraform/nested_query.go.tmpl",
 		"templates/terraform/unordered_list_customize_diff.go.tmpl",
+		"templates/terraform/state_migration.go.tmpl",
+	}
+	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+}
+
+func (td *TemplateData) GenerateActionFile(filePath string, resource api.Resource) {
+	templatePath := "templates/terraform/action.go.tmpl"
+	templates := []string{
+		templatePath,
+		"templates/terraform/schema_property.go.tmpl",
+		"templates/terraform/schema_subresource.go.tmpl",
+		"templates/terraform/expand_resource_ref.tmpl",
+		"templates/terraform/expand_property_method.go.tmpl",
 	}
 	td.GenerateFile(filePath, templatePath, resource, true, templates...)
 }
@@ -148,6 +161,14 @@ func (td *TemplateData) GenerateTestFile(filePath string, resource api.Resource)
 	td.GenerateFile(filePath, templatePath, tmplInput, true, templates...)
 }
 
+func (td *TemplateData) GenerateStateMigrationTestFile(filePath string, resource api.Resource) {
+	templatePath := "templates/terraform/examples/base_configs/state_migration_test_file.go.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+}
+
 func (td *TemplateData) GenerateIamPolicyFile(filePath string, resource api.Resource) {
 	templatePath := "templates/terraform/iam_policy.go.tmpl"
 	templates := []string{
@@ -172,6 +193,14 @@ func (td *TemplateData) GenerateIamDatasourceDocumentationFile(filePath string,
 	td.GenerateFile(filePath, templatePath, resource, false, templates...)
 }
 
+func (td *TemplateData) GenerateIamPolicyKindDatasourceDocumentationFile(filePath string, kindResource api.IamPolicyKindResource) {
+	templatePath := "templates/terraform/datasource_iam_policy_kind.html.markdown.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	td.GenerateFile(filePath, templatePath, kindResource, false, templates...)
+}
+
 func (td *TemplateData) GenerateIamPolicyTestFile(filePath string, resource api.Resource) {
 	templatePath := "templates/terraform/examples/base_configs/iam_test_file.go.tmpl"
 	templates := []string{
@@ -41,6 +41,9 @@ type Terraform struct {
 
 	ResourcesForVersion []map[string]string
 
+	// Request batchers declared by resources, mapped to their default send_after.
+	RequestBatchersForVersion map[string]string
+
 	TargetVersionName string
 
 	Version product.Version
@@ -106,12 +109,17 @@ func (t *Terraform) GenerateObject(object api.Resource, outputFolder, productPat
 		if generateCode {
 			// log.Printf("Generating %s tests", object.Name)
 			t.GenerateResourceTests(object, *templateData, outputFolder)
+			t.GenerateResourceStateMigrationTests(object, *templateData, outputFolder)
 			t.GenerateResourceSweeper(object, *templateData, outputFolder)
 			// log.Printf("Generating %s metadata", object.Name)
 			t.GenerateResourceMetadata(object, *templateData, outputFolder)
 		}
 	}
 
+	for _, action := range object.Actions {
+		t.GenerateAction(*action, *templateData, outputFolder, generateCode, generateDocs)
+	}
+
 	// if iam_policy is not defined or excluded, don't generate it
 	if object.IamPolicy == nil || object.IamPolicy.Exclude {
 		return
@@ -174,6 +182,23 @@ func (t *Terraform) GenerateResourceTests(object api.Resource, templateData Temp
 	templateData.GenerateTestFile(targetFilePath, object)
 }
 
+func (t *Terraform) GenerateResourceStateMigrationTests(object api.Resource, templateData TemplateData, outputFolder string) {
+	hasTests := slices.ContainsFunc(object.StateMigrations, func(m *api.StateMigration) bool {
+		return len(m.Tests) > 0
+	})
+	if !hasTests {
+		return
+	}
+
+	productName := t.Product.ApiName
+	targetFolder := path.Join(outputFolder, t.FolderName(), "services", productName)
+	if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+		log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
+	}
+	targetFilePath := path.Join(targetFolder, fmt.Sprintf("resource_%s_state_migration_generated_test.go", t.ResourceGoFilename(object)))
+	templateData.GenerateStateMigrationTestFile(targetFilePath, object)
+}
+
 func (t *Terraform) GenerateResourceSweeper(object api.Resource, templateData TemplateData, outputFolder string) {
 	if !object.ShouldGenerateSweepers() {
 		return
@@ -188,6 +213,41 @@ func (t *Terraform) GenerateResourceSweeper(object api.Resource, templateData Te
 	templateData.GenerateSweeperFile(targetFilePath, object)
 }
 
+// GenerateAction creates the resource, documentation, tests and metadata for
+// an action declared on a resource. Actions reuse the resource templates
+// through the resource built for them in api.Action.SetDefault.
+func (t *Terraform) GenerateAction(action api.Action, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) {
+	object := *action.ActionResource
+	if object.IsExcluded() || action.ResourceMetadata.IsExcluded() {
+		return
+	}
+	object.ImportPath = action.ResourceMetadata.ImportPath
+	object.Compiler = action.ResourceMetadata.Compiler
+
+	log.Printf("Generating %s action", object.Name)
+	if generateCode {
+		productName := t.Product.ApiName
+		targetFolder := path.Join(outputFolder, t.FolderName(), "services", productName)
+		if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+			log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
+		}
+		targetFilePath := path.Join(targetFolder, fmt.Sprintf("resource_%s.go", t.ResourceGoFilename(object)))
+		templateData.GenerateActionFile(targetFilePath, object)
+
+		t.GenerateResourceTests(object, templateData, outputFolder)
+		t.GenerateResourceMetadata(object, templateData, outputFolder)
+	}
+
+	if generateDocs {
+		targetFolder := path.Join(outputFolder, "website", "docs", "r")
+		if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+			log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
+		}
+		targetFilePath := path.Join(targetFolder, fmt.Sprintf("%s.html.markdown", t.FullResourceName(object)))
+		templateData.GenerateDocumentationFile(targetFilePath, object)
+	}
+}
+
 // GenerateProduct creates the product.go file for a given service directory.
 // This will be used to seed the directory and add a package-level comment
 // specific to the product.
@@ -260,6 +320,11 @@ func (t *Terraform) GenerateIamDocumentation(object api.Resource, templateData T
 	}
 	targetFilePath = path.Join(datasourceDocFolder, fmt.Sprintf("%s_iam_policy.html.markdown", t.FullResourceName(object)))
 	templateData.GenerateIamDatasourceDocumentationFile(targetFilePath, object)
+
+	for _, kind := range object.IamPolicyKinds() {
+		targetFilePath = path.Join(datasourceDocFolder, fmt.Sprintf("%s_iam_%s_policy.html.markdown", t.FullResourceName(object), kind.Name))
+		templateData.GenerateIamPolicyKindDatasourceDocumentationFile(targetFilePath, api.IamPolicyKindResource{Resource: &object, Kind: kind})
+	}
 }
 
 // Finds the folder name for a given version of the terraform provider
@@ -801,6 +866,39 @@ func (t *Terraform) generateResourcesForVersion(products []*api.Product) {
 				"ResourceName":  resourceName,
 				"IamClassName":  iamClassName,
 			})
+
+			if object.Batching != nil && !object.IsExcluded() {
+				if t.RequestBatchersForVersion == nil {
+					t.RequestBatchersForVersion = make(map[string]string)
+				}
+				t.RequestBatchersForVersion[object.BatcherName()] = object.Batching.SendAfter
+			}
+
+			if iamClassName != "" {
+				for _, kind := range object.IamPolicyKinds() {
+					t.IAMResourceCount++
+					t.ResourcesForVersion = append(t.ResourcesForVersion, map[string]string{
+						"TerraformName":          api.IamPolicyKindResource{Resource: object, Kind: kind}.KindTerraformName(),
+						"IamPolicyKind":          kind.CamelName(),
+						"IamPolicyKindClassName": iamClassName,
+					})
+				}
+			}
+
+			if object.IsExcluded() {
+				continue
+			}
+			for _, action := range object.Actions {
+				ar := action.ActionResource
+				if ar.Exclude || ar.NotInVersion(productDefinition.VersionObjOrClosest(t.TargetVersionName)) {
+					continue
+				}
+				t.ResourceCount++
+				t.ResourcesForVersion = append(t.ResourcesForVersion, map[string]string{
+					"TerraformName": ar.TerraformName(),
+					"ResourceName":  fmt.Sprintf("%s.Resource%s", service, ar.ResourceName()),
+				})
+			}
 		}
 	}
 }
@@ -0,0 +1,113 @@
+package fakegcp
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// Fault makes matching requests to the server fail.
+type Fault struct {
+	// HTTP method of the requests to fail. Matches any method if empty.
+	Method string
+
+	// Regular expression matched against the path of the requests to fail,
+	// e.g. "/topics/my-topic$". Matches any path if empty.
+	Path string
+
+	// Number of matching requests to fail. Defaults to 1; -1 fails all
+	// matching requests.
+	Count int
+
+	// HTTP status of the error, such as 409 or 503.
+	Status int
+
+	// Message of the error. Defaults to the status text.
+	Message string
+
+	// If true, the request is handled, and its change applied, but its
+	// operation finishes with the error instead, as for an operation that fails
+	// part-way. Only create, update and delete requests are matched.
+	InOperation bool
+
+	path *regexp.Regexp
+}
+
+// InjectFault makes the requests matching f fail. Faults are matched in the
+// order they were injected, and each request fails with at most one fault.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f.Count == 0 {
+		f.Count = 1
+	}
+	if f.Path != "" {
+		f.path = regexp.MustCompile(f.Path)
+	}
+	s.faults = append(s.faults, &f)
+}
+
+// takeFault returns the fault that r should fail with, if any, and counts it
+// as used.
+func (s *Server) takeFault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if f.path != nil && !f.path.MatchString(r.URL.Path) {
+			continue
+		}
+		if f.InOperation && r.Method == "GET" {
+			continue
+		}
+
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (f *Fault) message() string {
+	if f.Message != "" {
+		return f.Message
+	}
+	return http.StatusText(f.Status)
+}
+
+// Canonical google.rpc.Code values and names for HTTP statuses, used in
+// errors and operation errors.
+var rpcCodes = map[int]struct {
+	code   int
+	status string
+}{
+	http.StatusBadRequest:          {3, "INVALID_ARGUMENT"},
+	http.StatusUnauthorized:        {16, "UNAUTHENTICATED"},
+	http.StatusForbidden:           {7, "PERMISSION_DENIED"},
+	http.StatusNotFound:            {5, "NOT_FOUND"},
+	http.StatusConflict:            {6, "ALREADY_EXISTS"},
+	http.StatusPreconditionFailed:  {9, "FAILED_PRECONDITION"},
+	http.StatusTooManyRequests:     {8, "RESOURCE_EXHAUSTED"},
+	http.StatusInternalServerError: {13, "INTERNAL"},
+	http.StatusNotImplemented:      {12, "UNIMPLEMENTED"},
+	http.StatusServiceUnavailable:  {14, "UNAVAILABLE"},
+	http.StatusGatewayTimeout:      {4, "DEADLINE_EXCEEDED"},
+}
+
+func rpcCode(status int) int {
+	if c, ok := rpcCodes[status]; ok {
+		return c.code
+	}
+	return 2 // UNKNOWN
+}
+
+func rpcStatus(status int) string {
+	if c, ok := rpcCodes[status]; ok {
+		return c.status
+	}
+	return "UNKNOWN"
+}
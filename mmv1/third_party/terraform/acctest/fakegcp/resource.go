@@ -0,0 +1,208 @@
+package fakegcp
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Resource describes a collection served by the fake server, using the same
+// URL templates as the resource's mmv1 definition, e.g.
+//
+//	&fakegcp.Resource{
+//		BaseUrl:   "projects/{{project}}/locations/{{location}}/instances",
+//		CreateUrl: "projects/{{project}}/locations/{{location}}/instances?instanceId={{name}}",
+//		Async:     true,
+//	}
+//
+// URLs are matched against the end of the request path, so they work with any
+// base path, such as "/v1/" or "/v1beta1/".
+type Resource struct {
+	// URL of the collection, used to list resources. Corresponds to `base_url`.
+	BaseUrl string
+
+	// URL to create a resource, which may include query parameters. Defaults to
+	// BaseUrl. Corresponds to `create_url`.
+	CreateUrl string
+
+	// HTTP verb used to create a resource, "POST" or "PUT". Defaults to "POST".
+	// Corresponds to `create_verb`.
+	CreateVerb string
+
+	// URL of a single resource. Defaults to BaseUrl + "/{{name}}". Corresponds
+	// to `self_link`.
+	SelfLink string
+
+	// Request body fields holding SelfLink parameters that are not part of
+	// CreateUrl, e.g. "name" for resources created by POST to BaseUrl. Field
+	// values that are paths, such as "projects/p/topics/t", use their last
+	// segment. Corresponds to `identity`.
+	Identity []string
+
+	// If true, creates, updates and deletes return long-running operations.
+	// Corresponds to `async`.
+	Async bool
+
+	// If true, the `name` field of a resource is the last segment of its
+	// SelfLink, as in Compute APIs. Otherwise it is the full SelfLink.
+	ShortName bool
+
+	// Key of the list of resources in list responses. Defaults to the last
+	// segment of BaseUrl.
+	ListKey string
+
+	// Status returned when an `etag` sent with an update or delete does not match
+	// the current one. Defaults to 412 (Precondition Failed); some APIs use
+	// 409 (Conflict) instead.
+	EtagMismatchStatus int
+
+	base      *urlTemplate
+	create    *urlTemplate
+	createQ   map[string]string
+	selfLink  *urlTemplate
+	listKey   string
+	selfLinkT string
+}
+
+func (r *Resource) init() error {
+	if r.BaseUrl == "" {
+		return fmt.Errorf("fakegcp: BaseUrl is required")
+	}
+	if r.CreateVerb == "" {
+		r.CreateVerb = "POST"
+	}
+	if r.EtagMismatchStatus == 0 {
+		r.EtagMismatchStatus = 412
+	}
+
+	r.selfLinkT = r.SelfLink
+	if r.selfLinkT == "" {
+		r.selfLinkT = strings.TrimSuffix(r.BaseUrl, "/") + "/{{name}}"
+	}
+	createUrl := r.CreateUrl
+	if createUrl == "" {
+		createUrl = r.BaseUrl
+	}
+	createPath, createQuery, _ := strings.Cut(createUrl, "?")
+
+	var err error
+	if r.base, err = compileURLTemplate(r.BaseUrl); err != nil {
+		return err
+	}
+	if r.create, err = compileURLTemplate(createPath); err != nil {
+		return err
+	}
+	if r.selfLink, err = compileURLTemplate(r.selfLinkT); err != nil {
+		return err
+	}
+
+	// Query parameters of CreateUrl, keyed by parameter, with the name of the
+	// SelfLink parameter they set.
+	r.createQ = make(map[string]string)
+	if createQuery != "" {
+		for _, kv := range strings.Split(createQuery, "&") {
+			k, v, _ := strings.Cut(kv, "=")
+			if m := urlTemplateParam.FindStringSubmatch(v); m != nil {
+				r.createQ[k] = m[1]
+			}
+		}
+	}
+
+	r.listKey = r.ListKey
+	if r.listKey == "" {
+		segments := strings.Split(strings.TrimSuffix(r.BaseUrl, "/"), "/")
+		r.listKey = segments[len(segments)-1]
+	}
+	return nil
+}
+
+// createVars returns the SelfLink parameters of a create request, read from
+// the path and query of the request and from its body.
+func (r *Resource) createVars(pathVars map[string]string, query url.Values, body map[string]interface{}) map[string]string {
+	vars := make(map[string]string)
+	for k, v := range pathVars {
+		vars[k] = v
+	}
+	for param, name := range r.createQ {
+		if v := query.Get(param); v != "" {
+			vars[name] = v
+		}
+	}
+	for _, field := range r.Identity {
+		if _, ok := vars[field]; ok {
+			continue
+		}
+		if v, ok := body[field].(string); ok && v != "" {
+			vars[field] = v[strings.LastIndex(v, "/")+1:]
+		}
+	}
+	return vars
+}
+
+var urlTemplateParam = regexp.MustCompile(`^\{\{%?(\w+)\}\}$`)
+
+// urlTemplate matches request paths against an mmv1 URL template such as
+// "projects/{{project}}/topics/{{name}}".
+type urlTemplate struct {
+	template string
+	re       *regexp.Regexp
+	params   []string
+}
+
+func compileURLTemplate(template string) (*urlTemplate, error) {
+	t := &urlTemplate{template: strings.Trim(template, "/")}
+	var pattern strings.Builder
+	// Any base path, such as /v1/ or /compute/beta/, may precede the template.
+	pattern.WriteString(`^/(?:.*/)?`)
+	for i, segment := range strings.Split(t.template, "/") {
+		if i > 0 {
+			pattern.WriteString("/")
+		}
+		if m := urlTemplateParam.FindStringSubmatch(segment); m != nil {
+			pattern.WriteString(`([^/:]+)`)
+			t.params = append(t.params, m[1])
+			continue
+		}
+		if strings.Contains(segment, "{{") {
+			return nil, fmt.Errorf("fakegcp: unsupported URL template segment %q in %q", segment, template)
+		}
+		pattern.WriteString(regexp.QuoteMeta(segment))
+	}
+	pattern.WriteString(`$`)
+
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, err
+	}
+	t.re = re
+	return t, nil
+}
+
+// match returns the template parameters of path, or false if it doesn't match.
+func (t *urlTemplate) match(path string) (map[string]string, bool) {
+	m := t.re.FindStringSubmatch(path)
+	if m == nil {
+		return nil, false
+	}
+	vars := make(map[string]string, len(t.params))
+	for i, p := range t.params {
+		vars[p] = m[i+1]
+	}
+	return vars, true
+}
+
+// expand returns the template with its parameters replaced by vars.
+func (t *urlTemplate) expand(vars map[string]string) (string, error) {
+	segments := strings.Split(t.template, "/")
+	for i, segment := range segments {
+		if m := urlTemplateParam.FindStringSubmatch(segment); m != nil {
+			v, ok := vars[m[1]]
+			if !ok || v == "" {
+				return "", fmt.Errorf("missing value for %q", m[1])
+			}
+			segments[i] = v
+		}
+	}
+	return strings.Join(segments, "/"), nil
+}
@@ -0,0 +1,469 @@
+// Package fakegcp provides an in-process fake of Google Cloud REST APIs for
+// provider unit tests. It keeps resources in memory, described by the URL
+// templates of their mmv1 definitions, returns long-running operations for
+// async resources, and can inject errors into requests and operations, so
+// that generated CRUD code and its error handling can be tested without
+// network access.
+package fakegcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   map[string]interface{}
+}
+
+type operation struct {
+	name      string
+	pollsLeft int
+	response  map[string]interface{}
+	fault     *Fault
+}
+
+// Server is a fake Google Cloud REST API server.
+type Server struct {
+	// OperationPolls is the number of times an operation is polled before it
+	// is done. It must be set before requests are sent. Defaults to 1.
+	OperationPolls int
+
+	srv       *httptest.Server
+	resources []*Resource
+
+	mu         sync.Mutex
+	objects    map[string]map[string]interface{}
+	operations map[string]*operation
+	faults     []*Fault
+	requests   []Request
+	nextOp     int
+	nextEtag   int
+}
+
+// NewServer starts a fake server for the given resources, which is closed
+// when the test finishes.
+func NewServer(t testing.TB, resources ...*Resource) *Server {
+	t.Helper()
+
+	s := &Server{
+		OperationPolls: 1,
+		resources:      resources,
+		objects:        make(map[string]map[string]interface{}),
+		operations:     make(map[string]*operation),
+	}
+	for _, r := range resources {
+		if err := r.init(); err != nil {
+			t.Fatalf("invalid fake resource %q: %s", r.BaseUrl, err)
+		}
+	}
+	s.srv = httptest.NewServer(s)
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+// URL returns the base URL of the server, such as "http://127.0.0.1:1234".
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// Client returns an HTTP client sending requests to the server.
+func (s *Server) Client() *http.Client {
+	return s.srv.Client()
+}
+
+// NewConfig returns a provider configuration for the given project that sends
+// the requests of all services to the server, keeping their API versions, and
+// retries the errors the provider retries by default.
+func (s *Server) NewConfig(project string) *transport_tpg.Config {
+	config := &transport_tpg.Config{
+		Project:      project,
+		UserAgent:    "fakegcp",
+		PollInterval: 10 * time.Millisecond,
+	}
+	transport_tpg.ConfigureBasePaths(config)
+
+	// Point every *BasePath field at the server.
+	v := reflect.ValueOf(config).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() != reflect.String || !strings.HasSuffix(v.Type().Field(i).Name, "BasePath") || f.String() == "" {
+			continue
+		}
+		u, err := url.Parse(f.String())
+		if err != nil {
+			continue
+		}
+		f.SetString(s.srv.URL + u.Path)
+	}
+
+	config.Client = &http.Client{
+		Transport: transport_tpg.NewTransportWithDefaultRetries(s.srv.Client().Transport),
+	}
+	return config
+}
+
+// Object returns a copy of the resource with the given self link, such as
+// "projects/p/topics/t".
+func (s *Server) Object(selfLink string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[strings.Trim(selfLink, "/")]
+	if !ok {
+		return nil, false
+	}
+	return copyObject(obj), true
+}
+
+// SetObject creates or replaces the resource with the given self link, as if
+// it was changed outside of Terraform.
+func (s *Server) SetObject(selfLink string, obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj = copyObject(obj)
+	obj["etag"] = s.newEtag()
+	s.objects[strings.Trim(selfLink, "/")] = obj
+}
+
+// DeleteObject deletes the resource with the given self link, as if it was
+// deleted outside of Terraform.
+func (s *Server) DeleteObject(selfLink string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.objects, strings.Trim(selfLink, "/"))
+}
+
+// Requests returns the requests received by the server so far, excluding
+// operation polls.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body map[string]interface{}
+	if b, err := io.ReadAll(r.Body); err == nil && len(b) > 0 {
+		if err := json.Unmarshal(b, &body); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON body: %s", err))
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if op, ok := s.findOperation(r.URL.Path); ok && r.Method == "GET" {
+		s.pollOperation(w, op)
+		return
+	}
+
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Body:   body,
+	})
+
+	fault := s.takeFault(r)
+	if fault != nil && !fault.InOperation {
+		writeError(w, fault.Status, fault.message())
+		return
+	}
+
+	for _, res := range s.resources {
+		if vars, ok := res.selfLink.match(r.URL.Path); ok {
+			selfLink, _ := res.selfLink.expand(vars)
+			_, exists := s.objects[selfLink]
+			switch {
+			case r.Method == "GET":
+				s.get(w, selfLink)
+			case r.Method == "PUT" && res.CreateVerb == "PUT" && !exists:
+				s.create(w, r, res, vars, body, fault)
+			case r.Method == "PATCH" || r.Method == "PUT":
+				s.update(w, r, res, selfLink, body, fault)
+			case r.Method == "DELETE":
+				s.delete(w, r, res, selfLink, fault)
+			default:
+				writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not supported for %s", r.Method, r.URL.Path))
+			}
+			return
+		}
+		if vars, ok := res.create.match(r.URL.Path); ok && r.Method == "POST" && res.CreateVerb == "POST" {
+			s.create(w, r, res, vars, body, fault)
+			return
+		}
+		if _, ok := res.base.match(r.URL.Path); ok && r.Method == "GET" {
+			s.list(w, r, res)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, fmt.Sprintf("no fake resource matches %s %s", r.Method, r.URL.Path))
+}
+
+func (s *Server) get(w http.ResponseWriter, selfLink string) {
+	obj, ok := s.objects[selfLink]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("resource %q not found", selfLink))
+		return
+	}
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, res *Resource, pathVars map[string]string, body map[string]interface{}, fault *Fault) {
+	selfLink, err := res.selfLink.expand(res.createVars(pathVars, r.URL.Query(), body))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unable to create resource: %s", err))
+		return
+	}
+	if _, ok := s.objects[selfLink]; ok {
+		writeError(w, http.StatusConflict, fmt.Sprintf("resource %q already exists", selfLink))
+		return
+	}
+
+	obj := copyObject(body)
+	if res.ShortName {
+		obj["name"] = selfLink[strings.LastIndex(selfLink, "/")+1:]
+	} else {
+		obj["name"] = selfLink
+	}
+	obj["etag"] = s.newEtag()
+	s.objects[selfLink] = obj
+
+	s.respond(w, res, obj, fault)
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request, res *Resource, selfLink string, body map[string]interface{}, fault *Fault) {
+	obj, ok := s.objects[selfLink]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("resource %q not found", selfLink))
+		return
+	}
+	etag, _ := body["etag"].(string)
+	if !s.checkEtag(w, r, res, obj, etag) {
+		return
+	}
+
+	updated := copyObject(obj)
+	if mask := r.URL.Query().Get("updateMask"); mask != "" {
+		for _, path := range strings.Split(mask, ",") {
+			setPath(updated, strings.Split(strings.TrimSpace(path), "."), body)
+		}
+	} else if r.Method == "PUT" {
+		updated = copyObject(body)
+		updated["name"] = obj["name"]
+	} else {
+		for k, v := range body {
+			updated[k] = v
+		}
+	}
+	updated["etag"] = s.newEtag()
+	s.objects[selfLink] = updated
+
+	s.respond(w, res, updated, fault)
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request, res *Resource, selfLink string, fault *Fault) {
+	obj, ok := s.objects[selfLink]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("resource %q not found", selfLink))
+		return
+	}
+	if !s.checkEtag(w, r, res, obj, r.URL.Query().Get("etag")) {
+		return
+	}
+	delete(s.objects, selfLink)
+
+	s.respond(w, res, map[string]interface{}{}, fault)
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, res *Resource) {
+	vars, _ := res.base.match(r.URL.Path)
+	prefix, _ := res.base.expand(vars)
+
+	var selfLinks []string
+	for selfLink := range s.objects {
+		if vars, ok := res.selfLink.match("/" + selfLink); ok {
+			if p, _ := res.base.expand(vars); p == prefix {
+				selfLinks = append(selfLinks, selfLink)
+			}
+		}
+	}
+	sort.Strings(selfLinks)
+
+	start, _ := strconv.Atoi(r.URL.Query().Get("pageToken"))
+	end := len(selfLinks)
+	for _, param := range []string{"pageSize", "maxResults"} {
+		if size, err := strconv.Atoi(r.URL.Query().Get(param)); err == nil && size > 0 && start+size < end {
+			end = start + size
+		}
+	}
+	if start > end {
+		start = end
+	}
+
+	items := make([]interface{}, 0, end-start)
+	for _, selfLink := range selfLinks[start:end] {
+		items = append(items, s.objects[selfLink])
+	}
+	resp := map[string]interface{}{}
+	if len(items) > 0 {
+		resp[res.listKey] = items
+	}
+	if end < len(selfLinks) {
+		resp["nextPageToken"] = strconv.Itoa(end)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// checkEtag writes an error and returns false if etag, or the If-Match
+// header, is set and does not match the etag of obj.
+func (s *Server) checkEtag(w http.ResponseWriter, r *http.Request, res *Resource, obj map[string]interface{}, etag string) bool {
+	if etag == "" {
+		etag = r.Header.Get("If-Match")
+	}
+	if etag == "" || etag == obj["etag"] {
+		return true
+	}
+	writeError(w, res.EtagMismatchStatus, fmt.Sprintf("etag %q does not match the current etag %q", etag, obj["etag"]))
+	return false
+}
+
+// respond writes the response to a create, update or delete of res: obj, or
+// an operation for async resources.
+func (s *Server) respond(w http.ResponseWriter, res *Resource, obj map[string]interface{}, fault *Fault) {
+	if !res.Async {
+		if fault != nil {
+			// Faults in operations of synchronous resources fail the request,
+			// after the change is applied.
+			writeError(w, fault.Status, fault.message())
+			return
+		}
+		writeJSON(w, http.StatusOK, obj)
+		return
+	}
+
+	s.nextOp++
+	op := &operation{
+		name:      fmt.Sprintf("operations/fake-%d", s.nextOp),
+		pollsLeft: s.OperationPolls,
+		response:  copyObject(obj),
+		fault:     fault,
+	}
+	s.operations[op.name] = op
+	writeJSON(w, http.StatusOK, op.toJSON())
+}
+
+func (s *Server) findOperation(path string) (*operation, bool) {
+	i := strings.LastIndex(path, "/operations/")
+	if i < 0 {
+		return nil, false
+	}
+	op, ok := s.operations[path[i+1:]]
+	return op, ok
+}
+
+func (s *Server) pollOperation(w http.ResponseWriter, op *operation) {
+	if op.pollsLeft > 0 {
+		op.pollsLeft--
+	}
+	writeJSON(w, http.StatusOK, op.toJSON())
+}
+
+func (op *operation) toJSON() map[string]interface{} {
+	res := map[string]interface{}{
+		"name": op.name,
+		"done": op.pollsLeft <= 0,
+	}
+	if op.pollsLeft > 0 {
+		res["metadata"] = map[string]interface{}{
+			"progressPercent": 50,
+			"statusMessage":   "Running",
+		}
+		return res
+	}
+	if op.fault != nil {
+		res["error"] = map[string]interface{}{
+			"code":    rpcCode(op.fault.Status),
+			"message": op.fault.message(),
+		}
+		return res
+	}
+	res["response"] = op.response
+	return res
+}
+
+func (s *Server) newEtag() string {
+	s.nextEtag++
+	return fmt.Sprintf("etag-%d", s.nextEtag)
+}
+
+// setPath copies the value at path in src to dst, or deletes it from dst if
+// it isn't set in src, as an update mask path does.
+func setPath(dst map[string]interface{}, path []string, src map[string]interface{}) {
+	if len(path) == 1 {
+		if v, ok := src[path[0]]; ok {
+			dst[path[0]] = v
+		} else {
+			delete(dst, path[0])
+		}
+		return
+	}
+
+	srcChild, _ := src[path[0]].(map[string]interface{})
+	dstChild, ok := dst[path[0]].(map[string]interface{})
+	if !ok {
+		dstChild = make(map[string]interface{})
+		dst[path[0]] = dstChild
+	}
+	setPath(dstChild, path[1:], srcChild)
+}
+
+func copyObject(obj map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{})
+	if obj == nil {
+		return res
+	}
+	b, _ := json.Marshal(obj)
+	json.Unmarshal(b, &res)
+	return res
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error in the format of Google APIs, as parsed by
+// googleapi.CheckResponse.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    status,
+			"message": message,
+			"status":  rpcStatus(status),
+		},
+	})
+}
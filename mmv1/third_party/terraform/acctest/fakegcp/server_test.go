@@ -0,0 +1,184 @@
+package fakegcp
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+	"google.golang.org/api/googleapi"
+)
+
+var testTopic = &Resource{
+	BaseUrl:    "projects/{{project}}/topics",
+	CreateVerb: "PUT",
+}
+
+var testInstance = &Resource{
+	BaseUrl:   "projects/{{project}}/locations/{{location}}/instances",
+	CreateUrl: "projects/{{project}}/locations/{{location}}/instances?instanceId={{name}}",
+	Async:     true,
+}
+
+func sendTestRequest(t *testing.T, config *transport_tpg.Config, method, url string, body map[string]interface{}) (map[string]interface{}, error) {
+	t.Helper()
+	return transport_tpg.SendRequest(transport_tpg.SendRequestOptions{
+		Config:    config,
+		Method:    method,
+		RawURL:    url,
+		UserAgent: config.UserAgent,
+		Body:      body,
+	})
+}
+
+func checkErrorCode(t *testing.T, err error, code int) {
+	t.Helper()
+	gerr, ok := err.(*googleapi.Error)
+	if !ok || gerr.Code != code {
+		t.Fatalf("expected a %d error, got %v", code, err)
+	}
+}
+
+func TestServer_syncCRUD(t *testing.T) {
+	s := NewServer(t, testTopic)
+	config := s.NewConfig("my-project")
+	url := config.PubsubBasePath + "projects/my-project/topics/my-topic"
+
+	res, err := sendTestRequest(t, config, "PUT", url, map[string]interface{}{
+		"labels": map[string]interface{}{"env": "test"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating: %s", err)
+	}
+	if res["name"] != "projects/my-project/topics/my-topic" {
+		t.Errorf("expected the full name to be set, got %v", res["name"])
+	}
+
+	_, err = sendTestRequest(t, config, "PATCH", url+"?updateMask=messageRetentionDuration", map[string]interface{}{
+		"messageRetentionDuration": "600s",
+		"labels":                   map[string]interface{}{"ignored": "true"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error updating: %s", err)
+	}
+	res, err = sendTestRequest(t, config, "GET", url, nil)
+	if err != nil {
+		t.Fatalf("unexpected error reading: %s", err)
+	}
+	if res["messageRetentionDuration"] != "600s" {
+		t.Errorf("expected the masked field to be updated, got %v", res)
+	}
+	if expected := map[string]interface{}{"env": "test"}; !reflect.DeepEqual(res["labels"], expected) {
+		t.Errorf("expected fields outside the mask to be unchanged, got %v", res["labels"])
+	}
+
+	items, err := transport_tpg.ListAll(transport_tpg.SendRequestOptions{
+		Config: config,
+		RawURL: config.PubsubBasePath + "projects/my-project/topics",
+	}, "topics", transport_tpg.ListWithPageSize(1))
+	if err != nil || len(items) != 1 {
+		t.Errorf("expected 1 listed topic, got %v (%v)", items, err)
+	}
+
+	if _, err := sendTestRequest(t, config, "DELETE", url, nil); err != nil {
+		t.Fatalf("unexpected error deleting: %s", err)
+	}
+	_, err = sendTestRequest(t, config, "GET", url, nil)
+	checkErrorCode(t, err, http.StatusNotFound)
+}
+
+func TestServer_asyncCreate(t *testing.T) {
+	s := NewServer(t, testInstance)
+	s.OperationPolls = 2
+	config := s.NewConfig("my-project")
+	base := s.URL() + "/v1/"
+
+	op, err := sendTestRequest(t, config, "POST", base+"projects/my-project/locations/us-central1/instances?instanceId=my-instance", map[string]interface{}{
+		"tier": "BASIC_HDD",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating: %s", err)
+	}
+	if op["done"] != false {
+		t.Fatalf("expected a running operation, got %v", op)
+	}
+
+	for i := 0; i < 2; i++ {
+		op, err = sendTestRequest(t, config, "GET", base+op["name"].(string), nil)
+		if err != nil {
+			t.Fatalf("unexpected error polling: %s", err)
+		}
+	}
+	if op["done"] != true {
+		t.Fatalf("expected the operation to be done after 2 polls, got %v", op)
+	}
+	response := op["response"].(map[string]interface{})
+	if response["name"] != "projects/my-project/locations/us-central1/instances/my-instance" || response["tier"] != "BASIC_HDD" {
+		t.Errorf("unexpected operation response %v", response)
+	}
+	if _, ok := s.Object("projects/my-project/locations/us-central1/instances/my-instance"); !ok {
+		t.Errorf("expected the instance to exist")
+	}
+}
+
+func TestServer_faults(t *testing.T) {
+	s := NewServer(t, testTopic, testInstance)
+	config := s.NewConfig("my-project")
+	topicUrl := config.PubsubBasePath + "projects/my-project/topics/my-topic"
+	s.SetObject("projects/my-project/topics/my-topic", map[string]interface{}{"name": "projects/my-project/topics/my-topic"})
+
+	// Creating an existing resource conflicts.
+	_, err := sendTestRequest(t, config, "PUT", config.PubsubBasePath+"projects/my-project/topics/other", nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating: %s", err)
+	}
+	_, err = sendTestRequest(t, config, "POST", s.URL()+"/v1/"+"projects/my-project/locations/l/instances?instanceId=i", nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating: %s", err)
+	}
+	_, err = sendTestRequest(t, config, "POST", s.URL()+"/v1/"+"projects/my-project/locations/l/instances?instanceId=i", nil)
+	checkErrorCode(t, err, http.StatusConflict)
+
+	// Stale etags fail.
+	_, err = sendTestRequest(t, config, "PATCH", topicUrl, map[string]interface{}{"etag": "stale"})
+	checkErrorCode(t, err, http.StatusPreconditionFailed)
+
+	// Injected retryable errors are retried by the provider's transport.
+	s.InjectFault(Fault{Method: "GET", Path: "/topics/my-topic$", Status: http.StatusServiceUnavailable})
+	if _, err := sendTestRequest(t, config, "GET", topicUrl, nil); err != nil {
+		t.Fatalf("expected the injected 503 to be retried, got %s", err)
+	}
+
+	// Injected errors that aren't retryable are returned.
+	s.InjectFault(Fault{Method: "DELETE", Status: http.StatusForbidden, Message: "denied"})
+	_, err = sendTestRequest(t, config, "DELETE", topicUrl, nil)
+	checkErrorCode(t, err, http.StatusForbidden)
+	if _, ok := s.Object("projects/my-project/topics/my-topic"); !ok {
+		t.Errorf("expected a failed delete not to delete the topic")
+	}
+
+	// Operation errors still apply the change.
+	s.InjectFault(Fault{Method: "DELETE", Status: http.StatusInternalServerError, InOperation: true})
+	op, err := sendTestRequest(t, config, "DELETE", s.URL()+"/v1/"+"projects/my-project/locations/l/instances/i", nil)
+	if err != nil {
+		t.Fatalf("unexpected error deleting: %s", err)
+	}
+	op, err = sendTestRequest(t, config, "GET", s.URL()+"/v1/"+op["name"].(string), nil)
+	if err != nil {
+		t.Fatalf("unexpected error polling: %s", err)
+	}
+	if opErr, ok := op["error"].(map[string]interface{}); !ok || opErr["code"] != float64(13) {
+		t.Errorf("expected the operation to fail with INTERNAL, got %v", op)
+	}
+	if _, ok := s.Object("projects/my-project/locations/l/instances/i"); ok {
+		t.Errorf("expected the instance to be deleted despite the operation error")
+	}
+
+	var methods []string
+	for _, r := range s.Requests() {
+		methods = append(methods, r.Method)
+	}
+	if expected := []string{"PUT", "POST", "POST", "PATCH", "GET", "GET", "DELETE", "DELETE"}; !reflect.DeepEqual(methods, expected) {
+		t.Errorf("expected requests %v, got %v", expected, methods)
+	}
+}
@@ -3,10 +3,13 @@ package clouddeploy_test
 import (
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 
 	"github.com/hashicorp/terraform-provider-google/google/acctest"
+	"github.com/hashicorp/terraform-provider-google/google/acctest/fakegcp"
 	"github.com/hashicorp/terraform-provider-google/google/envvar"
+	"github.com/hashicorp/terraform-provider-google/google/services/clouddeploy"
 )
 
 func TestAccClouddeployAutomation_update(t *testing.T) {
@@ -186,3 +189,85 @@ resource "google_clouddeploy_delivery_pipeline" "pipeline" {
  }
 `, context)
 }
+
+var fakeClouddeployAutomation = &fakegcp.Resource{
+	BaseUrl:   "projects/{{project}}/locations/{{location}}/deliveryPipelines/{{delivery_pipeline}}/automations",
+	CreateUrl: "projects/{{project}}/locations/{{location}}/deliveryPipelines/{{delivery_pipeline}}/automations?automationId={{name}}",
+	Async:     true,
+}
+
+func TestUnitClouddeployAutomation_fakeServerConcurrentChanges(t *testing.T) {
+	s := fakegcp.NewServer(t, fakeClouddeployAutomation)
+	config := s.NewConfig("my-project")
+	r := clouddeploy.ResourceClouddeployAutomation()
+	selfLink := "projects/my-project/locations/us-central1/deliveryPipelines/my-pipeline/automations/my-automation"
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":              "my-automation",
+		"location":          "us-central1",
+		"delivery_pipeline": "my-pipeline",
+		"service_account":   "sa@my-project.iam.gserviceaccount.com",
+		"description":       "updated",
+		"selector": []interface{}{map[string]interface{}{
+			"targets": []interface{}{map[string]interface{}{"id": "*"}},
+		}},
+		"rules": []interface{}{map[string]interface{}{
+			"promote_release_rule": []interface{}{map[string]interface{}{"id": "promote-release"}},
+		}},
+	})
+	if err := r.Create(d, config); err != nil {
+		t.Fatalf("unexpected error creating: %s", err)
+	}
+
+	// changeConcurrently changes the automation outside of Terraform, and
+	// returns the etag Terraform last read and the new one.
+	changeConcurrently := func() (string, string) {
+		obj, ok := s.Object(selfLink)
+		if !ok {
+			t.Fatalf("expected the automation to exist")
+		}
+		obj["suspended"] = true
+		s.SetObject(selfLink, obj)
+		obj, _ = s.Object(selfLink)
+		return d.Get("etag").(string), obj["etag"].(string)
+	}
+	// lastEtags returns the etags sent with the requests with the given
+	// method since the n-th request.
+	lastEtags := func(n int, method string) []string {
+		var etags []string
+		for _, req := range s.Requests()[n:] {
+			if req.Method != method {
+				continue
+			}
+			if etag, ok := req.Body["etag"].(string); ok {
+				etags = append(etags, etag)
+			} else {
+				etags = append(etags, req.Query.Get("etag"))
+			}
+		}
+		return etags
+	}
+
+	// An update with a stale etag is retried with the current one.
+	stale, current := changeConcurrently()
+	n := len(s.Requests())
+	if err := r.Update(d, config); err != nil {
+		t.Fatalf("unexpected error updating: %s", err)
+	}
+	if etags := lastEtags(n, "PATCH"); len(etags) != 2 || etags[0] != stale || etags[1] != current {
+		t.Errorf("expected the update to be sent with %q, then %q, got %q", stale, current, etags)
+	}
+
+	// So is a delete.
+	stale, current = changeConcurrently()
+	n = len(s.Requests())
+	if err := r.Delete(d, config); err != nil {
+		t.Fatalf("unexpected error deleting: %s", err)
+	}
+	if etags := lastEtags(n, "DELETE"); len(etags) != 2 || etags[0] != stale || etags[1] != current {
+		t.Errorf("expected the delete to be sent with %q, then %q, got %q", stale, current, etags)
+	}
+	if _, ok := s.Object(selfLink); ok {
+		t.Errorf("expected the automation to be deleted")
+	}
+}
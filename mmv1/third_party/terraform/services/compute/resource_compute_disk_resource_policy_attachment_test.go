@@ -40,6 +40,35 @@ func TestAccComputeDiskResourcePolicyAttachment_update(t *testing.T) {
 	})
 }
 
+func TestAccComputeDiskResourcePolicyAttachment_batched(t *testing.T) {
+	t.Parallel()
+
+	diskName := fmt.Sprintf("tf-test-%s", acctest.RandString(t, 10))
+	policyName := fmt.Sprintf("tf-test-policy-%s", acctest.RandString(t, 10))
+
+	// Both attachments are added to the disk in one request, and removed in
+	// one request when the test is destroyed.
+	acctest.VcrTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.AccTestPreCheck(t) },
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeDiskResourcePolicyAttachment_batched(diskName, policyName),
+			},
+			{
+				ResourceName:      "google_compute_disk_resource_policy_attachment.snapshot",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "google_compute_disk_resource_policy_attachment.consistency_group",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func testAccComputeDiskResourcePolicyAttachment_basic(diskName, policyName string) string {
 	return fmt.Sprintf(`
 data "google_compute_image" "my_image" {
@@ -119,3 +148,56 @@ resource "google_compute_disk_resource_policy_attachment" "foobar" {
 }
 `, diskName, policyName)
 }
+
+func testAccComputeDiskResourcePolicyAttachment_batched(diskName, policyName string) string {
+	return fmt.Sprintf(`
+provider "google" {
+  batching {
+    batcher {
+      name       = "compute_disk_resource_policies"
+      send_after = "5s"
+    }
+  }
+}
+
+resource "google_compute_disk" "foobar" {
+  name = "%s"
+  type = "pd-balanced"
+  size = 10
+  zone = "us-central1-a"
+}
+
+resource "google_compute_resource_policy" "snapshot" {
+  name   = "%s-snapshot"
+  region = "us-central1"
+  snapshot_schedule_policy {
+    schedule {
+      daily_schedule {
+        days_in_cycle = 1
+        start_time    = "04:00"
+      }
+    }
+  }
+}
+
+resource "google_compute_resource_policy" "consistency_group" {
+  name   = "%s-cg"
+  region = "us-central1"
+  disk_consistency_group_policy {
+    enabled = true
+  }
+}
+
+resource "google_compute_disk_resource_policy_attachment" "snapshot" {
+  name = google_compute_resource_policy.snapshot.name
+  disk = google_compute_disk.foobar.name
+  zone = "us-central1-a"
+}
+
+resource "google_compute_disk_resource_policy_attachment" "consistency_group" {
+  name = google_compute_resource_policy.consistency_group.name
+  disk = google_compute_disk.foobar.name
+  zone = "us-central1-a"
+}
+`, diskName, policyName, policyName)
+}
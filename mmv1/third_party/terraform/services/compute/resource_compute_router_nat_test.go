@@ -10,8 +10,27 @@ import (
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/hashicorp/terraform-provider-google/google/acctest"
 	"github.com/hashicorp/terraform-provider-google/google/envvar"
+	"github.com/hashicorp/terraform-provider-google/google/services/compute"
+	"github.com/hashicorp/terraform-provider-google/google/tpgresource"
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
 )
 
+func TestUnitComputeRouterNat_mutexKey(t *testing.T) {
+	config := &transport_tpg.Config{Project: "my-project", Region: "us-central1"}
+
+	// NATs lock their router, so that concurrent changes to NATs of the same
+	// router don't overwrite each other.
+	tpgresource.CheckMutexKey(t, compute.ResourceComputeRouterNat(), config, compute.ComputeRouterNatMutex, map[string]interface{}{
+		"name":   "my-nat",
+		"router": "my-router",
+	}, "router/us-central1/my-router")
+	tpgresource.CheckMutexKey(t, compute.ResourceComputeRouterNat(), config, compute.ComputeRouterNatMutex, map[string]interface{}{
+		"name":   "my-nat",
+		"router": "my-router",
+		"region": "europe-west1",
+	}, "router/europe-west1/my-router")
+}
+
 func TestAccComputeRouterNat_basic(t *testing.T) {
 	t.Parallel()
 
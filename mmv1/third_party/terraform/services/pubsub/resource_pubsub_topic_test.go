@@ -2,10 +2,15 @@ package pubsub_test
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-provider-google/google/acctest"
+	"github.com/hashicorp/terraform-provider-google/google/acctest/fakegcp"
+	"github.com/hashicorp/terraform-provider-google/google/services/pubsub"
 )
 
 func TestAccPubsubTopic_update(t *testing.T) {
@@ -583,3 +588,54 @@ resource "google_pubsub_topic" "foo" {
 }
 `, topic)
 }
+
+var fakePubsubTopic = &fakegcp.Resource{
+	BaseUrl:    "projects/{{project}}/topics",
+	CreateVerb: "PUT",
+}
+
+func TestUnitPubsubTopic_fakeServerCRUD(t *testing.T) {
+	s := fakegcp.NewServer(t, fakePubsubTopic)
+	config := s.NewConfig("my-project")
+	r := pubsub.ResourcePubsubTopic()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name": "my-topic",
+	})
+	if err := r.Create(d, config); err != nil {
+		t.Fatalf("unexpected error creating: %s", err)
+	}
+	if d.Id() != "projects/my-project/topics/my-topic" {
+		t.Errorf("unexpected id %q", d.Id())
+	}
+	if _, ok := s.Object("projects/my-project/topics/my-topic"); !ok {
+		t.Fatalf("expected the topic to be created")
+	}
+
+	// A topic deleted outside of Terraform is removed from state on read.
+	s.DeleteObject("projects/my-project/topics/my-topic")
+	if err := r.Read(d, config); err != nil {
+		t.Fatalf("unexpected error reading: %s", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the deleted topic to be removed from state, got id %q", d.Id())
+	}
+}
+
+func TestUnitPubsubTopic_fakeServerCreateConflict(t *testing.T) {
+	s := fakegcp.NewServer(t, fakePubsubTopic)
+	config := s.NewConfig("my-project")
+	r := pubsub.ResourcePubsubTopic()
+
+	s.InjectFault(fakegcp.Fault{Method: "PUT", Status: http.StatusConflict, Message: "Resource already exists in the project"})
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name": "my-topic",
+	})
+	err := r.Create(d, config)
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected a conflict error, got %v", err)
+	}
+	if _, ok := s.Object("projects/my-project/topics/my-topic"); ok {
+		t.Errorf("expected the failed create not to create the topic")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 
 	"github.com/hashicorp/terraform-provider-google/google/acctest"
 	"github.com/hashicorp/terraform-provider-google/google/services/workflows"
@@ -124,6 +125,55 @@ EOF
 `, name)
 }
 
+func TestAccWorkflowsWorkflow_CreateTimeoutResumed(t *testing.T) {
+	// Depends on the create operation outlasting its timeout
+	acctest.SkipIfVcr(t)
+	t.Parallel()
+
+	workflowName := fmt.Sprintf("tf-test-acc-workflow-%d", acctest.RandInt(t))
+
+	acctest.VcrTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.AccTestPreCheck(t) },
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories(t),
+		CheckDestroy:             testAccCheckWorkflowsWorkflowDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				// The create times out, but the workflow is kept rather than tainted,
+				// and isn't read until the create finishes.
+				Config: testAccWorkflowsWorkflow_CreateTimeout(workflowName),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				// The update waits for the create if it's still running.
+				Config: testAccWorkflowsWorkflow_Update(workflowName),
+			},
+		},
+	})
+}
+
+func testAccWorkflowsWorkflow_CreateTimeout(name string) string {
+	return fmt.Sprintf(`
+resource "google_workflows_workflow" "example" {
+  name                = "%s"
+  region              = "us-central1"
+  description         = "Magic"
+  deletion_protection = false
+  source_contents     = <<-EOF
+  - returnOutput:
+      return: "Hello"
+EOF
+
+  timeouts {
+    create = "1s"
+  }
+}
+`, name)
+}
+
 func TestAccWorkflowsWorkflow_UpdateDeletionProtectionFalseToTrue(t *testing.T) {
 	// Custom test written to test diffs
 	t.Parallel()
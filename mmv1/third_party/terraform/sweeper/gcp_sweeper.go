@@ -74,25 +74,23 @@ func ListParentResourcesInLocation(d *tpgresource.ResourceDataMock, config *tran
 		return nil, err
 	}
 
-	res, err := transport_tpg.SendRequest(transport_tpg.SendRequestOptions{
+	rl, err := transport_tpg.ListAll(transport_tpg.SendRequestOptions{
 		Config:    config,
 		Method:    "GET",
 		Project:   config.Project,
 		RawURL:    listUrl,
 		UserAgent: config.UserAgent,
-	})
+	}, responseField)
 	if err != nil {
 		log.Printf("[INFO][SWEEPER_LOG] Error in response from request %s: %s", listUrl, err)
 		return nil, err
 	}
 
-	resourceList, ok := res[responseField]
-	if !ok {
+	if len(rl) == 0 {
 		log.Printf("[INFO][SWEEPER_LOG] Nothing found in response.")
 		return nil, fmt.Errorf("nothing found in response")
 	}
 
-	rl := resourceList.([]interface{})
 	names := []string{}
 	for _, r := range rl {
 		resource := r.(map[string]interface{})
@@ -0,0 +1,49 @@
+package tpgiamresource
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"fmt"
+
+	"github.com/hashicorp/terraform-provider-google/google/tpgresource"
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func DataSourceIamRawPolicy(parentSpecificSchema map[string]*schema.Schema, newUpdaterFunc NewResourceIamRawPolicyUpdaterFunc, options ...func(*IamSettings)) *schema.Resource {
+	settings := &IamSettings{}
+	for _, o := range options {
+		o(settings)
+	}
+
+	return &schema.Resource{
+		Read: DatasourceIamRawPolicyRead(newUpdaterFunc),
+		// if non-empty, this will be used to send a deprecation message when the
+		// datasource is used.
+		DeprecationMessage: settings.DeprecationMessage,
+		Schema:             tpgresource.MergeSchemas(IamPolicyBaseDataSourceSchema, parentSpecificSchema),
+		UseJSONNumber:      true,
+	}
+}
+
+func DatasourceIamRawPolicyRead(newUpdaterFunc NewResourceIamRawPolicyUpdaterFunc) schema.ReadFunc {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		config := meta.(*transport_tpg.Config)
+
+		updater, err := newUpdaterFunc(d, config)
+		if err != nil {
+			return err
+		}
+
+		policy, err := iamRawPolicyReadWithRetry(updater)
+		if err != nil {
+			return transport_tpg.HandleNotFoundError(err, d, fmt.Sprintf("Resource %q with policy", updater.DescribeResource()))
+		}
+
+		if err := setIamRawPolicyState(d, policy); err != nil {
+			return err
+		}
+		d.SetId(updater.GetResourceId())
+
+		return nil
+	}
+}
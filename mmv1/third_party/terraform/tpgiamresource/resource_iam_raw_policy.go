@@ -0,0 +1,278 @@
+package tpgiamresource
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/hashicorp/terraform-provider-google/google/tpgresource"
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+// These types are implemented per GCP resource type and policy kind for policies other than
+// the allow policy (e.g. deny policies). As the wire format differs per kind, policies are
+// handled as raw JSON objects and the implementation maps them to and from the API payloads.
+type (
+	// The ResourceIamRawPolicyUpdater interface is implemented for each policy kind attached to a GCP resource.
+	// Implementations should be created per resource and should keep track of the resource identifier.
+	ResourceIamRawPolicyUpdater interface {
+		// Fetch the existing policy attached to a resource, including its etag if any.
+		GetResourceIamRawPolicy() (map[string]interface{}, error)
+
+		// Replaces the existing policy attached to a resource.
+		SetResourceIamRawPolicy(policy map[string]interface{}) error
+
+		// A mutex guards against concurrent to call to the SetResourceIamRawPolicy method.
+		// The mutex key should be made of the resource type, policy kind and resource id.
+		GetMutexKey() string
+
+		// Returns the unique resource identifier.
+		GetResourceId() string
+
+		// Textual description of this resource to be used in error message.
+		// The description should include the unique resource identifier.
+		DescribeResource() string
+	}
+
+	// Factory for generating ResourceIamRawPolicyUpdater for given ResourceData resource
+	NewResourceIamRawPolicyUpdaterFunc func(d tpgresource.TerraformResourceData, config *transport_tpg.Config) (ResourceIamRawPolicyUpdater, error)
+)
+
+var IamRawPolicyBaseSchema = map[string]*schema.Schema{
+	"policy_data": {
+		Type:             schema.TypeString,
+		Required:         true,
+		DiffSuppressFunc: structure.SuppressJsonDiff,
+		ValidateFunc:     validation.StringIsJSON,
+	},
+	"etag": {
+		Type:     schema.TypeString,
+		Computed: true,
+	},
+}
+
+func ResourceIamRawPolicy(parentSpecificSchema map[string]*schema.Schema, newUpdaterFunc NewResourceIamRawPolicyUpdaterFunc, resourceIdParser ResourceIdParserFunc, options ...func(*IamSettings)) *schema.Resource {
+	settings := NewIamSettings(options...)
+
+	return &schema.Resource{
+		Create: ResourceIamRawPolicyCreate(newUpdaterFunc),
+		Read:   ResourceIamRawPolicyRead(newUpdaterFunc),
+		Update: ResourceIamRawPolicyUpdate(newUpdaterFunc),
+		Delete: ResourceIamRawPolicyDelete(newUpdaterFunc),
+
+		// if non-empty, this will be used to send a deprecation message when the
+		// resource is used.
+		DeprecationMessage: settings.DeprecationMessage,
+
+		Schema: tpgresource.MergeSchemas(IamRawPolicyBaseSchema, parentSpecificSchema),
+		Importer: &schema.ResourceImporter{
+			State: iamPolicyImport(resourceIdParser),
+		},
+		UseJSONNumber: true,
+	}
+}
+
+func ResourceIamRawPolicyCreate(newUpdaterFunc NewResourceIamRawPolicyUpdaterFunc) schema.CreateFunc {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		config := meta.(*transport_tpg.Config)
+
+		updater, err := newUpdaterFunc(d, config)
+		if err != nil {
+			return err
+		}
+
+		if err = setIamRawPolicyData(d, updater); err != nil {
+			return err
+		}
+
+		d.SetId(updater.GetResourceId())
+		return ResourceIamRawPolicyRead(newUpdaterFunc)(d, meta)
+	}
+}
+
+func ResourceIamRawPolicyRead(newUpdaterFunc NewResourceIamRawPolicyUpdaterFunc) schema.ReadFunc {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		config := meta.(*transport_tpg.Config)
+
+		updater, err := newUpdaterFunc(d, config)
+		if err != nil {
+			return err
+		}
+
+		policy, err := iamRawPolicyReadWithRetry(updater)
+		if err != nil {
+			return transport_tpg.HandleNotFoundError(err, d, fmt.Sprintf("Resource %q with policy", updater.DescribeResource()))
+		}
+
+		return setIamRawPolicyState(d, policy)
+	}
+}
+
+func ResourceIamRawPolicyUpdate(newUpdaterFunc NewResourceIamRawPolicyUpdaterFunc) schema.UpdateFunc {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		config := meta.(*transport_tpg.Config)
+
+		updater, err := newUpdaterFunc(d, config)
+		if err != nil {
+			return err
+		}
+
+		if d.HasChange("policy_data") {
+			if err := setIamRawPolicyData(d, updater); err != nil {
+				return err
+			}
+		}
+
+		return ResourceIamRawPolicyRead(newUpdaterFunc)(d, meta)
+	}
+}
+
+func ResourceIamRawPolicyDelete(newUpdaterFunc NewResourceIamRawPolicyUpdaterFunc) schema.DeleteFunc {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		config := meta.(*transport_tpg.Config)
+
+		updater, err := newUpdaterFunc(d, config)
+		if err != nil {
+			return err
+		}
+
+		// Set an empty policy to delete the attached policy.
+		return writeIamRawPolicy(d, updater, make(map[string]interface{}))
+	}
+}
+
+// Locking wrapper around read-only operation with retries.
+func iamRawPolicyReadWithRetry(updater ResourceIamRawPolicyUpdater) (map[string]interface{}, error) {
+	mutexKey := updater.GetMutexKey()
+	transport_tpg.MutexStore.Lock(mutexKey)
+	defer transport_tpg.MutexStore.Unlock(mutexKey)
+
+	log.Printf("[DEBUG] Retrieving policy for %s\n", updater.DescribeResource())
+	var policy map[string]interface{}
+	err := transport_tpg.Retry(transport_tpg.RetryOptions{
+		RetryFunc: func() (perr error) {
+			policy, perr = updater.GetResourceIamRawPolicy()
+			return perr
+		},
+		Timeout: 10 * time.Minute,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] Retrieved policy for %s: %#v\n", updater.DescribeResource(), policy)
+	return policy, nil
+}
+
+func setIamRawPolicyData(d *schema.ResourceData, updater ResourceIamRawPolicyUpdater) error {
+	policy, err := structure.ExpandJsonFromString(d.Get("policy_data").(string))
+	if err != nil {
+		return fmt.Errorf("'policy_data' is not valid for %s: %s", updater.DescribeResource(), err)
+	}
+
+	return writeIamRawPolicy(d, updater, policy)
+}
+
+// Replaces the policy attached to the resource, sending the etag of the policy the
+// change is based on so that the API rejects it if the policy was modified since.
+// This is the etag last read into state, or on create the etag of the current policy.
+// Like the allow policy, the write is retried with the etag of the current policy
+// when it conflicts with concurrent policy changes.
+func writeIamRawPolicy(d *schema.ResourceData, updater ResourceIamRawPolicyUpdater, policy map[string]interface{}) error {
+	mutexKey := updater.GetMutexKey()
+	transport_tpg.MutexStore.Lock(mutexKey)
+	defer transport_tpg.MutexStore.Unlock(mutexKey)
+
+	etag := d.Get("etag").(string)
+	if d.Id() == "" {
+		current, err := updater.GetResourceIamRawPolicy()
+		if err != nil {
+			return err
+		}
+		etag, _ = current["etag"].(string)
+	}
+
+	backoff := time.Second
+	for {
+		if etag != "" {
+			policy["etag"] = etag
+		}
+
+		log.Printf("[DEBUG] Setting policy for %s to %#v\n", updater.DescribeResource(), policy)
+		err := updater.SetResourceIamRawPolicy(policy)
+		if err == nil {
+			return nil
+		}
+		if !tpgresource.IsConflictError(err) {
+			return err
+		}
+
+		log.Printf("[DEBUG]: Concurrent policy changes, retrying with the current etag after %s\n", backoff)
+		time.Sleep(backoff)
+		backoff = backoff * 2
+		if backoff > 30*time.Second {
+			return errwrap.Wrapf(fmt.Sprintf("Error setting policy for %s: Too many conflicts.  Latest error: {{err}}", updater.DescribeResource()), err)
+		}
+
+		current, err := updater.GetResourceIamRawPolicy()
+		if err != nil {
+			return err
+		}
+		etag, _ = current["etag"].(string)
+	}
+}
+
+func setIamRawPolicyState(d tpgresource.TerraformResourceData, policy map[string]interface{}) error {
+	etag, _ := policy["etag"].(string)
+	if err := d.Set("etag", etag); err != nil {
+		return fmt.Errorf("Error setting etag: %s", err)
+	}
+	if err := d.Set("policy_data", marshalIamRawPolicy(policy)); err != nil {
+		return fmt.Errorf("Error setting policy_data: %s", err)
+	}
+	return nil
+}
+
+func marshalIamRawPolicy(policy map[string]interface{}) string {
+	data := make(map[string]interface{}, len(policy))
+	for k, v := range policy {
+		if k != "etag" {
+			data[k] = v
+		}
+	}
+	pdBytes, _ := json.Marshal(data)
+	return string(pdBytes)
+}
+
+// Top-level fields that policies commonly return as output-only metadata. They are
+// dropped from policies of kinds that don't list the fields they manage.
+var iamRawPolicyOutputOnlyFields = []string{"name", "uid", "kind", "createTime", "updateTime", "deleteTime"}
+
+// Returns the given top-level fields of a policy, along with its etag. This is used by
+// implementations of ResourceIamRawPolicyUpdater to drop output-only fields returned by the API.
+// If no fields are given, the policy is returned without common output-only fields.
+func SelectRawPolicyFields(policy map[string]interface{}, fields ...string) map[string]interface{} {
+	if len(fields) == 0 {
+		selected := make(map[string]interface{}, len(policy))
+		for k, v := range policy {
+			if !slices.Contains(iamRawPolicyOutputOnlyFields, k) {
+				selected[k] = v
+			}
+		}
+		return selected
+	}
+
+	selected := make(map[string]interface{})
+	for _, f := range append(fields, "etag") {
+		if v, ok := policy[f]; ok {
+			selected[f] = v
+		}
+	}
+	return selected
+}
@@ -1,6 +1,8 @@
 package tpgresource
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -107,7 +109,81 @@ func OperationDone(w Waiter) bool {
 	return false
 }
 
+// Once an operation has run for operationPollGrowthAfter, the interval between
+// polls grows by operationPollIntervalGrowth after each poll, up to
+// maxOperationPollInterval.
+const (
+	operationPollGrowthAfter    = time.Minute
+	operationPollIntervalGrowth = 1.5
+	maxOperationPollInterval    = time.Minute
+)
+
+// nextOperationPollInterval returns the interval to wait before the next poll
+// of an operation, given the current one. An interval of 0 lets
+// retry.StateChangeConf choose its own backoff and is left unchanged.
+func nextOperationPollInterval(current time.Duration) time.Duration {
+	if current <= 0 || current >= maxOperationPollInterval {
+		return current
+	}
+	next := time.Duration(float64(current) * operationPollIntervalGrowth)
+	if next > maxOperationPollInterval {
+		return maxOperationPollInterval
+	}
+	return next
+}
+
+// OperationProgress returns the progress reported by an operation, either in
+// its metadata as `progressPercent` and `statusMessage`, as used by most APIs,
+// or as top-level `progress` and `statusMessage` fields, as used by Compute.
+// It returns an empty string if the operation reports no progress.
+func OperationProgress(op interface{}) string {
+	var progress struct {
+		Progress      interface{} `json:"progress"`
+		StatusMessage string      `json:"statusMessage"`
+		Metadata      struct {
+			ProgressPercent interface{} `json:"progressPercent"`
+			StatusMessage   string      `json:"statusMessage"`
+		} `json:"metadata"`
+	}
+	if err := Convert(op, &progress); err != nil {
+		return ""
+	}
+
+	percent, message := progress.Metadata.ProgressPercent, progress.Metadata.StatusMessage
+	if percent == nil {
+		percent = progress.Progress
+	}
+	if message == "" {
+		message = progress.StatusMessage
+	}
+	switch {
+	case percent != nil && message != "":
+		return fmt.Sprintf("%v%% complete: %s", percent, message)
+	case percent != nil:
+		return fmt.Sprintf("%v%% complete", percent)
+	default:
+		return message
+	}
+}
+
+// OperationName returns the name of the operation in an API response, or an
+// empty string if the response is not an operation.
+func OperationName(op map[string]interface{}) string {
+	name, _ := op["name"].(string)
+	return name
+}
+
+// IsOperationWaitInterrupted returns whether an error returned by OperationWait
+// is caused by Terraform no longer waiting for the operation, either because it
+// didn't finish in time or because the wait was cancelled, in which case the
+// operation may still be running.
+func IsOperationWaitInterrupted(err error) bool {
+	var timeoutErr *retry.TimeoutError
+	return errors.As(err, &timeoutErr) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
 func CommonRefreshFunc(w Waiter) retry.StateRefreshFunc {
+	lastProgress := ""
 	return func() (interface{}, string, error) {
 		op, err := w.QueryOp()
 		if err != nil {
@@ -132,11 +208,48 @@ func CommonRefreshFunc(w Waiter) retry.StateRefreshFunc {
 		}
 
 		log.Printf("[DEBUG] Got %v while polling for operation %s's status", w.State(), w.OpName())
+		if progress := OperationProgress(op); progress != "" && progress != lastProgress {
+			log.Printf("[INFO] Operation %s is %s", w.OpName(), progress)
+			lastProgress = progress
+		}
 		return op, w.State(), nil
 	}
 }
 
+// OperationPoll queries the operation of w once, without waiting for it, and
+// returns whether it is done. The error is either that of the query, returned
+// as is, or that of the operation once it has failed.
+func OperationPoll(w Waiter) (bool, error) {
+	if OperationDone(w) {
+		return true, w.Error()
+	}
+	op, err := w.QueryOp()
+	if err != nil {
+		return false, err
+	}
+	if err := w.SetOp(op); err != nil {
+		return false, fmt.Errorf("Cannot continue, unable to use operation: %s", err)
+	}
+
+	log.Printf("[DEBUG] Got %v while polling for operation %s's status", w.State(), w.OpName())
+	if progress := OperationProgress(op); progress != "" {
+		log.Printf("[INFO] Operation %s is %s", w.OpName(), progress)
+	}
+	return OperationDone(w), w.Error()
+}
+
+// OperationWait polls the operation of w until it is done or timeout is
+// reached. Polls are pollInterval apart, growing for long operations.
 func OperationWait(w Waiter, activity string, timeout time.Duration, pollInterval time.Duration) error {
+	return OperationWaitContext(context.Background(), w, activity, timeout, pollInterval)
+}
+
+// OperationWaitContext is OperationWait, but also stops waiting once ctx is
+// done, e.g. because Terraform was interrupted.
+func OperationWaitContext(ctx context.Context, w Waiter, activity string, timeout time.Duration, pollInterval time.Duration) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	if OperationDone(w) {
 		return w.Error()
 	}
@@ -144,12 +257,21 @@ func OperationWait(w Waiter, activity string, timeout time.Duration, pollInterva
 	c := &retry.StateChangeConf{
 		Pending:      w.PendingStates(),
 		Target:       w.TargetStates(),
-		Refresh:      CommonRefreshFunc(w),
 		Timeout:      timeout,
 		MinTimeout:   2 * time.Second,
 		PollInterval: pollInterval,
 	}
-	opRaw, err := c.WaitForState()
+	refresh := CommonRefreshFunc(w)
+	start := time.Now()
+	c.Refresh = func() (interface{}, string, error) {
+		res, state, err := refresh()
+		// StateChangeConf reads PollInterval after each refresh, from the same goroutine.
+		if time.Since(start) > operationPollGrowthAfter {
+			c.PollInterval = nextOperationPollInterval(c.PollInterval)
+		}
+		return res, state, err
+	}
+	opRaw, err := c.WaitForStateContext(ctx)
 	if err != nil {
 		return fmt.Errorf("Error waiting for %s: %w", activity, err)
 	}
@@ -1,6 +1,9 @@
 package tpgresource
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/url"
 	"testing"
 	"time"
@@ -67,3 +70,146 @@ func TestOperationWait_TimeoutsShouldRetry(t *testing.T) {
 			expectedRunCount, testWaiter.runCount)
 	}
 }
+
+func TestNextOperationPollInterval(t *testing.T) {
+	cases := map[string]struct {
+		Current  time.Duration
+		Expected time.Duration
+	}{
+		"unset": {
+			Current:  0,
+			Expected: 0,
+		},
+		"grows": {
+			Current:  10 * time.Second,
+			Expected: 15 * time.Second,
+		},
+		"capped": {
+			Current:  50 * time.Second,
+			Expected: maxOperationPollInterval,
+		},
+		"at cap": {
+			Current:  maxOperationPollInterval,
+			Expected: maxOperationPollInterval,
+		},
+	}
+
+	for tn, tc := range cases {
+		if got := nextOperationPollInterval(tc.Current); got != tc.Expected {
+			t.Errorf("bad: %s, expected %s, got %s", tn, tc.Expected, got)
+		}
+	}
+}
+
+func TestOperationProgress(t *testing.T) {
+	cases := map[string]struct {
+		Op       map[string]interface{}
+		Expected string
+	}{
+		"metadata": {
+			Op: map[string]interface{}{
+				"name": "operations/op",
+				"metadata": map[string]interface{}{
+					"progressPercent": 40,
+					"statusMessage":   "Creating nodes",
+				},
+			},
+			Expected: "40% complete: Creating nodes",
+		},
+		"metadata percent only": {
+			Op: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"progressPercent": "75",
+				},
+			},
+			Expected: "75% complete",
+		},
+		"compute": {
+			Op: map[string]interface{}{
+				"progress":      10,
+				"statusMessage": "Pending",
+			},
+			Expected: "10% complete: Pending",
+		},
+		"no progress": {
+			Op: map[string]interface{}{
+				"name": "operations/op",
+				"done": false,
+			},
+			Expected: "",
+		},
+	}
+
+	for tn, tc := range cases {
+		if got := OperationProgress(tc.Op); got != tc.Expected {
+			t.Errorf("bad: %s, expected %q, got %q", tn, tc.Expected, got)
+		}
+	}
+}
+
+type neverDoneWaiter struct {
+	TestWaiter
+}
+
+func (w *neverDoneWaiter) State() string {
+	return "RUNNING"
+}
+
+func (neverDoneWaiter) PendingStates() []string {
+	return []string{"RUNNING"}
+}
+
+func TestOperationWait_Timeout(t *testing.T) {
+	err := OperationWait(&neverDoneWaiter{}, "my-activity", 100*time.Millisecond, 10*time.Millisecond)
+	if !IsOperationWaitInterrupted(err) {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+	if IsOperationWaitInterrupted(fmt.Errorf("Error waiting for my-activity: %w", errors.New("operation failed"))) {
+		t.Errorf("expected an operation error not to be a timeout")
+	}
+}
+
+func TestOperationWaitContext_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	err := OperationWaitContext(ctx, &neverDoneWaiter{}, "my-activity", time.Minute, 10*time.Millisecond)
+	if !IsOperationWaitInterrupted(err) {
+		t.Errorf("expected a cancelled wait to be interrupted, got %v", err)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("expected the wait to stop when its context is cancelled")
+	}
+}
+
+type failedWaiter struct {
+	TestWaiter
+}
+
+func (failedWaiter) Error() error {
+	return errors.New("operation failed")
+}
+
+func TestOperationPoll(t *testing.T) {
+	w := &TestWaiter{}
+	if done, err := OperationPoll(w); done || err == nil {
+		t.Errorf("expected a failed query to return its error, got done: %t, error: %v", done, err)
+	}
+	if done, err := OperationPoll(w); !done || err != nil {
+		t.Errorf("expected the operation to be done, got done: %t, error: %v", done, err)
+	}
+	if w.runCount != 2 {
+		t.Errorf("expected the operation to be queried once per poll, got %d queries", w.runCount)
+	}
+
+	if done, err := OperationPoll(&failedWaiter{TestWaiter{runCount: 1}}); !done || err == nil {
+		t.Errorf("expected a failed operation to be done with its error, got done: %t, error: %v", done, err)
+	}
+	if done, _ := OperationPoll(&neverDoneWaiter{TestWaiter{runCount: 1}}); done {
+		t.Errorf("expected a running operation not to be done")
+	}
+}
@@ -0,0 +1,191 @@
+package tpgresource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// privateStateKey is the key of the provider's values in the private state the
+// SDK keeps for each resource, next to its timeouts and schema version.
+const privateStateKey = "google"
+
+type privateStateContextKey struct{}
+
+// privateState holds the provider's values in the private state of the
+// resource a request is made for.
+type privateState struct {
+	sync.Mutex
+	values map[string]string
+}
+
+// privateStates maps the ResourceData of the resource functions being run to
+// the private state of their resource, as the SDK passes neither private state
+// nor the request context to resource functions.
+var privateStates sync.Map
+
+// PrivateStateServer wraps the SDK provider server returned by server so that
+// resource functions wrapped with WithPrivateState can read and write values in
+// the private state of their resource with GetPrivate and SetPrivate. Unlike
+// attributes, private state isn't shown to users.
+func PrivateStateServer(server func() tfprotov5.ProviderServer) func() tfprotov5.ProviderServer {
+	return func() tfprotov5.ProviderServer {
+		return privateStateServer{server()}
+	}
+}
+
+type privateStateServer struct {
+	tfprotov5.ProviderServer
+}
+
+func (s privateStateServer) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	p, err := decodePrivateState(req.Private)
+	if err != nil {
+		return &tfprotov5.ReadResourceResponse{Diagnostics: privateStateDiagnostics(err)}, nil
+	}
+	resp, err := s.ProviderServer.ReadResource(context.WithValue(ctx, privateStateContextKey{}, p), req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	if resp.Private, err = p.encode(resp.Private); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, privateStateDiagnostics(err)...)
+	}
+	return resp, nil
+}
+
+func (s privateStateServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	resp, err := s.ProviderServer.PlanResourceChange(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	// The SDK plans the private state of changed resources afresh, so the
+	// provider's values are carried over from their prior private state.
+	p, err := decodePrivateState(req.PriorPrivate)
+	if err == nil {
+		resp.PlannedPrivate, err = p.encode(resp.PlannedPrivate)
+	}
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, privateStateDiagnostics(err)...)
+	}
+	return resp, nil
+}
+
+func (s privateStateServer) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	p, err := decodePrivateState(req.PlannedPrivate)
+	if err != nil {
+		return &tfprotov5.ApplyResourceChangeResponse{Diagnostics: privateStateDiagnostics(err)}, nil
+	}
+	resp, err := s.ProviderServer.ApplyResourceChange(context.WithValue(ctx, privateStateContextKey{}, p), req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	if resp.Private, err = p.encode(resp.Private); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, privateStateDiagnostics(err)...)
+	}
+	return resp, nil
+}
+
+func privateStateDiagnostics(err error) []*tfprotov5.Diagnostic {
+	return []*tfprotov5.Diagnostic{
+		{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Error handling private state",
+			Detail:   err.Error(),
+		},
+	}
+}
+
+// decodePrivateState returns the provider's values in a private state encoded
+// by the SDK.
+func decodePrivateState(private []byte) (*privateState, error) {
+	p := &privateState{values: map[string]string{}}
+	if len(private) == 0 {
+		return p, nil
+	}
+	var meta map[string]json.RawMessage
+	if err := json.Unmarshal(private, &meta); err != nil {
+		return nil, fmt.Errorf("Error decoding private state: %s", err)
+	}
+	if raw, ok := meta[privateStateKey]; ok {
+		if err := json.Unmarshal(raw, &p.values); err != nil {
+			return nil, fmt.Errorf("Error decoding provider values in private state: %s", err)
+		}
+	}
+	return p, nil
+}
+
+// encode returns private, a private state encoded by the SDK, with the
+// provider's values set to those of p.
+func (p *privateState) encode(private []byte) ([]byte, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	meta := map[string]interface{}{}
+	if len(private) > 0 {
+		if err := json.Unmarshal(private, &meta); err != nil {
+			return nil, fmt.Errorf("Error decoding private state: %s", err)
+		}
+	}
+	if _, ok := meta[privateStateKey]; !ok && len(p.values) == 0 {
+		return private, nil
+	}
+	if len(p.values) == 0 {
+		delete(meta, privateStateKey)
+	} else {
+		meta[privateStateKey] = p.values
+	}
+	return json.Marshal(meta)
+}
+
+// WithPrivateState adapts a resource function to the SDK's context-aware
+// signature, giving it access to the private state of its resource through
+// GetPrivate and SetPrivate when the provider is served by PrivateStateServer.
+func WithPrivateState(f func(*schema.ResourceData, interface{}) error) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		if p, ok := ctx.Value(privateStateContextKey{}).(*privateState); ok {
+			privateStates.Store(d, p)
+			defer privateStates.Delete(d)
+		}
+		return diag.FromErr(f(d, meta))
+	}
+}
+
+// GetPrivate returns the value of key in the private state of the resource of
+// d, or an empty string if it's not set or private state isn't available.
+func GetPrivate(d *schema.ResourceData, key string) string {
+	v, ok := privateStates.Load(d)
+	if !ok {
+		return ""
+	}
+	p := v.(*privateState)
+	p.Lock()
+	defer p.Unlock()
+	return p.values[key]
+}
+
+// SetPrivate sets key to value in the private state of the resource of d, or
+// removes key if value is empty. The value is dropped, with a warning, if private
+// state isn't available.
+func SetPrivate(d *schema.ResourceData, key, value string) {
+	v, ok := privateStates.Load(d)
+	if !ok {
+		if value != "" {
+			log.Printf("[WARN] Private state is not available to keep %s %q", key, value)
+		}
+		return
+	}
+	p := v.(*privateState)
+	p.Lock()
+	defer p.Unlock()
+	if value == "" {
+		delete(p.values, key)
+	} else {
+		p.values[key] = value
+	}
+}
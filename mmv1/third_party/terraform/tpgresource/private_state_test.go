@@ -0,0 +1,112 @@
+package tpgresource
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// sdkTimeouts stands in for the values the SDK keeps in private state.
+const sdkTimeouts = `{"e2bfb730-ecaa-11e6-8f88-34363bc7c4c0":{"create":60000000000}}`
+
+// fakeResourceServer runs f as the function of a resource, keeping private
+// state the way the SDK does.
+type fakeResourceServer struct {
+	tfprotov5.ProviderServer
+	t *testing.T
+	f func(*schema.ResourceData, interface{}) error
+}
+
+func (s fakeResourceServer) run(ctx context.Context) {
+	d := schema.TestResourceDataRaw(s.t, map[string]*schema.Schema{}, map[string]interface{}{})
+	if diags := WithPrivateState(s.f)(ctx, d, nil); diags.HasError() {
+		s.t.Fatalf("unexpected error: %v", diags)
+	}
+}
+
+func (s fakeResourceServer) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	s.run(ctx)
+	return &tfprotov5.ReadResourceResponse{Private: req.Private}, nil
+}
+
+func (s fakeResourceServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	return &tfprotov5.PlanResourceChangeResponse{PlannedPrivate: []byte(sdkTimeouts)}, nil
+}
+
+func (s fakeResourceServer) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	s.run(ctx)
+	return &tfprotov5.ApplyResourceChangeResponse{Private: []byte(sdkTimeouts)}, nil
+}
+
+func privateStateValues(t *testing.T, private []byte) map[string]interface{} {
+	var meta map[string]interface{}
+	if err := json.Unmarshal(private, &meta); err != nil {
+		t.Fatalf("unexpected error decoding private state %s: %s", private, err)
+	}
+	return meta
+}
+
+func TestPrivateStateServer(t *testing.T) {
+	ctx := context.Background()
+	var f func(*schema.ResourceData, interface{}) error
+	server := PrivateStateServer(func() tfprotov5.ProviderServer {
+		return fakeResourceServer{
+			t: t,
+			f: func(d *schema.ResourceData, meta interface{}) error { return f(d, meta) },
+		}
+	})()
+
+	f = func(d *schema.ResourceData, _ interface{}) error {
+		SetPrivate(d, "pending_operation", "operations/op-1")
+		return nil
+	}
+	applied, err := server.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{PlannedPrivate: []byte(sdkTimeouts)})
+	if err != nil {
+		t.Fatalf("unexpected error applying: %s", err)
+	}
+	expected := privateStateValues(t, []byte(sdkTimeouts))
+	expected["google"] = map[string]interface{}{"pending_operation": "operations/op-1"}
+	if got := privateStateValues(t, applied.Private); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected the applied private state to be %v, got %v", expected, got)
+	}
+
+	planned, err := server.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{PriorPrivate: applied.Private})
+	if err != nil {
+		t.Fatalf("unexpected error planning: %s", err)
+	}
+	if got := privateStateValues(t, planned.PlannedPrivate); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected the planned private state to be %v, got %v", expected, got)
+	}
+
+	f = func(d *schema.ResourceData, _ interface{}) error {
+		if got := GetPrivate(d, "pending_operation"); got != "operations/op-1" {
+			t.Errorf("expected to read the pending operation, got %q", got)
+		}
+		SetPrivate(d, "pending_operation", "")
+		return nil
+	}
+	read, err := server.ReadResource(ctx, &tfprotov5.ReadResourceRequest{Private: planned.PlannedPrivate})
+	if err != nil {
+		t.Fatalf("unexpected error reading: %s", err)
+	}
+	delete(expected, "google")
+	if got := privateStateValues(t, read.Private); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected the read private state to be %v, got %v", expected, got)
+	}
+
+	if read.Diagnostics != nil || planned.Diagnostics != nil || applied.Diagnostics != nil {
+		t.Errorf("unexpected diagnostics: %v, %v, %v", applied.Diagnostics, planned.Diagnostics, read.Diagnostics)
+	}
+}
+
+func TestPrivateStateUnavailable(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{}, map[string]interface{}{})
+	SetPrivate(d, "pending_operation", "operations/op-1")
+	if got := GetPrivate(d, "pending_operation"); got != "" {
+		t.Errorf("expected no private state outside of a private state server, got %q", got)
+	}
+}
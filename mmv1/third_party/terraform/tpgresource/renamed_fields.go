@@ -0,0 +1,73 @@
+package tpgresource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// RequireRenamedField checks that a renamed field that was required, or its
+// deprecated alias, is set. The schema makes the two conflict, and the
+// resource reads and writes whichever of them is configured, see
+// ResolveRenamedField and SetRenamedField.
+func RequireRenamedField(field, alias string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+		rawConfig := diff.GetRawConfig()
+		if rawConfig.IsNull() {
+			return nil
+		}
+		if !rawConfigSet(rawConfig.GetAttr(field)) && !rawConfigSet(rawConfig.GetAttr(alias)) {
+			return fmt.Errorf("one of `%s` or its deprecated alias `%s` must be set", field, alias)
+		}
+		return nil
+	}
+}
+
+// ResolveRenamedField copies the deprecated alias of a renamed field to the
+// field if the alias is the one in use, so that the resource only has to
+// expand the field.
+func ResolveRenamedField(d *schema.ResourceData, field, alias string) error {
+	if !renamedFieldAliasInUse(d, alias) {
+		return nil
+	}
+	return d.Set(field, d.Get(alias))
+}
+
+// SetRenamedField sets the value read for a renamed field on the field or its
+// deprecated alias, whichever is in use, and clears the other one, so that
+// neither of them differs from the configuration.
+func SetRenamedField(d *schema.ResourceData, field, alias string, v interface{}) error {
+	if renamedFieldAliasInUse(d, alias) {
+		field, alias = alias, field
+	}
+	if err := d.Set(field, v); err != nil {
+		return err
+	}
+	return d.Set(alias, nil)
+}
+
+// renamedFieldAliasInUse returns whether the alias of a renamed field is set in
+// the configuration, or in the state when the configuration isn't available,
+// such as when refreshing.
+func renamedFieldAliasInUse(d *schema.ResourceData, alias string) bool {
+	if rawConfig := d.GetRawConfig(); !rawConfig.IsNull() {
+		return rawConfigSet(rawConfig.GetAttr(alias))
+	}
+	_, ok := d.GetOk(alias)
+	return ok
+}
+
+// rawConfigSet returns whether a field is set in the configuration. Blocks
+// that aren't set are empty rather than null, and values that aren't known
+// yet are set.
+func rawConfigSet(v cty.Value) bool {
+	if v.IsNull() {
+		return false
+	}
+	if v.IsKnown() && (v.Type().IsListType() || v.Type().IsSetType()) {
+		return v.LengthInt() > 0
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package tpgresource
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var renamedFieldSchema = map[string]*schema.Schema{
+	"new_name": {Type: schema.TypeString, Optional: true},
+	"old_name": {Type: schema.TypeString, Optional: true, ConflictsWith: []string{"new_name"}},
+}
+
+func TestResolveRenamedField(t *testing.T) {
+	cases := map[string]struct {
+		Config   map[string]interface{}
+		Expected string
+	}{
+		"alias set": {
+			Config:   map[string]interface{}{"old_name": "foo"},
+			Expected: "foo",
+		},
+		"field set": {
+			Config:   map[string]interface{}{"new_name": "foo"},
+			Expected: "foo",
+		},
+		"neither set": {
+			Config:   map[string]interface{}{},
+			Expected: "",
+		},
+	}
+
+	for tn, tc := range cases {
+		d := schema.TestResourceDataRaw(t, renamedFieldSchema, tc.Config)
+		if err := ResolveRenamedField(d, "new_name", "old_name"); err != nil {
+			t.Errorf("bad: %s, unexpected error: %s", tn, err)
+			continue
+		}
+		if got := d.Get("new_name"); got != tc.Expected {
+			t.Errorf("bad: %s, expected new_name to be %q, got %q", tn, tc.Expected, got)
+		}
+	}
+}
+
+func TestSetRenamedField(t *testing.T) {
+	cases := map[string]struct {
+		Config   map[string]interface{}
+		Expected map[string]interface{}
+	}{
+		"alias set": {
+			Config:   map[string]interface{}{"old_name": "foo"},
+			Expected: map[string]interface{}{"new_name": "", "old_name": "bar"},
+		},
+		"field set": {
+			Config:   map[string]interface{}{"new_name": "foo"},
+			Expected: map[string]interface{}{"new_name": "bar", "old_name": ""},
+		},
+		"neither set": {
+			Config:   map[string]interface{}{},
+			Expected: map[string]interface{}{"new_name": "bar", "old_name": ""},
+		},
+	}
+
+	for tn, tc := range cases {
+		d := schema.TestResourceDataRaw(t, renamedFieldSchema, tc.Config)
+		if err := SetRenamedField(d, "new_name", "old_name", "bar"); err != nil {
+			t.Errorf("bad: %s, unexpected error: %s", tn, err)
+			continue
+		}
+		for k, v := range tc.Expected {
+			if got := d.Get(k); got != v {
+				t.Errorf("bad: %s, expected %s to be %q, got %q", tn, k, v, got)
+			}
+		}
+	}
+}
+
+func TestRawConfigSet(t *testing.T) {
+	cases := map[string]struct {
+		Value    cty.Value
+		Expected bool
+	}{
+		"null":          {Value: cty.NullVal(cty.String), Expected: false},
+		"string":        {Value: cty.StringVal("foo"), Expected: true},
+		"unknown":       {Value: cty.UnknownVal(cty.String), Expected: true},
+		"empty block":   {Value: cty.ListValEmpty(cty.EmptyObject), Expected: false},
+		"block":         {Value: cty.ListVal([]cty.Value{cty.EmptyObjectVal}), Expected: true},
+		"unknown block": {Value: cty.UnknownVal(cty.List(cty.EmptyObject)), Expected: true},
+	}
+
+	for tn, tc := range cases {
+		if got := rawConfigSet(tc.Value); got != tc.Expected {
+			t.Errorf("bad: %s, expected %t, got %t", tn, tc.Expected, got)
+		}
+	}
+}
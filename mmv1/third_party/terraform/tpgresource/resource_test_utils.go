@@ -219,3 +219,52 @@ func GetResourceAttributes(n string, s *terraform.State) (map[string]string, err
 
 	return rs.Primary.Attributes, nil
 }
+
+// CheckMutexKey checks that a resource's mutex template, such as the
+// ComputeRouterNatMutex constant generated from the `mutex` of
+// ComputeRouterNat, builds the expected lock key from the given field values.
+// It also fails if the template references a field that isn't in the
+// resource's schema or that resolves to an empty value, as resources would
+// then share a lock they shouldn't, or not share one they should.
+func CheckMutexKey(t *testing.T, r *schema.Resource, config *transport_tpg.Config, mutex string, fields map[string]interface{}, expected string) {
+	t.Helper()
+	d := SetupTestResourceDataFromConfigMap(t, r.Schema, fields)
+
+	key, problems := mutexKey(r, d, config, mutex)
+	for _, p := range problems {
+		t.Error(p)
+	}
+	if key != expected {
+		t.Errorf("expected mutex %q to build lock key %q, got %q", mutex, expected, key)
+	}
+}
+
+// Returns the lock key the mutex builds for d, along with the problems with
+// the fields the mutex references.
+func mutexKey(r *schema.Resource, d TerraformResourceData, config *transport_tpg.Config, mutex string) (string, []string) {
+	var problems []string
+	re := regexp.MustCompile("{{([%[:word:]]+)}}")
+	for _, m := range re.FindAllStringSubmatch(mutex, -1) {
+		field := strings.TrimPrefix(m[1], "%")
+		switch field {
+		case "project", "project_id_or_project", "region", "zone":
+		default:
+			if _, ok := r.Schema[field]; !ok {
+				problems = append(problems, fmt.Sprintf("mutex %q references %q, which is not a field of the resource", mutex, field))
+				continue
+			}
+		}
+		v, err := ReplaceVars(d, config, m[0])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("unable to resolve %q in mutex %q: %s", m[0], mutex, err))
+		} else if v == "" {
+			problems = append(problems, fmt.Sprintf("%q in mutex %q resolves to an empty value", m[0], mutex))
+		}
+	}
+
+	key, err := ReplaceVars(d, config, mutex)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("unable to build lock key from mutex %q: %s", mutex, err))
+	}
+	return key, problems
+}
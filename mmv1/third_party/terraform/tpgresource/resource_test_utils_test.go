@@ -0,0 +1,70 @@
+package tpgresource
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestMutexKey(t *testing.T) {
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":   {Type: schema.TypeString, Required: true},
+			"router": {Type: schema.TypeString, Required: true},
+			"region": {Type: schema.TypeString, Optional: true},
+		},
+	}
+	config := &transport_tpg.Config{Project: "my-project", Region: "us-central1"}
+
+	cases := map[string]struct {
+		Mutex            string
+		Config           map[string]interface{}
+		ExpectedKey      string
+		ExpectedProblems []string
+	}{
+		"fields and provider region": {
+			Mutex:       "router/{{region}}/{{router}}",
+			Config:      map[string]interface{}{"name": "my-nat", "router": "my-router"},
+			ExpectedKey: "router/us-central1/my-router",
+		},
+		"region field overrides the provider region": {
+			Mutex:       "router/{{region}}/{{router}}",
+			Config:      map[string]interface{}{"name": "my-nat", "router": "my-router", "region": "europe-west1"},
+			ExpectedKey: "router/europe-west1/my-router",
+		},
+		"provider project and escaped field": {
+			Mutex:       "apps/{{project}}/{{%name}}",
+			Config:      map[string]interface{}{"name": "my/nat", "router": "my-router"},
+			ExpectedKey: "apps/my-project/my%2Fnat",
+		},
+		"unknown field": {
+			Mutex:       "router/{{region}}/{{routers}}",
+			Config:      map[string]interface{}{"name": "my-nat", "router": "my-router"},
+			ExpectedKey: "router/us-central1/",
+			ExpectedProblems: []string{
+				`mutex "router/{{region}}/{{routers}}" references "routers", which is not a field of the resource`,
+			},
+		},
+		"empty field": {
+			Mutex:       "router/{{region}}/{{router}}",
+			Config:      map[string]interface{}{"name": "my-nat"},
+			ExpectedKey: "router/us-central1/",
+			ExpectedProblems: []string{
+				`"{{router}}" in mutex "router/{{region}}/{{router}}" resolves to an empty value`,
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		d := schema.TestResourceDataRaw(t, r.Schema, tc.Config)
+		key, problems := mutexKey(r, d, config, tc.Mutex)
+		if key != tc.ExpectedKey {
+			t.Errorf("bad: %s, expected lock key %q, got %q", tn, tc.ExpectedKey, key)
+		}
+		if !reflect.DeepEqual(problems, tc.ExpectedProblems) {
+			t.Errorf("bad: %s, expected problems %q, got %q", tn, tc.ExpectedProblems, problems)
+		}
+	}
+}
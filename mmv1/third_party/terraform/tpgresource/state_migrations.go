@@ -0,0 +1,127 @@
+package tpgresource
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Helpers used by generated state upgraders. They operate on the raw JSON
+// state passed to a schema.StateUpgrader, where nested blocks are stored as
+// lists of maps. Field paths are dot-separated Terraform field names, such
+// as "settings.ip_configuration", and are applied to every element of the
+// lists they go through.
+
+// RenameStateField moves the value stored under the path `from` to the
+// sibling field `to`. Nothing is changed if the field is not set.
+func RenameStateField(rawState map[string]interface{}, from, to string) {
+	parent, name := splitStatePath(from)
+	for _, obj := range stateObjectsAtPath(rawState, parent) {
+		v, ok := obj[name]
+		if !ok {
+			continue
+		}
+		delete(obj, name)
+		obj[to] = v
+	}
+}
+
+// ScalarToListStateField wraps the scalar value stored under the path in a
+// list with a single element. Unset and null values become an empty list.
+func ScalarToListStateField(rawState map[string]interface{}, path string) {
+	parent, name := splitStatePath(path)
+	for _, obj := range stateObjectsAtPath(rawState, parent) {
+		v, ok := obj[name]
+		if !ok {
+			continue
+		}
+		switch v.(type) {
+		case nil:
+			obj[name] = []interface{}{}
+		case []interface{}:
+			// Already a list, nothing to do.
+		default:
+			obj[name] = []interface{}{v}
+		}
+	}
+}
+
+// FlattenStateObject moves the fields of the nested block stored under the
+// path to its parent, and removes the block. This matches a NestedObject
+// that is flattened into its parent with `flatten_object`.
+func FlattenStateObject(rawState map[string]interface{}, path string) {
+	parent, name := splitStatePath(path)
+	for _, obj := range stateObjectsAtPath(rawState, parent) {
+		v, ok := obj[name]
+		if !ok {
+			continue
+		}
+		delete(obj, name)
+
+		l, ok := v.([]interface{})
+		if !ok || len(l) == 0 {
+			continue
+		}
+		nested, ok := l[0].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for k, nv := range nested {
+			obj[k] = nv
+		}
+	}
+}
+
+// StateIdFromFormat builds a resource id from the top-level fields in the
+// state, replacing every {{field}} in the format with its value.
+func StateIdFromFormat(rawState map[string]interface{}, idFormat string) (string, error) {
+	re := regexp.MustCompile("{{([[:word:]]+)}}")
+	var err error
+	id := re.ReplaceAllStringFunc(idFormat, func(m string) string {
+		field := re.FindStringSubmatch(m)[1]
+		v, ok := rawState[field]
+		if !ok || v == nil || v == "" {
+			err = fmt.Errorf("field %q is not set in state, can't build id from %q", field, idFormat)
+			return ""
+		}
+		return fmt.Sprintf("%v", v)
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func splitStatePath(path string) (string, string) {
+	i := strings.LastIndex(path, ".")
+	if i < 0 {
+		return "", path
+	}
+	return path[:i], path[i+1:]
+}
+
+// Returns every object found under the path, descending into each element
+// of the nested blocks it goes through.
+func stateObjectsAtPath(rawState map[string]interface{}, path string) []map[string]interface{} {
+	objs := []map[string]interface{}{rawState}
+	if path == "" {
+		return objs
+	}
+
+	for _, name := range strings.Split(path, ".") {
+		var next []map[string]interface{}
+		for _, obj := range objs {
+			l, ok := obj[name].([]interface{})
+			if !ok {
+				continue
+			}
+			for _, raw := range l {
+				if m, ok := raw.(map[string]interface{}); ok {
+					next = append(next, m)
+				}
+			}
+		}
+		objs = next
+	}
+	return objs
+}
@@ -0,0 +1,156 @@
+package tpgresource
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenameStateField(t *testing.T) {
+	cases := map[string]struct {
+		From     string
+		To       string
+		State    map[string]interface{}
+		Expected map[string]interface{}
+	}{
+		"top-level field": {
+			From:     "old_name",
+			To:       "new_name",
+			State:    map[string]interface{}{"old_name": "foo", "other": "bar"},
+			Expected: map[string]interface{}{"new_name": "foo", "other": "bar"},
+		},
+		"unset field": {
+			From:     "old_name",
+			To:       "new_name",
+			State:    map[string]interface{}{"other": "bar"},
+			Expected: map[string]interface{}{"other": "bar"},
+		},
+		"nested field in every element": {
+			From: "rules.old_name",
+			To:   "new_name",
+			State: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{"old_name": "a"},
+					map[string]interface{}{"old_name": "b"},
+				},
+			},
+			Expected: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{"new_name": "a"},
+					map[string]interface{}{"new_name": "b"},
+				},
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		RenameStateField(tc.State, tc.From, tc.To)
+		if !reflect.DeepEqual(tc.State, tc.Expected) {
+			t.Errorf("bad: %s, expected %#v, got %#v", tn, tc.Expected, tc.State)
+		}
+	}
+}
+
+func TestScalarToListStateField(t *testing.T) {
+	cases := map[string]struct {
+		Path     string
+		State    map[string]interface{}
+		Expected map[string]interface{}
+	}{
+		"scalar": {
+			Path:     "host",
+			State:    map[string]interface{}{"host": "foo"},
+			Expected: map[string]interface{}{"host": []interface{}{"foo"}},
+		},
+		"null": {
+			Path:     "host",
+			State:    map[string]interface{}{"host": nil},
+			Expected: map[string]interface{}{"host": []interface{}{}},
+		},
+		"already a list": {
+			Path:     "host",
+			State:    map[string]interface{}{"host": []interface{}{"foo"}},
+			Expected: map[string]interface{}{"host": []interface{}{"foo"}},
+		},
+		"nested": {
+			Path: "config.host",
+			State: map[string]interface{}{
+				"config": []interface{}{map[string]interface{}{"host": "foo"}},
+			},
+			Expected: map[string]interface{}{
+				"config": []interface{}{map[string]interface{}{"host": []interface{}{"foo"}}},
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		ScalarToListStateField(tc.State, tc.Path)
+		if !reflect.DeepEqual(tc.State, tc.Expected) {
+			t.Errorf("bad: %s, expected %#v, got %#v", tn, tc.Expected, tc.State)
+		}
+	}
+}
+
+func TestFlattenStateObject(t *testing.T) {
+	cases := map[string]struct {
+		Path     string
+		State    map[string]interface{}
+		Expected map[string]interface{}
+	}{
+		"flattens the first element": {
+			Path: "settings",
+			State: map[string]interface{}{
+				"name":     "foo",
+				"settings": []interface{}{map[string]interface{}{"size": 10, "tier": "basic"}},
+			},
+			Expected: map[string]interface{}{"name": "foo", "size": 10, "tier": "basic"},
+		},
+		"empty block is removed": {
+			Path:     "settings",
+			State:    map[string]interface{}{"name": "foo", "settings": []interface{}{}},
+			Expected: map[string]interface{}{"name": "foo"},
+		},
+		"unset block": {
+			Path:     "settings",
+			State:    map[string]interface{}{"name": "foo"},
+			Expected: map[string]interface{}{"name": "foo"},
+		},
+	}
+
+	for tn, tc := range cases {
+		FlattenStateObject(tc.State, tc.Path)
+		if !reflect.DeepEqual(tc.State, tc.Expected) {
+			t.Errorf("bad: %s, expected %#v, got %#v", tn, tc.Expected, tc.State)
+		}
+	}
+}
+
+func TestStateIdFromFormat(t *testing.T) {
+	cases := map[string]struct {
+		Format        string
+		State         map[string]interface{}
+		Expected      string
+		ExpectedError bool
+	}{
+		"all fields set": {
+			Format:   "projects/{{project}}/locations/{{location}}/things/{{name}}",
+			State:    map[string]interface{}{"project": "p", "location": "global", "name": "n"},
+			Expected: "projects/p/locations/global/things/n",
+		},
+		"missing field": {
+			Format:        "projects/{{project}}/things/{{name}}",
+			State:         map[string]interface{}{"name": "n"},
+			ExpectedError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		id, err := StateIdFromFormat(tc.State, tc.Format)
+		if (err != nil) != tc.ExpectedError {
+			t.Errorf("bad: %s, expected error %t, got %v", tn, tc.ExpectedError, err)
+			continue
+		}
+		if id != tc.Expected {
+			t.Errorf("bad: %s, expected %q, got %q", tn, tc.Expected, id)
+		}
+	}
+}
@@ -483,39 +483,24 @@ func ServiceAccountFQN(serviceAccount string, d TerraformResourceData, config *t
 	return fmt.Sprintf("projects/-/serviceAccounts/%s@%s.iam.gserviceaccount.com", serviceAccount, project), nil
 }
 
+// PaginatedListRequest lists all pages of baseUrl and returns the items that
+// flattener reads from each page's response.
 func PaginatedListRequest(project, baseUrl, userAgent string, config *transport_tpg.Config, flattener func(map[string]interface{}) []interface{}) ([]interface{}, error) {
-	res, err := transport_tpg.SendRequest(transport_tpg.SendRequestOptions{
+	ls := make([]interface{}, 0)
+	err := transport_tpg.ListPages(transport_tpg.SendRequestOptions{
 		Config:    config,
 		Method:    "GET",
 		Project:   project,
 		RawURL:    baseUrl,
 		UserAgent: userAgent,
+	}, "", func(page *transport_tpg.ListPage) error {
+		ls = append(ls, flattener(page.Response)...)
+		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	ls := flattener(res)
-	pageToken, ok := res["pageToken"]
-	for ok {
-		if pageToken.(string) == "" {
-			break
-		}
-		url := fmt.Sprintf("%s?pageToken=%s", baseUrl, pageToken.(string))
-		res, err = transport_tpg.SendRequest(transport_tpg.SendRequestOptions{
-			Config:    config,
-			Method:    "GET",
-			Project:   project,
-			RawURL:    url,
-			UserAgent: userAgent,
-		})
-		if err != nil {
-			return nil, err
-		}
-		ls = append(ls, flattener(res))
-		pageToken, ok = res["pageToken"]
-	}
-
 	return ls, nil
 }
 
@@ -0,0 +1,237 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Responses larger than this are not parsed for an operation name.
+const maxAuditLogParsedResponseBytes = 1 << 20
+
+// auditLogEntry is a single line of the audit log, describing one API call.
+// Request and response bodies are never logged, only their sizes.
+type auditLogEntry struct {
+	Time            string `json:"time"`
+	Method          string `json:"method"`
+	URL             string `json:"url"`
+	URLTemplate     string `json:"url_template"`
+	ResourceAddress string `json:"resource_address,omitempty"`
+	Status          int    `json:"status,omitempty"`
+	Error           string `json:"error,omitempty"`
+	LatencyMs       int64  `json:"latency_ms"`
+	Retries         int    `json:"retries"`
+	Operation       string `json:"operation,omitempty"`
+	RequestBytes    int64  `json:"request_bytes"`
+	ResponseBytes   int64  `json:"response_bytes"`
+}
+
+type auditLogContextKey string
+
+const (
+	auditLogResourceKey auditLogContextKey = "resource"
+	auditLogRetriesKey  auditLogContextKey = "retries"
+)
+
+// ContextWithAuditResource returns a context recording the address of the
+// Terraform resource a request is made for, e.g. "google_pubsub_topic.projects/p/topics/t".
+// Requests made with this context are attributed to the resource in the audit log.
+func ContextWithAuditResource(ctx context.Context, address string) context.Context {
+	return context.WithValue(ctx, auditLogResourceKey, address)
+}
+
+// AuditResourceAddress returns the address recorded in the audit log for a
+// resource of the given type and ID, e.g. "google_pubsub_topic.projects/p/topics/t",
+// or just the type if the resource has no ID yet.
+func AuditResourceAddress(resourceType, id string) string {
+	if id == "" {
+		return resourceType
+	}
+	return resourceType + "." + id
+}
+
+// recordAuditRetry counts a retry of the request with the given context, if
+// it is recorded in an audit log.
+func recordAuditRetry(ctx context.Context) {
+	if retries, ok := ctx.Value(auditLogRetriesKey).(*int); ok {
+		*retries++
+	}
+}
+
+// auditLogWriter appends lines to an audit log file, shared by all
+// transports writing to the same path.
+type auditLogWriter struct {
+	sync.Mutex
+	w io.Writer
+}
+
+var (
+	auditLogWritersMu sync.Mutex
+	auditLogWriters   = make(map[string]*auditLogWriter)
+)
+
+func openAuditLog(path string) (*auditLogWriter, error) {
+	auditLogWritersMu.Lock()
+	defer auditLogWritersMu.Unlock()
+
+	if w, ok := auditLogWriters[path]; ok {
+		return w, nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open audit log %q: %w", path, err)
+	}
+	w := &auditLogWriter{w: f}
+	auditLogWriters[path] = w
+	return w, nil
+}
+
+func (w *auditLogWriter) write(entry *auditLogEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("[WARN] Audit Log Transport: unable to encode entry: %s", err)
+		return
+	}
+
+	w.Lock()
+	defer w.Unlock()
+	if _, err := w.w.Write(append(line, '\n')); err != nil {
+		log.Printf("[WARN] Audit Log Transport: unable to write entry: %s", err)
+	}
+}
+
+type auditLogTransport struct {
+	internal http.RoundTripper
+	log      *auditLogWriter
+}
+
+// NewTransportWithAuditLog returns a transport that appends a JSON line per API
+// call to the file at path. It should wrap the retry transport, so that each
+// line describes a request along with its retries. If path is empty, the given
+// transport is returned unchanged.
+func NewTransportWithAuditLog(internal http.RoundTripper, path string) (http.RoundTripper, error) {
+	if path == "" {
+		return internal, nil
+	}
+	if internal == nil {
+		internal = http.DefaultTransport
+	}
+
+	w, err := openAuditLog(path)
+	if err != nil {
+		return nil, err
+	}
+	return &auditLogTransport{internal: internal, log: w}, nil
+}
+
+func (t *auditLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retries := 0
+	req = req.WithContext(context.WithValue(req.Context(), auditLogRetriesKey, &retries))
+
+	entry := &auditLogEntry{
+		Method:       req.Method,
+		URL:          req.URL.String(),
+		URLTemplate:  auditLogURLTemplate(req.URL.Path),
+		RequestBytes: req.ContentLength,
+	}
+	entry.ResourceAddress, _ = req.Context().Value(auditLogResourceKey).(string)
+
+	start := time.Now()
+	entry.Time = start.UTC().Format(time.RFC3339Nano)
+	resp, err := t.internal.RoundTrip(req)
+	entry.LatencyMs = time.Since(start).Milliseconds()
+	entry.Retries = retries
+
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if resp != nil {
+		entry.Status = resp.StatusCode
+		entry.Operation, entry.ResponseBytes = auditLogOperationName(req, resp)
+	}
+	t.log.write(entry)
+
+	return resp, err
+}
+
+var auditLogVersionSegment = regexp.MustCompile(`^(v\d+((alpha|beta|p\d+beta)\d*)?|alpha|beta)$`)
+
+// auditLogURLTemplate replaces resource IDs in a REST path with `*`, so that calls
+// can be grouped by API method, e.g. /v1/projects/p/topics/t:setIamPolicy becomes
+// /v1/projects/*/topics/*:setIamPolicy. Segments after the API version are assumed
+// to alternate between collections and IDs.
+func auditLogURLTemplate(path string) string {
+	segments := strings.Split(path, "/")
+	start := -1
+	for i, s := range segments {
+		if auditLogVersionSegment.MatchString(s) {
+			start = i + 1
+			break
+		}
+	}
+	if start < 0 {
+		return path
+	}
+
+	for i := start + 1; i < len(segments); i += 2 {
+		id := segments[i]
+		method := ""
+		if j := strings.LastIndex(id, ":"); j >= 0 && i == len(segments)-1 {
+			method = id[j:]
+		}
+		segments[i] = "*" + method
+	}
+	return strings.Join(segments, "/")
+}
+
+// auditLogOperationName returns the name of the long-running operation
+// returned or polled by a call, if any, and the size of the response body if
+// it was read to find it.
+func auditLogOperationName(req *http.Request, resp *http.Response) (string, int64) {
+	if i := strings.Index(req.URL.Path, "/operations/"); i >= 0 {
+		return req.URL.Path[i+1:], resp.ContentLength
+	}
+	if resp.Body == nil || resp.ContentLength > maxAuditLogParsedResponseBytes || !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return "", resp.ContentLength
+	}
+
+	orig := resp.Body
+	body, err := ioutil.ReadAll(io.LimitReader(orig, maxAuditLogParsedResponseBytes+1))
+	if err != nil || len(body) > maxAuditLogParsedResponseBytes {
+		// Keep the whole body readable without parsing it.
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), orig), orig}
+		return "", resp.ContentLength
+	}
+	orig.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	var op struct {
+		Name     string `json:"name"`
+		Kind     string `json:"kind"`
+		SelfLink string `json:"selfLink"`
+		Done     *bool  `json:"done"`
+	}
+	if err := json.Unmarshal(body, &op); err != nil {
+		return "", int64(len(body))
+	}
+	if op.Done != nil || strings.HasSuffix(op.Kind, "#operation") || strings.Contains(op.Name, "/operations/") {
+		if op.SelfLink != "" {
+			return op.SelfLink, int64(len(body))
+		}
+		return op.Name, int64(len(body))
+	}
+	return "", int64(len(body))
+}
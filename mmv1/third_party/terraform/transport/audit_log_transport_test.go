@@ -0,0 +1,164 @@
+package transport
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuditLogURLTemplate(t *testing.T) {
+	cases := map[string]struct {
+		Path     string
+		Expected string
+	}{
+		"collection": {
+			Path:     "/v1/projects/my-project/topics",
+			Expected: "/v1/projects/*/topics",
+		},
+		"resource": {
+			Path:     "/v1/projects/my-project/topics/my-topic",
+			Expected: "/v1/projects/*/topics/*",
+		},
+		"custom method": {
+			Path:     "/v1/projects/my-project/topics/my-topic:setIamPolicy",
+			Expected: "/v1/projects/*/topics/*:setIamPolicy",
+		},
+		"service prefix": {
+			Path:     "/compute/beta/projects/my-project/zones/us-central1-a/instances/vm",
+			Expected: "/compute/beta/projects/*/zones/*/instances/*",
+		},
+		"no version": {
+			Path:     "/storage/b/my-bucket",
+			Expected: "/storage/b/my-bucket",
+		},
+	}
+
+	for tn, tc := range cases {
+		if got := auditLogURLTemplate(tc.Path); got != tc.Expected {
+			t.Errorf("bad: %s, expected %q, got %q", tn, tc.Expected, got)
+		}
+	}
+}
+
+func TestAuditResourceAddress(t *testing.T) {
+	if got := AuditResourceAddress("google_pubsub_topic", "projects/p/topics/t"); got != "google_pubsub_topic.projects/p/topics/t" {
+		t.Errorf("unexpected address %q", got)
+	}
+	if got := AuditResourceAddress("google_pubsub_topic", ""); got != "google_pubsub_topic" {
+		t.Errorf("unexpected address %q", got)
+	}
+}
+
+func TestNewTransportWithAuditLog_noPath(t *testing.T) {
+	internal := &http.Transport{}
+	rt, err := NewTransportWithAuditLog(internal, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if rt != internal {
+		t.Errorf("expected the transport to be returned unchanged")
+	}
+}
+
+func readAuditLog(t *testing.T, path string) []auditLogEntry {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unable to open audit log: %s", err)
+	}
+	defer f.Close()
+
+	var entries []auditLogEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry auditLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid audit log line %q: %s", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAuditLogTransport(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/projects/p/topics/t" && calls == 1:
+			w.WriteHeader(testRetryTransportCodeRetry)
+			w.Write([]byte(`{"error": {"code": 500, "message": "retry"}}`))
+		case r.URL.Path == "/v1/projects/p/topics/t":
+			w.Write([]byte(`{"name": "projects/p/operations/op-1", "done": false}`))
+		default:
+			w.Write([]byte(`{"name": "projects/p/topics/other"}`))
+		}
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	retryTransport := &retryTransport{
+		retryPredicates: []RetryErrorPredicateFunc{testRetryTransportRetryPredicate},
+		internal:        http.DefaultTransport,
+		jitter:          func(time.Duration) time.Duration { return 0 },
+	}
+	rt, err := NewTransportWithAuditLog(retryTransport, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	client := &http.Client{Transport: rt}
+
+	for _, u := range []string{"/v1/projects/p/topics/t", "/v1/projects/p/topics/other"} {
+		req, err := http.NewRequest("POST", ts.URL+u, strings.NewReader(`{"labels": {}}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		req = req.WithContext(ContextWithAuditResource(req.Context(), "google_pubsub_topic.t"))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		// The response body must still be readable after the audit log parses it.
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil || !json.Valid(body) {
+			t.Errorf("expected a readable JSON response body, got %q (%v)", body, err)
+		}
+	}
+
+	entries := readAuditLog(t, path)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit log entries, got %d", len(entries))
+	}
+
+	first := entries[0]
+	if first.Method != "POST" || first.Status != http.StatusOK || first.Retries != 1 {
+		t.Errorf("expected a successful POST with 1 retry, got %+v", first)
+	}
+	if first.URLTemplate != "/v1/projects/*/topics/*" {
+		t.Errorf("unexpected url template %q", first.URLTemplate)
+	}
+	if first.ResourceAddress != "google_pubsub_topic.t" {
+		t.Errorf("unexpected resource address %q", first.ResourceAddress)
+	}
+	if first.Operation != "projects/p/operations/op-1" {
+		t.Errorf("unexpected operation %q", first.Operation)
+	}
+	if first.RequestBytes != int64(len(`{"labels": {}}`)) || first.ResponseBytes <= 0 {
+		t.Errorf("unexpected body sizes in %+v", first)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, first.Time); err != nil {
+		t.Errorf("unexpected time %q: %s", first.Time, err)
+	}
+
+	if second := entries[1]; second.Retries != 0 || second.Operation != "" {
+		t.Errorf("expected no retries and no operation, got %+v", second)
+	}
+}
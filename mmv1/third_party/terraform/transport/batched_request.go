@@ -0,0 +1,98 @@
+package transport
+
+import (
+	"fmt"
+)
+
+// SendBatchedRequest sends a request through batcher, combining it with the
+// requests sent concurrently to the same URL with the same method. Bodies are
+// combined by concatenating the items of their combineField list, and each
+// combined request returns the response to the batch.
+func SendBatchedRequest(batcher *RequestBatcher, combineField string, opt SendRequestOptions) (map[string]interface{}, error) {
+	if batcher == nil {
+		return SendRequest(opt)
+	}
+
+	timeout := opt.Timeout
+	if timeout == 0 {
+		timeout = DefaultRequestTimeout
+	}
+
+	request := &BatchRequest{
+		ResourceName: opt.RawURL,
+		Body:         opt.Body,
+		CombineF:     CombineListFieldBodies(combineField),
+		SendF: func(_ string, body interface{}) (interface{}, error) {
+			batchOpt := opt
+			batchOpt.Body = body.(map[string]interface{})
+			return SendRequest(batchOpt)
+		},
+		DebugId: fmt.Sprintf("%s %s", opt.Method, opt.RawURL),
+	}
+
+	res, err := batcher.SendRequestWithTimeout(fmt.Sprintf("%s %s", opt.Method, opt.RawURL), request, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(map[string]interface{}), nil
+}
+
+// CombineListFieldBodies returns a BatcherCombineFunc combining request bodies
+// by concatenating the items of their field list. The other fields of the
+// bodies are expected to be equal, and are kept from the first body.
+func CombineListFieldBodies(field string) BatcherCombineFunc {
+	return func(currV interface{}, toAddV interface{}) (interface{}, error) {
+		curr, ok := currV.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("provider error in batch combiner: expected data to be type map[string]interface{}, got %v with type %T", currV, currV)
+		}
+		toAdd, ok := toAddV.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("provider error in batch combiner: expected data to be type map[string]interface{}, got %v with type %T", toAddV, toAddV)
+		}
+
+		currItems, err := listFieldItems(curr, field)
+		if err != nil {
+			return nil, err
+		}
+		toAddItems, err := listFieldItems(toAdd, field)
+		if err != nil {
+			return nil, err
+		}
+
+		// Bodies are copied, as the body of the first request is kept to
+		// retry it alone if the batch fails.
+		combined := make(map[string]interface{}, len(curr))
+		for k, v := range curr {
+			combined[k] = v
+		}
+		combined[field] = append(append(make([]interface{}, 0, len(currItems)+len(toAddItems)), currItems...), toAddItems...)
+		return combined, nil
+	}
+}
+
+func listFieldItems(body map[string]interface{}, field string) ([]interface{}, error) {
+	switch v := body[field].(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		return v, nil
+	case []map[string]interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			items = append(items, item)
+		}
+		return items, nil
+	case []string:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			items = append(items, item)
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("provider error in batch combiner: expected field %q to be a list, got %v with type %T", field, v, v)
+	}
+}
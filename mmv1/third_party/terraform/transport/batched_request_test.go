@@ -0,0 +1,108 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// bodyRecordingTransport records the bodies of the requests it receives.
+type bodyRecordingTransport struct {
+	sync.Mutex
+	bodies []map[string]interface{}
+}
+
+func (t *bodyRecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body map[string]interface{}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	t.Lock()
+	t.bodies = append(t.bodies, body)
+	t.Unlock()
+	return faultResponse(req, 200, nil, []byte(`{"name": "operation-1"}`), false), nil
+}
+
+func TestSendBatchedRequest(t *testing.T) {
+	rt := &bodyRecordingTransport{}
+	config := &Config{Client: &http.Client{Transport: rt}}
+	batcher := NewRequestBatcher("test", context.Background(), &BatchingConfig{
+		SendAfter:      time.Second,
+		EnableBatching: true,
+	})
+
+	wg := sync.WaitGroup{}
+	for _, policy := range []string{"a", "b", "c"} {
+		wg.Add(1)
+		go func(policy string) {
+			defer wg.Done()
+			res, err := SendBatchedRequest(batcher, "resourcePolicies", SendRequestOptions{
+				Config:  config,
+				Method:  "POST",
+				RawURL:  "https://compute.googleapis.com/compute/v1/projects/p/zones/z/disks/d/addResourcePolicies",
+				Body:    map[string]interface{}{"resourcePolicies": []interface{}{policy}},
+				Timeout: 10 * time.Second,
+			})
+			if err != nil {
+				t.Errorf("unexpected error sending policy %q: %s", policy, err)
+				return
+			}
+			if res["name"] != "operation-1" {
+				t.Errorf("expected policy %q to get the response to the batch, got %v", policy, res)
+			}
+		}(policy)
+	}
+	wg.Wait()
+
+	if len(rt.bodies) != 1 {
+		t.Fatalf("expected the requests to be sent in 1 batch, got %d requests: %v", len(rt.bodies), rt.bodies)
+	}
+	got := map[string]bool{}
+	for _, v := range rt.bodies[0]["resourcePolicies"].([]interface{}) {
+		got[v.(string)] = true
+	}
+	if !reflect.DeepEqual(got, map[string]bool{"a": true, "b": true, "c": true}) {
+		t.Fatalf("expected the batch to combine all policies, got %v", rt.bodies[0])
+	}
+}
+
+func TestCombineListFieldBodies(t *testing.T) {
+	combine := CombineListFieldBodies("networkEndpoints")
+
+	first := map[string]interface{}{
+		"networkEndpoints": []map[string]interface{}{{"port": 80}},
+		"other":            "kept",
+	}
+	combined, err := combine(first, map[string]interface{}{
+		"networkEndpoints": []interface{}{map[string]interface{}{"port": 81}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	combined, err = combine(combined, map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"networkEndpoints": []interface{}{
+			map[string]interface{}{"port": 80},
+			map[string]interface{}{"port": 81},
+		},
+		"other": "kept",
+	}
+	if !reflect.DeepEqual(combined, expected) {
+		t.Errorf("expected %v, got %v", expected, combined)
+	}
+	if len(first["networkEndpoints"].([]map[string]interface{})) != 1 {
+		t.Errorf("expected the first body to be unchanged, got %v", first)
+	}
+
+	if _, err := combine(first, map[string]interface{}{"networkEndpoints": "a"}); err == nil {
+		t.Errorf("expected an error combining a field that isn't a list")
+	}
+}
@@ -24,6 +24,35 @@ type RequestBatcher struct {
 	parentCtx context.Context
 	batches   map[string]*startedBatch
 	debugId   string
+	stats     batcherStats
+}
+
+// batcherStats summarises the batches sent by a RequestBatcher, reported in debug logs.
+type batcherStats struct {
+	batches      int
+	requests     int
+	maxBatchSize int
+
+	// Time requests spent waiting for their batch to be sent.
+	totalWait time.Duration
+	maxWait   time.Duration
+
+	// Requests sent separately after their batch failed, and how many of those failed too.
+	singleRetries       int
+	failedSingleRetries int
+}
+
+func (s batcherStats) String() string {
+	var avgSize float64
+	var avgWait time.Duration
+	if s.batches > 0 {
+		avgSize = float64(s.requests) / float64(s.batches)
+	}
+	if s.requests > 0 {
+		avgWait = s.totalWait / time.Duration(s.requests)
+	}
+	return fmt.Sprintf("%d batches combining %d requests (avg size %.1f, max %d), avg wait %s (max %s), %d single-request retries (%d failed)",
+		s.batches, s.requests, avgSize, s.maxBatchSize, avgWait, s.maxWait, s.singleRetries, s.failedSingleRetries)
 }
 
 // These types are meant to be the public interface to batchers. They define
@@ -94,6 +123,9 @@ type batchSubscriber struct {
 
 	// respCh is the channel created to communicate the result to a waiting goroutine.s
 	respCh chan batchResponse
+
+	// registeredAt is when the request was added to the batch.
+	registeredAt time.Time
 }
 
 // BatchingConfig contains user configuration for controlling batch requests.
@@ -102,6 +134,60 @@ type BatchingConfig struct {
 	EnableBatching bool
 }
 
+// Names of batchers used by handwritten resources.
+const (
+	BatcherServiceUsage = "service_usage"
+	BatcherIam          = "iam"
+)
+
+// RequestBatcherRegistry holds the named batchers of a provider. Batchers are
+// created on first use, so that each API needing batching gets its own batcher
+// with its own configuration.
+type RequestBatcherRegistry struct {
+	sync.Mutex
+
+	parentCtx     context.Context
+	defaultConfig *BatchingConfig
+	configs       map[string]*BatchingConfig
+	batchers      map[string]*RequestBatcher
+}
+
+// NewRequestBatcherRegistry initializes a registry of batchers. Batchers use the
+// config registered under their name in configs if any, or defaultConfig.
+func NewRequestBatcherRegistry(ctx context.Context, defaultConfig *BatchingConfig, configs map[string]*BatchingConfig) *RequestBatcherRegistry {
+	return &RequestBatcherRegistry{
+		parentCtx:     ctx,
+		defaultConfig: defaultConfig,
+		configs:       configs,
+		batchers:      make(map[string]*RequestBatcher),
+	}
+}
+
+// Get returns the batcher with the given name, creating it if needed.
+func (r *RequestBatcherRegistry) Get(name string) *RequestBatcher {
+	r.Lock()
+	defer r.Unlock()
+
+	if b, ok := r.batchers[name]; ok {
+		return b
+	}
+
+	config := r.defaultConfig
+	if c, ok := r.configs[name]; ok {
+		config = c
+	}
+	if config == nil {
+		config = &BatchingConfig{
+			SendAfter:      time.Second * DefaultBatchSendIntervalSec,
+			EnableBatching: true,
+		}
+	}
+	log.Printf("[DEBUG] Creating batcher %q with config %+v", name, *config)
+	b := NewRequestBatcher(name, r.parentCtx, config)
+	r.batchers[name] = b
+	return b
+}
+
 // Initializes a new batcher.
 func NewRequestBatcher(debugId string, ctx context.Context, config *BatchingConfig) *RequestBatcher {
 	batcher := &RequestBatcher{
@@ -127,7 +213,7 @@ func (b *RequestBatcher) stop() {
 	b.Lock()
 	defer b.Unlock()
 
-	log.Printf("[DEBUG] Stopping batcher %q", b.debugId)
+	log.Printf("[DEBUG] Stopping batcher %q: %s", b.debugId, b.stats)
 	for batchKey, batch := range b.batches {
 		log.Printf("[DEBUG] Cancelling started batch for batchKey %q", batchKey)
 		batch.timer.Stop()
@@ -227,6 +313,7 @@ func (b *RequestBatcher) registerBatchRequest(batchKey string, newRequest *Batch
 	sub := batchSubscriber{
 		singleRequest: newRequest,
 		respCh:        respCh,
+		registeredAt:  time.Now(),
 	}
 
 	// Create a new batch with copy of the given batch request.
@@ -259,6 +346,7 @@ func (b *RequestBatcher) registerBatchRequest(batchKey string, newRequest *Batch
 
 func (b *RequestBatcher) sendBatchWithSingleRetry(batchKey string, batch *startedBatch) {
 	log.Printf("[DEBUG] Sending batch %q combining %d requests)", batchKey, len(batch.subscribers))
+	b.recordBatch(batch)
 	resp := batch.send()
 
 	// If the batch failed and combines more than one request, retry each single request.
@@ -269,6 +357,7 @@ func (b *RequestBatcher) sendBatchWithSingleRetry(batchKey string, batch *starte
 			log.Printf("[DEBUG] Retrying single request %q", sub.singleRequest.DebugId)
 			singleResp := sub.singleRequest.send()
 			log.Printf("[DEBUG] Retried single request %q returned response: %v", sub.singleRequest.DebugId, singleResp)
+			b.recordSingleRetry(singleResp)
 
 			if singleResp.IsError() {
 				singleResp.err = errwrap.Wrapf(
@@ -287,6 +376,39 @@ func (b *RequestBatcher) sendBatchWithSingleRetry(batchKey string, batch *starte
 	}
 }
 
+// recordBatch updates the batcher's stats with a batch about to be sent.
+func (b *RequestBatcher) recordBatch(batch *startedBatch) {
+	b.Lock()
+	defer b.Unlock()
+
+	now := time.Now()
+	size := len(batch.subscribers)
+	b.stats.batches++
+	b.stats.requests += size
+	if size > b.stats.maxBatchSize {
+		b.stats.maxBatchSize = size
+	}
+	for _, sub := range batch.subscribers {
+		wait := now.Sub(sub.registeredAt)
+		b.stats.totalWait += wait
+		if wait > b.stats.maxWait {
+			b.stats.maxWait = wait
+		}
+	}
+	log.Printf("[DEBUG] Batcher %q: %s", b.debugId, b.stats)
+}
+
+// recordSingleRetry updates the batcher's stats with a request retried alone after its batch failed.
+func (b *RequestBatcher) recordSingleRetry(resp batchResponse) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.stats.singleRetries++
+	if resp.IsError() {
+		b.stats.failedSingleRetries++
+	}
+}
+
 // popBatch safely gets and removes a batch with given batchkey from the
 // RequestBatcher's started batches.
 func (b *RequestBatcher) popBatch(batchKey string) *startedBatch {
@@ -320,6 +442,7 @@ func (batch *startedBatch) addRequest(newRequest *BatchRequest) (<-chan batchRes
 	sub := batchSubscriber{
 		singleRequest: newRequest,
 		respCh:        respCh,
+		registeredAt:  time.Now(),
 	}
 	batch.subscribers = append(batch.subscribers, sub)
 	return respCh, nil
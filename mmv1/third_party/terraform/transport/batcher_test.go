@@ -198,6 +198,45 @@ func TestRequestBatcher_errInSend(t *testing.T) {
 	}
 
 	wg.Wait()
+
+	testBatcher.Lock()
+	stats := testBatcher.stats
+	testBatcher.Unlock()
+	if stats.batches != 1 || stats.requests != numRequests || stats.maxBatchSize != numRequests {
+		t.Errorf("expected a single batch of %d requests, got stats: %s", numRequests, stats)
+	}
+	if stats.singleRetries != numRequests || stats.failedSingleRetries != 1 {
+		t.Errorf("expected %d single-request retries with 1 failure, got stats: %s", numRequests, stats)
+	}
+}
+
+func TestRequestBatcherRegistry(t *testing.T) {
+	defaultConfig := &BatchingConfig{
+		SendAfter:      time.Second,
+		EnableBatching: true,
+	}
+	registry := NewRequestBatcherRegistry(context.Background(), defaultConfig, map[string]*BatchingConfig{
+		"compute_firewall": {
+			SendAfter:      5 * time.Second,
+			EnableBatching: false,
+		},
+	})
+
+	iam := registry.Get(BatcherIam)
+	if iam != registry.Get(BatcherIam) {
+		t.Errorf("expected the same batcher to be returned for the same name")
+	}
+	if iam.SendAfter != time.Second || !iam.EnableBatching {
+		t.Errorf("expected batcher %q to use the default config, got %+v", BatcherIam, *iam.BatchingConfig)
+	}
+
+	firewall := registry.Get("compute_firewall")
+	if firewall == iam {
+		t.Errorf("expected different batchers for different names")
+	}
+	if firewall.SendAfter != 5*time.Second || firewall.EnableBatching {
+		t.Errorf("expected batcher %q to use its own config, got %+v", "compute_firewall", *firewall.BatchingConfig)
+	}
 }
 
 func TestRequestBatcher_errTimeout(t *testing.T) {
@@ -469,6 +469,189 @@ func TestConfigLoadAndValidate_customBatchingConfig(t *testing.T) {
 	}
 }
 
+func TestExpandProviderBatchingConfigs(t *testing.T) {
+	defaults := map[string]string{
+		"compute_firewall": "5s",
+		"dns_record_set":   "",
+	}
+
+	cases := map[string]struct {
+		Value    interface{}
+		Expected map[string]transport_tpg.BatchingConfig
+	}{
+		"unset uses the defaults of generated batchers": {
+			Value: nil,
+			Expected: map[string]transport_tpg.BatchingConfig{
+				"compute_firewall": {SendAfter: 5 * time.Second, EnableBatching: true},
+				"dns_record_set":   {SendAfter: transport_tpg.DefaultBatchSendIntervalSec * time.Second, EnableBatching: true},
+			},
+		},
+		"provider send_after overrides the defaults of generated batchers": {
+			Value: []interface{}{
+				map[string]interface{}{
+					"send_after":      "1s",
+					"enable_batching": true,
+				},
+			},
+			Expected: map[string]transport_tpg.BatchingConfig{
+				"compute_firewall": {SendAfter: time.Second, EnableBatching: true},
+				"dns_record_set":   {SendAfter: time.Second, EnableBatching: true},
+			},
+		},
+		"batcher blocks override the provider settings": {
+			Value: []interface{}{
+				map[string]interface{}{
+					"send_after":      "1s",
+					"enable_batching": true,
+					"batcher": []interface{}{
+						map[string]interface{}{
+							"name":            "compute_firewall",
+							"send_after":      "10s",
+							"enable_batching": true,
+						},
+						map[string]interface{}{
+							"name":            transport_tpg.BatcherIam,
+							"send_after":      "",
+							"enable_batching": false,
+						},
+					},
+				},
+			},
+			Expected: map[string]transport_tpg.BatchingConfig{
+				"compute_firewall":       {SendAfter: 10 * time.Second, EnableBatching: true},
+				"dns_record_set":         {SendAfter: time.Second, EnableBatching: true},
+				transport_tpg.BatcherIam: {SendAfter: time.Second, EnableBatching: false},
+			},
+		},
+		"batcher blocks keep the defaults of unset fields": {
+			Value: []interface{}{
+				map[string]interface{}{
+					"enable_batching": true,
+					"batcher": []interface{}{
+						map[string]interface{}{
+							"name":            "compute_firewall",
+							"send_after":      "",
+							"enable_batching": false,
+						},
+					},
+				},
+			},
+			Expected: map[string]transport_tpg.BatchingConfig{
+				"compute_firewall": {SendAfter: 5 * time.Second, EnableBatching: false},
+				"dns_record_set":   {SendAfter: transport_tpg.DefaultBatchSendIntervalSec * time.Second, EnableBatching: true},
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		configs, err := transport_tpg.ExpandProviderBatchingConfigs(tc.Value, defaults)
+		if err != nil {
+			t.Fatalf("bad: %s, unexpected error: %v", tn, err)
+		}
+		if len(configs) != len(tc.Expected) {
+			t.Fatalf("bad: %s, expected %d batcher configs, got %d", tn, len(tc.Expected), len(configs))
+		}
+		for name, expected := range tc.Expected {
+			if got, ok := configs[name]; !ok || *got != expected {
+				t.Errorf("bad: %s, expected batcher %q config %+v, got %+v", tn, name, expected, got)
+			}
+		}
+	}
+
+	config := &transport_tpg.Config{
+		Credentials: transport_tpg.TestFakeCredentialsPath,
+		Project:     "my-gce-project",
+		Region:      "us-central1",
+		BatchingConfigs: map[string]*transport_tpg.BatchingConfig{
+			transport_tpg.BatcherIam: {SendAfter: 7 * time.Second, EnableBatching: true},
+		},
+	}
+	if err := config.LoadAndValidate(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.RequestBatcherIam != config.RequestBatcher(transport_tpg.BatcherIam) {
+		t.Fatalf("expected RequestBatcherIam to be registered as %q", transport_tpg.BatcherIam)
+	}
+	if config.RequestBatcherIam.SendAfter != 7*time.Second {
+		t.Fatalf("expected RequestBatcherIam SendAfter to be 7 seconds, got %v", config.RequestBatcherIam.SendAfter)
+	}
+}
+
+func TestConfigLoadAndValidate_rateLimitConfig(t *testing.T) {
+	rateLimitCfg, err := transport_tpg.ExpandProviderRateLimitConfig([]interface{}{
+		map[string]interface{}{
+			"requests_per_second": 5.0,
+			"burst":               2,
+			"per_method":          true,
+			"host_requests_per_second": map[string]interface{}{
+				"compute.googleapis.com": 1.5,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rateLimitCfg.RequestsPerSecond != 5 || rateLimitCfg.Burst != 2 || !rateLimitCfg.PerMethod {
+		t.Fatalf("unexpected rate limit config: %+v", rateLimitCfg)
+	}
+	if rateLimitCfg.HostRequestsPerSecond["compute.googleapis.com"] != 1.5 {
+		t.Fatalf("expected compute.googleapis.com to be limited to 1.5 requests per second, got %v", rateLimitCfg.HostRequestsPerSecond)
+	}
+
+	config := &transport_tpg.Config{
+		Credentials:     transport_tpg.TestFakeCredentialsPath,
+		Project:         "my-gce-project",
+		Region:          "us-central1",
+		RateLimitConfig: rateLimitCfg,
+	}
+
+	err = config.LoadAndValidate(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestExpandProviderRateLimitConfig(t *testing.T) {
+	cases := map[string]struct {
+		Value       interface{}
+		ExpectNil   bool
+		ExpectError bool
+	}{
+		"unset": {
+			Value:     nil,
+			ExpectNil: true,
+		},
+		"empty block": {
+			Value:     []interface{}{nil},
+			ExpectNil: true,
+		},
+		"negative requests_per_second": {
+			Value: []interface{}{
+				map[string]interface{}{"requests_per_second": -1.0},
+			},
+			ExpectError: true,
+		},
+		"zero host_requests_per_second": {
+			Value: []interface{}{
+				map[string]interface{}{
+					"host_requests_per_second": map[string]interface{}{"iam.googleapis.com": 0.0},
+				},
+			},
+			ExpectError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		cfg, err := transport_tpg.ExpandProviderRateLimitConfig(tc.Value)
+		if (err != nil) != tc.ExpectError {
+			t.Fatalf("bad: %s, expected error: %t, got: %v", tn, tc.ExpectError, err)
+		}
+		if tc.ExpectNil && cfg != nil {
+			t.Fatalf("bad: %s, expected no rate limit config, got %+v", tn, cfg)
+		}
+	}
+}
+
 func TestRemoveBasePathVersion(t *testing.T) {
 	cases := []struct {
 		BaseURL  string
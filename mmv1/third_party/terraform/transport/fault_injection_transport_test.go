@@ -0,0 +1,271 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// injectedFault describes how faultInjectionTransport fails matching requests.
+type injectedFault struct {
+	// HTTP method of the requests to fail. Matches any method if empty.
+	Method string
+
+	// Regular expression matched against the URL of the requests to fail.
+	// Matches any URL if empty.
+	URL string
+
+	// Number of matching requests to fail. Defaults to 1; -1 fails all matching
+	// requests.
+	Count int
+
+	// Wait before failing the request. If the request's context is done first,
+	// its error is returned instead.
+	Delay time.Duration
+
+	// Fail with a connection reset by peer, before any response is read.
+	ConnectionReset bool
+
+	// Fail with a network timeout, before any response is read.
+	Timeout bool
+
+	// HTTP status of the response. Defaults to 200 for truncated bodies and is
+	// otherwise required unless the request fails with a network error.
+	Status int
+
+	// Reason and message of the Google API error in the response body. The
+	// reason is set on both the legacy `errors` list and a google.rpc.ErrorInfo
+	// detail. The message defaults to the status text.
+	Reason  string
+	Message string
+
+	// Additional headers of the response, such as Retry-After.
+	Header http.Header
+
+	// Cut the response body short, so that reading it fails with an unexpected
+	// EOF as if the connection dropped mid-response.
+	TruncateBody bool
+
+	url *regexp.Regexp
+}
+
+// faultInjectionTransport is an http.RoundTripper that fails requests matching
+// its faults, and sends the others to an internal transport. Faults are
+// matched in the order they were injected; each request fails with at most one
+// fault. If internal is nil, requests without a fault succeed with an empty
+// JSON object.
+type faultInjectionTransport struct {
+	internal http.RoundTripper
+
+	mu       sync.Mutex
+	faults   []*injectedFault
+	attempts int
+}
+
+func newFaultInjectionTransport(internal http.RoundTripper, faults ...injectedFault) *faultInjectionTransport {
+	t := &faultInjectionTransport{internal: internal}
+	for _, f := range faults {
+		t.Inject(f)
+	}
+	return t
+}
+
+// Inject makes the requests matching f fail.
+func (t *faultInjectionTransport) Inject(f injectedFault) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if f.Count == 0 {
+		f.Count = 1
+	}
+	if f.URL != "" {
+		f.url = regexp.MustCompile(f.URL)
+	}
+	t.faults = append(t.faults, &f)
+}
+
+// Attempts returns the number of requests sent through the transport.
+func (t *faultInjectionTransport) Attempts() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.attempts
+}
+
+func (t *faultInjectionTransport) takeFault(req *http.Request) *injectedFault {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.attempts++
+	for i, f := range t.faults {
+		if f.Method != "" && f.Method != req.Method {
+			continue
+		}
+		if f.url != nil && !f.url.MatchString(req.URL.String()) {
+			continue
+		}
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				t.faults = append(t.faults[:i], t.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (t *faultInjectionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		// Like a real transport, consume and close the request body.
+		io.Copy(ioutil.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	f := t.takeFault(req)
+	if f == nil {
+		if t.internal != nil {
+			return t.internal.RoundTrip(req)
+		}
+		return faultResponse(req, http.StatusOK, nil, []byte("{}"), false), nil
+	}
+
+	if f.Delay > 0 {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(f.Delay):
+		}
+	}
+
+	switch {
+	case f.ConnectionReset:
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	case f.Timeout:
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}
+	}
+
+	status := f.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	body := []byte(`{"name": "ok"}`)
+	if status >= 300 {
+		body = faultErrorBody(status, f.Reason, f.Message)
+	}
+	return faultResponse(req, status, f.Header, body, f.TruncateBody), nil
+}
+
+// faultErrorBody returns the JSON body of a Google API error.
+func faultErrorBody(status int, reason, message string) []byte {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	e := map[string]interface{}{
+		"code":    status,
+		"message": message,
+	}
+	if reason != "" {
+		e["errors"] = []interface{}{
+			map[string]interface{}{"domain": "global", "reason": reason, "message": message},
+		}
+		e["details"] = []interface{}{
+			map[string]interface{}{
+				"@type":  "type.googleapis.com/google.rpc.ErrorInfo",
+				"reason": reason,
+				"domain": "googleapis.com",
+			},
+		}
+	}
+	body, _ := json.Marshal(map[string]interface{}{"error": e})
+	return body
+}
+
+func faultResponse(req *http.Request, status int, header http.Header, body []byte, truncate bool) *http.Response {
+	h := http.Header{"Content-Type": []string{"application/json"}}
+	for k, v := range header {
+		h[k] = v
+	}
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+
+	var r io.Reader = bytes.NewReader(body)
+	if truncate {
+		r = io.MultiReader(bytes.NewReader(body[:len(body)/2]), errorReader{io.ErrUnexpectedEOF})
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          ioutil.NopCloser(r),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func TestFaultInjectionTransport(t *testing.T) {
+	ft := newFaultInjectionTransport(nil,
+		injectedFault{Method: "POST", URL: "/topics/t$", Status: 409, Reason: "alreadyExists", Message: "Topic exists"},
+		injectedFault{URL: "/subscriptions/", Count: 2, Status: 503},
+	)
+	client := &http.Client{Transport: ft}
+
+	resp, err := client.Post("https://pubsub.googleapis.com/v1/projects/p/topics/t", "application/json", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	gerr, ok := googleapi.CheckResponse(resp).(*googleapi.Error)
+	if !ok || gerr.Code != 409 || gerr.Message != "Topic exists" || len(gerr.Errors) != 1 || gerr.Errors[0].Reason != "alreadyExists" {
+		t.Errorf("unexpected error %#v", gerr)
+	}
+	if len(gerr.Details) != 1 {
+		t.Errorf("expected an ErrorInfo detail, got %v", gerr.Details)
+	}
+
+	// The fault was used up, and requests without a fault succeed.
+	for _, u := range []string{"/v1/projects/p/topics/t", "/v1/projects/p/topics/other"} {
+		resp, err := client.Post("https://pubsub.googleapis.com"+u, "application/json", nil)
+		if err != nil || resp.StatusCode != 200 {
+			t.Errorf("expected %s to succeed, got %v (%v)", u, resp, err)
+		}
+	}
+
+	for i, expected := range []int{503, 503, 200} {
+		resp, err := client.Get("https://pubsub.googleapis.com/v1/projects/p/subscriptions/s")
+		if err != nil || resp.StatusCode != expected {
+			t.Errorf("bad: request %d, expected %d, got %v (%v)", i, expected, resp, err)
+		}
+	}
+	if ft.Attempts() != 6 {
+		t.Errorf("expected 6 attempts, got %d", ft.Attempts())
+	}
+
+	ft.Inject(injectedFault{ConnectionReset: true})
+	if _, err := client.Get("https://pubsub.googleapis.com/"); err == nil {
+		t.Errorf("expected a connection reset")
+	} else if ok, _ := isConnectionResetNetworkError(err); !ok {
+		t.Errorf("expected a connection reset, got %s", err)
+	}
+
+	ft.Inject(injectedFault{TruncateBody: true})
+	resp, err = client.Get("https://pubsub.googleapis.com/")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := ioutil.ReadAll(resp.Body); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected an unexpected EOF reading a truncated body, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package transport
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// ListSettings configures how list requests are paginated.
+type ListSettings struct {
+	// Name and value of the query parameter limiting the size of each page.
+	PageSizeParam string
+	PageSize      int
+}
+
+// ListWithPageSize sets the `pageSize` query parameter of each list request.
+func ListWithPageSize(size int) func(*ListSettings) {
+	return func(s *ListSettings) {
+		s.PageSizeParam = "pageSize"
+		s.PageSize = size
+	}
+}
+
+// ListWithMaxResults sets the `maxResults` query parameter of each list request,
+// used instead of `pageSize` by Compute and other older APIs.
+func ListWithMaxResults(size int) func(*ListSettings) {
+	return func(s *ListSettings) {
+		s.PageSizeParam = "maxResults"
+		s.PageSize = size
+	}
+}
+
+// ListPage is a single page of a list response.
+type ListPage struct {
+	// Items of the page. For aggregated lists, the items of all scopes.
+	Items []interface{}
+
+	// Locations that could not be reached, for which the page may be missing items.
+	Unreachable []string
+
+	// The raw response.
+	Response map[string]interface{}
+}
+
+// ListPages sends opt as a list request, following nextPageToken until all
+// pages are read, and calls f with each page. Items are read from itemsKey,
+// falling back to `items` as used by Compute APIs.
+//
+// Aggregated list responses, where items are keyed by scope such as
+// {"items": {"zones/us-central1-a": {"instances": [...]}}}, are flattened into
+// a single list of items.
+//
+// Listing stops at the first error returned by a request or by f, or if
+// opt.Context is cancelled.
+func ListPages(opt SendRequestOptions, itemsKey string, f func(*ListPage) error, options ...func(*ListSettings)) error {
+	settings := &ListSettings{}
+	for _, o := range options {
+		o(settings)
+	}
+	if opt.Method == "" {
+		opt.Method = "GET"
+	}
+
+	baseUrl := opt.RawURL
+	if settings.PageSizeParam != "" && settings.PageSize > 0 {
+		u, err := AddQueryParams(baseUrl, map[string]string{settings.PageSizeParam: strconv.Itoa(settings.PageSize)})
+		if err != nil {
+			return err
+		}
+		baseUrl = u
+	}
+
+	pageToken := ""
+	for page := 1; ; page++ {
+		if opt.Context != nil {
+			if err := opt.Context.Err(); err != nil {
+				return fmt.Errorf("listing %s stopped after %d pages: %w", opt.RawURL, page-1, err)
+			}
+		}
+
+		opt.RawURL = baseUrl
+		if pageToken != "" {
+			u, err := AddQueryParams(baseUrl, map[string]string{"pageToken": pageToken})
+			if err != nil {
+				return err
+			}
+			opt.RawURL = u
+		}
+
+		res, err := SendRequest(opt)
+		if err != nil {
+			return err
+		}
+
+		listPage, err := newListPage(res, itemsKey)
+		if err != nil {
+			return fmt.Errorf("reading page %d of %s: %w", page, opt.RawURL, err)
+		}
+		if len(listPage.Unreachable) > 0 {
+			log.Printf("[WARN] List response from %s is missing results for unreachable locations: %v", opt.RawURL, listPage.Unreachable)
+		}
+		if err := f(listPage); err != nil {
+			return err
+		}
+
+		pageToken, _ = res["nextPageToken"].(string)
+		if pageToken == "" {
+			return nil
+		}
+	}
+}
+
+// ListAll sends opt as a list request and returns the items of all pages. See ListPages.
+func ListAll(opt SendRequestOptions, itemsKey string, options ...func(*ListSettings)) ([]interface{}, error) {
+	items := make([]interface{}, 0)
+	err := ListPages(opt, itemsKey, func(page *ListPage) error {
+		items = append(items, page.Items...)
+		return nil
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func newListPage(res map[string]interface{}, itemsKey string) (*ListPage, error) {
+	page := &ListPage{
+		Items:    make([]interface{}, 0),
+		Response: res,
+	}
+
+	raw, ok := res[itemsKey]
+	if !ok {
+		raw = res["items"]
+	}
+
+	switch v := raw.(type) {
+	case nil:
+	case []interface{}:
+		page.Items = append(page.Items, v...)
+	case map[string]interface{}:
+		// Aggregated list, keyed by scope. Each scope has a list of items, or a
+		// warning if there are no items in that scope.
+		for scope, scopedV := range v {
+			scoped, ok := scopedV.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("unexpected value of type %T for scope %q of aggregated list", scopedV, scope)
+			}
+			for k, items := range scoped {
+				if k == "warning" {
+					continue
+				}
+				if ls, ok := items.([]interface{}); ok {
+					page.Items = append(page.Items, ls...)
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unexpected value of type %T for %q in list response", raw, itemsKey)
+	}
+
+	// Most APIs use `unreachable`, Compute uses `unreachables`.
+	for _, key := range []string{"unreachable", "unreachables"} {
+		if ls, ok := res[key].([]interface{}); ok {
+			for _, u := range ls {
+				if s, ok := u.(string); ok {
+					page.Unreachable = append(page.Unreachable, s)
+				}
+			}
+		}
+	}
+
+	return page, nil
+}
@@ -0,0 +1,189 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// testListServer serves the given pages in order, keyed by the page token
+// requesting them, and records the query of each request.
+func testListServer(t *testing.T, pages map[string]map[string]interface{}, queries *[]map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := map[string]string{}
+		for k, v := range r.URL.Query() {
+			q[k] = v[0]
+		}
+		*queries = append(*queries, q)
+
+		page, ok := pages[r.URL.Query().Get("pageToken")]
+		if !ok {
+			t.Errorf("unexpected page token %q", r.URL.Query().Get("pageToken"))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+	}))
+}
+
+func testListConfig(ts *httptest.Server) *Config {
+	return &Config{Client: ts.Client()}
+}
+
+func TestListAll_pages(t *testing.T) {
+	var queries []map[string]string
+	ts := testListServer(t, map[string]map[string]interface{}{
+		"": {
+			"things":        []interface{}{"a", "b"},
+			"nextPageToken": "page2",
+		},
+		"page2": {
+			"things":        []interface{}{"c"},
+			"nextPageToken": "page3",
+		},
+		"page3": {},
+	}, &queries)
+	defer ts.Close()
+
+	items, err := ListAll(SendRequestOptions{
+		Config: testListConfig(ts),
+		RawURL: ts.URL + "/v1/projects/p/things?filter=x",
+	}, "things", ListWithPageSize(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if expected := []interface{}{"a", "b", "c"}; !reflect.DeepEqual(items, expected) {
+		t.Errorf("expected items %v, got %v", expected, items)
+	}
+	if len(queries) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(queries))
+	}
+	for i, q := range queries {
+		if q["pageSize"] != "2" || q["filter"] != "x" {
+			t.Errorf("expected request %d to keep its query and set pageSize, got %v", i, q)
+		}
+	}
+	if queries[2]["pageToken"] != "page3" {
+		t.Errorf("expected last request to use page token %q, got %v", "page3", queries[2])
+	}
+}
+
+func TestListAll_aggregated(t *testing.T) {
+	var queries []map[string]string
+	ts := testListServer(t, map[string]map[string]interface{}{
+		"": {
+			"items": map[string]interface{}{
+				"zones/us-central1-a": map[string]interface{}{
+					"instances": []interface{}{"a", "b"},
+				},
+				"zones/us-central1-b": map[string]interface{}{
+					"warning": map[string]interface{}{"code": "NO_RESULTS_ON_PAGE"},
+				},
+				"zones/us-east1-b": map[string]interface{}{
+					"instances": []interface{}{"c"},
+				},
+			},
+			"unreachables": []interface{}{"zones/us-west1-a"},
+		},
+	}, &queries)
+	defer ts.Close()
+
+	var pages []*ListPage
+	err := ListPages(SendRequestOptions{
+		Config: testListConfig(ts),
+		RawURL: ts.URL + "/compute/v1/projects/p/aggregated/instances",
+	}, "items", func(page *ListPage) error {
+		pages = append(pages, page)
+		return nil
+	}, ListWithMaxResults(500))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(pages) != 1 {
+		t.Fatalf("expected 1 page, got %d", len(pages))
+	}
+	var items []string
+	for _, item := range pages[0].Items {
+		items = append(items, item.(string))
+	}
+	sort.Strings(items)
+	if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(items, expected) {
+		t.Errorf("expected items %v, got %v", expected, items)
+	}
+	if expected := []string{"zones/us-west1-a"}; !reflect.DeepEqual(pages[0].Unreachable, expected) {
+		t.Errorf("expected unreachable %v, got %v", expected, pages[0].Unreachable)
+	}
+	if queries[0]["maxResults"] != "500" {
+		t.Errorf("expected maxResults to be set, got %v", queries[0])
+	}
+}
+
+func TestListAll_itemsFallbackAndEmpty(t *testing.T) {
+	cases := map[string]struct {
+		Response map[string]interface{}
+		Expected []interface{}
+	}{
+		"items key": {
+			Response: map[string]interface{}{"items": []interface{}{"a"}},
+			Expected: []interface{}{"a"},
+		},
+		"empty response": {
+			Response: map[string]interface{}{},
+			Expected: []interface{}{},
+		},
+	}
+
+	for tn, tc := range cases {
+		var queries []map[string]string
+		ts := testListServer(t, map[string]map[string]interface{}{"": tc.Response}, &queries)
+
+		items, err := ListAll(SendRequestOptions{
+			Config: testListConfig(ts),
+			RawURL: ts.URL,
+		}, "things")
+		ts.Close()
+		if err != nil {
+			t.Fatalf("bad: %s, unexpected error: %s", tn, err)
+		}
+		if !reflect.DeepEqual(items, tc.Expected) {
+			t.Errorf("bad: %s, expected items %v, got %v", tn, tc.Expected, items)
+		}
+	}
+}
+
+func TestListAll_contextCancelled(t *testing.T) {
+	var queries []map[string]string
+	ts := testListServer(t, map[string]map[string]interface{}{
+		"": {
+			"things":        []interface{}{"a"},
+			"nextPageToken": "page2",
+		},
+		"page2": {
+			"things": []interface{}{"b"},
+		},
+	}, &queries)
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := ListPages(SendRequestOptions{
+		Config:  testListConfig(ts),
+		RawURL:  ts.URL,
+		Context: ctx,
+	}, "things", func(page *ListPage) error {
+		cancel()
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("expected an error after the context was cancelled")
+	}
+	if len(queries) != 1 {
+		t.Errorf("expected listing to stop after the first page, got %d requests", len(queries))
+	}
+}
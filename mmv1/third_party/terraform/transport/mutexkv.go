@@ -1,32 +1,69 @@
 package transport
 
 import (
+	"fmt"
 	"log"
+	"runtime"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 )
 
+// How often to log that a lock is still being waited for.
+const mutexWaitLogInterval = 1 * time.Minute
+
 // MutexKV is a simple key/value store for arbitrary mutexes. It can be used to
 // serialize changes across arbitrary collaborators that share knowledge of the
 // keys they must serialize on.
 //
 // The initial use case is to let aws_security_group_rule resources serialize
 // their access to individual security groups based on SG ID.
+//
+// MutexKV keeps track of who holds and waits for each key, so that a hang on
+// a lock can be traced back to its holder. See Dump.
 type MutexKV struct {
 	lock  sync.Mutex
 	store map[string]*sync.RWMutex
+
+	// Current holders and waiters of each key.
+	holders map[string][]*mutexOwner
+	waiters map[string][]*mutexOwner
+}
+
+// mutexOwner is a caller holding or waiting for a key.
+type mutexOwner struct {
+	caller string
+	read   bool
+	since  time.Time
+}
+
+func (o *mutexOwner) String() string {
+	mode := "write"
+	if o.read {
+		mode = "read"
+	}
+	return fmt.Sprintf("%s (%s, %s)", o.caller, mode, time.Since(o.since).Round(time.Millisecond))
 }
 
 // Locks the mutex for the given key. Caller is responsible for calling Unlock
 // for the same key
 func (m *MutexKV) Lock(key string) {
-	log.Printf("[DEBUG] Locking %q", key)
-	m.get(key).Lock()
-	log.Printf("[DEBUG] Locked %q", key)
+	m.acquire(key, false, 0)
+}
+
+// LockWithTimeout locks the mutex for the given key like Lock, but gives up
+// with an error naming the key and its holders if it can't be locked within
+// timeout. A timeout of 0 waits indefinitely. Caller is responsible for
+// calling Unlock for the same key if the mutex was locked.
+func (m *MutexKV) LockWithTimeout(key string, timeout time.Duration) error {
+	return m.acquire(key, false, timeout)
 }
 
 // Unlock the mutex for the given key. Caller must have called Lock for the same key first
 func (m *MutexKV) Unlock(key string) {
 	log.Printf("[DEBUG] Unlocking %q", key)
+	m.release(key, false)
 	m.get(key).Unlock()
 	log.Printf("[DEBUG] Unlocked %q", key)
 }
@@ -34,18 +71,209 @@ func (m *MutexKV) Unlock(key string) {
 // Acquires a read-lock on the mutex for the given key. Caller is responsible for calling RUnlock
 // for the same key
 func (m *MutexKV) RLock(key string) {
-	log.Printf("[DEBUG] RLocking %q", key)
-	m.get(key).RLock()
-	log.Printf("[DEBUG] RLocked %q", key)
+	m.acquire(key, true, 0)
+}
+
+// RLockWithTimeout acquires a read-lock on the mutex for the given key like
+// RLock, but gives up with an error naming the key and its holders if it
+// can't be acquired within timeout. A timeout of 0 waits indefinitely.
+func (m *MutexKV) RLockWithTimeout(key string, timeout time.Duration) error {
+	return m.acquire(key, true, timeout)
 }
 
 // Releases a read-lock on the mutex for the given key. Caller must have called RLock for the same key first
 func (m *MutexKV) RUnlock(key string) {
 	log.Printf("[DEBUG] RUnlocking %q", key)
+	m.release(key, true)
 	m.get(key).RUnlock()
 	log.Printf("[DEBUG] RUnlocked %q", key)
 }
 
+// acquire locks or read-locks the mutex for key, logging how long it waited
+// for it, and giving up after timeout if it is not 0.
+func (m *MutexKV) acquire(key string, read bool, timeout time.Duration) error {
+	verb, action := "Lock", "lock"
+	if read {
+		verb, action = "RLock", "read-lock"
+	}
+	log.Printf("[DEBUG] %sing %q", verb, key)
+
+	owner := &mutexOwner{caller: mutexCaller(), read: read, since: time.Now()}
+	mutex := m.get(key)
+	m.lock.Lock()
+	m.waiters[key] = append(m.waiters[key], owner)
+	m.lock.Unlock()
+
+	// The mutex is locked in the background so that waits can be logged and
+	// timed out. acquired is closed once it is locked, unless the wait was
+	// abandoned first, in which case the mutex is unlocked right away.
+	acquired := make(chan struct{})
+	abandoned := false
+	go func() {
+		if read {
+			mutex.RLock()
+		} else {
+			mutex.Lock()
+		}
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		if abandoned {
+			if read {
+				mutex.RUnlock()
+			} else {
+				mutex.Unlock()
+			}
+			return
+		}
+		close(acquired)
+	}()
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	ticker := time.NewTicker(mutexWaitLogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-acquired:
+			m.lock.Lock()
+			m.removeWaiter(key, owner)
+			waited := time.Since(owner.since)
+			owner.since = time.Now()
+			m.holders[key] = append(m.holders[key], owner)
+			m.lock.Unlock()
+			log.Printf("[DEBUG] %sed %q after waiting %s", verb, key, waited.Round(time.Millisecond))
+			return nil
+		case <-ticker.C:
+			log.Printf("[WARN] Still waiting to %s %q after %s, held by %s", action, key, time.Since(owner.since).Round(time.Second), m.holdersOf(key))
+		case <-deadline:
+			m.lock.Lock()
+			select {
+			case <-acquired:
+				// The mutex was locked as the wait timed out.
+				m.lock.Unlock()
+				continue
+			default:
+			}
+			abandoned = true
+			m.removeWaiter(key, owner)
+			holders := m.holdersLocked(key)
+			m.lock.Unlock()
+			log.Printf("[WARN] Timed out waiting to %s %q, current locks:\n%s", action, key, m.Dump())
+			return fmt.Errorf("timed out after %s waiting to %s %q, held by %s", timeout, action, key, holders)
+		}
+	}
+}
+
+// release removes a holder of key, the oldest one in the given mode.
+func (m *MutexKV) release(key string, read bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	holders := m.holders[key]
+	for i, o := range holders {
+		if o.read == read {
+			m.holders[key] = append(holders[:i:i], holders[i+1:]...)
+			break
+		}
+	}
+	if len(m.holders[key]) == 0 {
+		delete(m.holders, key)
+	}
+}
+
+// removeWaiter removes owner from the waiters of key. m.lock must be held.
+func (m *MutexKV) removeWaiter(key string, owner *mutexOwner) {
+	waiters := m.waiters[key]
+	for i, o := range waiters {
+		if o == owner {
+			m.waiters[key] = append(waiters[:i:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(m.waiters[key]) == 0 {
+		delete(m.waiters, key)
+	}
+}
+
+func (m *MutexKV) holdersOf(key string) string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.holdersLocked(key)
+}
+
+// holdersLocked describes the holders of key. m.lock must be held.
+func (m *MutexKV) holdersLocked(key string) string {
+	holders := m.holders[key]
+	if len(holders) == 0 {
+		return "no one"
+	}
+	var s []string
+	for _, o := range holders {
+		s = append(s, o.String())
+	}
+	return strings.Join(s, ", ")
+}
+
+// Dump describes the keys that are currently held or waited for, with their
+// holders and waiters, one key per line. Callers are reported by function
+// name, along with their lock mode and how long they've held or waited for
+// the key.
+func (m *MutexKV) Dump() string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	keys := make(map[string]bool)
+	for k := range m.holders {
+		keys[k] = true
+	}
+	for k := range m.waiters {
+		keys[k] = true
+	}
+	if len(keys) == 0 {
+		return "no locks held"
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var b strings.Builder
+	for _, k := range sorted {
+		fmt.Fprintf(&b, "%q held by %s", k, m.holdersLocked(k))
+		if waiters := m.waiters[k]; len(waiters) > 0 {
+			var s []string
+			for _, o := range waiters {
+				s = append(s, o.String())
+			}
+			fmt.Fprintf(&b, ", waited for by %s", strings.Join(s, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// mutexCaller returns the name of the function that called into MutexKV,
+// such as "compute.resourceComputeRouterNatCreate".
+func mutexCaller() string {
+	pcs := make([]uintptr, 10)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		name := frame.Function[strings.LastIndex(frame.Function, "/")+1:]
+		if !strings.HasPrefix(name, "transport.(*MutexKV)") && name != "transport.LockedCall" {
+			return name
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
 // Returns a mutex for the given key, no guarantee of its lock status
 func (m *MutexKV) get(key string) *sync.RWMutex {
 	m.lock.Lock()
@@ -61,7 +289,9 @@ func (m *MutexKV) get(key string) *sync.RWMutex {
 // Returns a properly initialized MutexKV
 func NewMutexKV() *MutexKV {
 	return &MutexKV{
-		store: make(map[string]*sync.RWMutex),
+		store:   make(map[string]*sync.RWMutex),
+		holders: make(map[string][]*mutexOwner),
+		waiters: make(map[string][]*mutexOwner),
 	}
 }
 
@@ -0,0 +1,37 @@
+//go:build !windows
+
+package transport
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var dumpMutexesOnSignalOnce sync.Once
+
+// DumpMutexesOnSignal logs the locks of MutexStore when the provider receives
+// SIGQUIT. It's called when configuring the provider rather than when loading
+// the package, so that programs importing it keep their own signal handling.
+func DumpMutexesOnSignal() {
+	dumpMutexesOnSignalOnce.Do(func() {
+		dumpMutexesOnSignal(MutexStore, syscall.SIGQUIT)
+	})
+}
+
+// dumpMutexesOnSignal logs the locks of m when the process receives sig, and
+// then re-raises it with its default behavior restored. For SIGQUIT, that is
+// dumping all goroutines and exiting, so a hung apply shows both which keys
+// are held and where each goroutine is blocked.
+func dumpMutexesOnSignal(m *MutexKV, sig syscall.Signal) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, sig)
+	go func() {
+		<-c
+		log.Printf("[WARN] Received %s, current locks:\n%s", sig, m.Dump())
+		signal.Reset(sig)
+		syscall.Kill(syscall.Getpid(), sig)
+	}()
+}
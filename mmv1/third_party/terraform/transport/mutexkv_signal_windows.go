@@ -0,0 +1,6 @@
+//go:build windows
+
+package transport
+
+// DumpMutexesOnSignal does nothing on Windows, which has no SIGQUIT.
+func DumpMutexesOnSignal() {}
@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMutexKV_LockWithTimeout(t *testing.T) {
+	m := NewMutexKV()
+	m.Lock("router/us-central1/my-router")
+
+	err := m.LockWithTimeout("router/us-central1/my-router", 10*time.Millisecond)
+	if err == nil {
+		t.Fatalf("expected a timeout locking a held key")
+	}
+	for _, expected := range []string{`"router/us-central1/my-router"`, "transport.TestMutexKV_LockWithTimeout", "write"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected the error to contain %q, got %q", expected, err)
+		}
+	}
+
+	// Other keys aren't affected.
+	if err := m.LockWithTimeout("router/us-central1/other", 10*time.Millisecond); err != nil {
+		t.Errorf("unexpected error locking another key: %s", err)
+	}
+	m.Unlock("router/us-central1/other")
+
+	// The abandoned wait doesn't keep the key locked once released.
+	m.Unlock("router/us-central1/my-router")
+	if err := m.LockWithTimeout("router/us-central1/my-router", time.Second); err != nil {
+		t.Errorf("unexpected error locking a released key: %s", err)
+	}
+	m.Unlock("router/us-central1/my-router")
+
+	if dump := m.Dump(); dump != "no locks held" {
+		t.Errorf("expected no locks to be held, got %q", dump)
+	}
+}
+
+func TestMutexKV_RLockWithTimeout(t *testing.T) {
+	m := NewMutexKV()
+	m.RLock("k")
+	if err := m.RLockWithTimeout("k", 10*time.Millisecond); err != nil {
+		t.Errorf("expected read-locks to be shared, got %s", err)
+	}
+	if err := m.LockWithTimeout("k", 10*time.Millisecond); err == nil || !strings.Contains(err.Error(), "read") {
+		t.Errorf("expected a timeout naming the readers, got %v", err)
+	}
+	m.RUnlock("k")
+	m.RUnlock("k")
+	if err := m.LockWithTimeout("k", time.Second); err != nil {
+		t.Errorf("unexpected error locking a released key: %s", err)
+	}
+	m.Unlock("k")
+}
+
+func TestMutexKV_Dump(t *testing.T) {
+	m := NewMutexKV()
+	m.Lock("b")
+	m.RLock("a")
+
+	waiting := make(chan struct{})
+	go func() {
+		close(waiting)
+		m.Lock("b")
+		m.Unlock("b")
+	}()
+	<-waiting
+	// Wait for the goroutine to register as a waiter.
+	for i := 0; i < 100 && !strings.Contains(m.Dump(), "waited for by"); i++ {
+		time.Sleep(time.Millisecond)
+	}
+
+	lines := strings.Split(strings.TrimSpace(m.Dump()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 keys in the dump, got %q", lines)
+	}
+	if !strings.HasPrefix(lines[0], `"a" held by transport.TestMutexKV_Dump (read, `) {
+		t.Errorf("unexpected dump of a: %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], `"b" held by transport.TestMutexKV_Dump (write, `) || !strings.Contains(lines[1], "waited for by transport.TestMutexKV_Dump.func1 (write, ") {
+		t.Errorf("unexpected dump of b: %q", lines[1])
+	}
+
+	m.RUnlock("a")
+	m.Unlock("b")
+}
+
+func TestLockedCall_caller(t *testing.T) {
+	err := LockedCall("k", func() error {
+		if dump := MutexStore.Dump(); !strings.Contains(dump, `"k" held by transport.TestLockedCall_caller (write, `) {
+			t.Errorf("expected the caller of LockedCall to hold the key, got %q", dump)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
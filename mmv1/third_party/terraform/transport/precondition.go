@@ -0,0 +1,251 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/errwrap"
+	"google.golang.org/api/googleapi"
+)
+
+// DefaultPreconditionMaxRetries is the number of times a request is retried
+// after conflicts unless configured otherwise.
+const DefaultPreconditionMaxRetries = 3
+
+// Ways of sending a precondition with a request.
+const (
+	// As a field of the request body, e.g. {"etag": "..."}.
+	PreconditionInBody = "body"
+	// As a query parameter, e.g. ?etag=...
+	PreconditionInQuery = "query"
+	// As an If-Match header.
+	PreconditionInHeader = "header"
+)
+
+// Precondition makes a request conditional on the resource it modifies not
+// having changed since it was last read, as identified by its etag or
+// fingerprint.
+//
+// APIs reject requests with a stale value with a 412, or a 409 with an
+// ABORTED status. The resource is then read again with Read, and the request
+// rebased on it: fields of the body the request doesn't change take their
+// current values, while those it changes keep theirs. If a field it changes
+// was also changed concurrently, or after MaxRetries retries, a
+// *PreconditionConflictError is returned.
+type Precondition struct {
+	// API field holding the value, such as "etag" or "fingerprint". It is also
+	// the name of the query parameter for PreconditionInQuery.
+	Field string
+
+	// How the value is sent, one of the PreconditionIn* constants.
+	In string
+
+	// Last read value. If empty, the request is sent without a precondition.
+	Value string
+
+	// Values of the fields of the request body as last read, which the
+	// request was planned from. Fields of the body whose value differs are
+	// the ones the request changes. Fields missing from Base are always
+	// sent as they are.
+	Base map[string]interface{}
+
+	// Number of times to retry the request after a conflict.
+	MaxRetries int
+
+	// Reads the current resource, including the precondition field.
+	Read func() (map[string]interface{}, error)
+}
+
+// apply returns a copy of opt with the precondition set to value.
+func (p *Precondition) apply(opt SendRequestOptions, value string) (SendRequestOptions, error) {
+	opt.Precondition = nil
+	if value == "" {
+		return opt, nil
+	}
+
+	switch p.In {
+	case PreconditionInBody:
+		body := make(map[string]interface{}, len(opt.Body)+1)
+		for k, v := range opt.Body {
+			body[k] = v
+		}
+		body[p.Field] = value
+		opt.Body = body
+	case PreconditionInQuery:
+		u, err := AddQueryParams(opt.RawURL, map[string]string{p.Field: value})
+		if err != nil {
+			return opt, err
+		}
+		opt.RawURL = u
+	case PreconditionInHeader:
+		headers := make(http.Header)
+		for k, v := range opt.Headers {
+			headers[k] = v
+		}
+		headers.Set("If-Match", value)
+		opt.Headers = headers
+	default:
+		return opt, fmt.Errorf("unknown precondition placement %q", p.In)
+	}
+	return opt, nil
+}
+
+// sendRequestWithPrecondition sends a request with opt.Precondition, rebasing
+// it on the resource's current state and retrying it after conflicts.
+func sendRequestWithPrecondition(opt SendRequestOptions) (map[string]interface{}, error) {
+	p := opt.Precondition
+	value := p.Value
+	body := opt.Body
+	for attempt := 0; ; attempt++ {
+		o := opt
+		o.Body = body
+		o, err := p.apply(o, value)
+		if err != nil {
+			return nil, err
+		}
+		res, err := SendRequest(o)
+		if err == nil || value == "" || !IsPreconditionFailedError(err) {
+			return res, err
+		}
+		if attempt >= p.MaxRetries || p.Read == nil {
+			return nil, &PreconditionConflictError{Field: p.Field, Retries: attempt, Err: err}
+		}
+
+		log.Printf("[DEBUG] %s %s: %s %q is stale, reading the resource to retry (%d/%d)", opt.Method, opt.RawURL, p.Field, value, attempt+1, p.MaxRetries)
+		current, rerr := p.Read()
+		if rerr != nil {
+			return nil, rerr
+		}
+		value, _ = current[p.Field].(string)
+
+		var conflicts []string
+		body, conflicts = p.rebase(opt.Body, current)
+		if len(conflicts) > 0 {
+			return nil, &PreconditionConflictError{Field: p.Field, Fields: conflicts, Retries: attempt, Err: err}
+		}
+	}
+}
+
+// rebase returns body updated with the values of current for the fields the
+// request doesn't change, and the fields it changes that were also changed in
+// current.
+func (p *Precondition) rebase(body, current map[string]interface{}) (map[string]interface{}, []string) {
+	if body == nil {
+		return nil, nil
+	}
+
+	rebased := make(map[string]interface{}, len(body))
+	var conflicts []string
+	for k, v := range body {
+		base, ok := p.Base[k]
+		if !ok || k == p.Field {
+			rebased[k] = v
+			continue
+		}
+		if preconditionValuesEqual(v, base) {
+			// The request doesn't change the field, so its current value is
+			// sent rather than overwriting a concurrent change.
+			if cur, ok := current[k]; ok {
+				rebased[k] = cur
+			}
+			continue
+		}
+		if cur := current[k]; !preconditionValuesEqual(cur, base) && !preconditionValuesEqual(cur, v) {
+			conflicts = append(conflicts, k)
+		}
+		rebased[k] = v
+	}
+	sort.Strings(conflicts)
+	return rebased, conflicts
+}
+
+// preconditionValuesEqual returns whether a and b have the same JSON
+// representation. Numbers are compared by their text, as APIs return int64
+// values as strings.
+func preconditionValuesEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(normalizePreconditionValue(a), normalizePreconditionValue(b))
+}
+
+func normalizePreconditionValue(v interface{}) interface{} {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return v
+	}
+	return normalizePreconditionNumbers(decoded)
+}
+
+func normalizePreconditionNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizePreconditionNumbers(item)
+		}
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = normalizePreconditionNumbers(item)
+		}
+	}
+	return v
+}
+
+// IsPreconditionFailedError returns whether err is the error APIs return for
+// a request with a stale etag or fingerprint: a 412, or a 409 with an ABORTED
+// status or an aborted or conditionNotMet reason. Other 409s, such as for
+// resources that already exist or operations in progress, aren't.
+func IsPreconditionFailedError(err error) bool {
+	gerr, ok := errwrap.GetType(err, &googleapi.Error{}).(*googleapi.Error)
+	if !ok || gerr == nil {
+		return false
+	}
+	switch gerr.Code {
+	case http.StatusPreconditionFailed:
+		return true
+	case http.StatusConflict:
+		if strings.Contains(gerr.Body, `"ABORTED"`) {
+			return true
+		}
+		for _, e := range gerr.Errors {
+			if e.Reason == "aborted" || e.Reason == "conditionNotMet" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PreconditionConflictError is returned when a request with a Precondition
+// kept conflicting with concurrent changes to the resource.
+type PreconditionConflictError struct {
+	Field string
+	// Fields the request changes that were also changed concurrently, if any.
+	Fields  []string
+	Retries int
+	Err     error
+}
+
+func (e *PreconditionConflictError) Error() string {
+	if len(e.Fields) > 0 {
+		return fmt.Sprintf("the resource was modified concurrently: %s changed since it was last read, and the request would overwrite the changes. Refresh and apply again, or make sure nothing else is modifying the resource. Last error: %s", strings.Join(e.Fields, ", "), e.Err)
+	}
+	return fmt.Sprintf("the resource was modified concurrently: its %s changed while the request was made, and retrying %d times with the current %s didn't resolve the conflict. Refresh and apply again, or make sure nothing else is modifying the resource. Last error: %s", e.Field, e.Retries, e.Field, e.Err)
+}
+
+func (e *PreconditionConflictError) WrappedErrors() []error {
+	return []error{e.Err}
+}
+
+func (e *PreconditionConflictError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,331 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"google.golang.org/api/googleapi"
+)
+
+// etagCheckingTransport rejects requests whose etag doesn't match its current
+// one with a 412. Its etag changes before each of the first conflicts requests
+// to simulate concurrent modifications.
+type etagCheckingTransport struct {
+	etag      string
+	conflicts int
+	sent      []string
+	bodies    []map[string]interface{}
+}
+
+func (t *etagCheckingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	etag := req.URL.Query().Get("etag")
+	if v := req.Header.Get("If-Match"); v != "" {
+		etag = v
+	}
+	if req.Body != nil {
+		var body map[string]interface{}
+		if err := json.NewDecoder(req.Body).Decode(&body); err == nil {
+			if v, ok := body["etag"].(string); ok {
+				etag = v
+			}
+			t.bodies = append(t.bodies, body)
+		}
+	}
+	t.sent = append(t.sent, etag)
+
+	if t.conflicts > 0 {
+		t.conflicts--
+		t.etag += "'"
+	}
+	status := 200
+	if etag != "" && etag != t.etag {
+		status = 412
+	}
+	if status != 200 {
+		return faultResponse(req, status, nil, faultErrorBody(status, "", "etag mismatch"), false), nil
+	}
+	return faultResponse(req, status, nil, []byte(`{"name": "t"}`), false), nil
+}
+
+func TestSendRequest_precondition(t *testing.T) {
+	cases := map[string]struct {
+		In         string
+		Value      string
+		Conflicts  int
+		MaxRetries int
+		Sent       []string
+		Conflict   bool
+	}{
+		"up to date": {
+			In:    PreconditionInBody,
+			Value: "a",
+			Sent:  []string{"a"},
+		},
+		"no value": {
+			In:        PreconditionInBody,
+			Conflicts: 1,
+			Sent:      []string{""},
+		},
+		"retried in body": {
+			In:         PreconditionInBody,
+			Value:      "a",
+			Conflicts:  1,
+			MaxRetries: 3,
+			Sent:       []string{"a", "a'"},
+		},
+		"retried in query": {
+			In:         PreconditionInQuery,
+			Value:      "a",
+			Conflicts:  2,
+			MaxRetries: 3,
+			Sent:       []string{"a", "a'", "a''"},
+		},
+		"retried in header": {
+			In:         PreconditionInHeader,
+			Value:      "a",
+			Conflicts:  1,
+			MaxRetries: 3,
+			Sent:       []string{"a", "a'"},
+		},
+		"conflict": {
+			In:         PreconditionInBody,
+			Value:      "a",
+			Conflicts:  5,
+			MaxRetries: 2,
+			Sent:       []string{"a", "a'", "a''"},
+			Conflict:   true,
+		},
+		"no retries": {
+			In:       PreconditionInQuery,
+			Value:    "stale",
+			Sent:     []string{"stale"},
+			Conflict: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		et := &etagCheckingTransport{etag: "a", conflicts: tc.Conflicts}
+		config := &Config{Client: &http.Client{Transport: et}}
+		body := map[string]interface{}{"name": "t"}
+		_, err := SendRequest(SendRequestOptions{
+			Config:  config,
+			Method:  "PATCH",
+			RawURL:  "https://example.googleapis.com/v1/things/t",
+			Body:    body,
+			Timeout: 5 * time.Second,
+			Precondition: &Precondition{
+				Field:      "etag",
+				In:         tc.In,
+				Value:      tc.Value,
+				MaxRetries: tc.MaxRetries,
+				Read: func() (map[string]interface{}, error) {
+					return map[string]interface{}{"etag": et.etag}, nil
+				},
+			},
+		})
+
+		// Reads aren't sent through the transport, so only the request's
+		// attempts are recorded.
+		if strings.Join(et.sent, ",") != strings.Join(tc.Sent, ",") {
+			t.Errorf("bad: %s, expected etags %q to be sent, got %q", tn, tc.Sent, et.sent)
+		}
+		if _, ok := body["etag"]; ok {
+			t.Errorf("bad: %s, the request body was modified", tn)
+		}
+
+		var conflict *PreconditionConflictError
+		if tc.Conflict {
+			if !errors.As(err, &conflict) {
+				t.Errorf("bad: %s, expected a conflict error, got %v", tn, err)
+				continue
+			}
+			if conflict.Retries != tc.MaxRetries {
+				t.Errorf("bad: %s, expected %d retries, got %d", tn, tc.MaxRetries, conflict.Retries)
+			}
+			if !IsGoogleApiErrorWithCode(err, 412) {
+				t.Errorf("bad: %s, expected the conflict error to wrap the API error, got %v", tn, err)
+			}
+			if gerr, ok := errwrap.GetType(err, &googleapi.Error{}).(*googleapi.Error); !ok || gerr.Code != 412 {
+				t.Errorf("bad: %s, expected the API error to be found with errwrap, got %v", tn, err)
+			}
+		} else if err != nil {
+			t.Errorf("bad: %s, unexpected error %s", tn, err)
+		}
+	}
+}
+
+func TestSendRequest_preconditionReadError(t *testing.T) {
+	et := &etagCheckingTransport{etag: "b"}
+	_, err := SendRequest(SendRequestOptions{
+		Config:  &Config{Client: &http.Client{Transport: et}},
+		Method:  "DELETE",
+		RawURL:  "https://example.googleapis.com/v1/things/t",
+		Timeout: 5 * time.Second,
+		Precondition: &Precondition{
+			Field:      "etag",
+			In:         PreconditionInQuery,
+			Value:      "a",
+			MaxRetries: 3,
+			Read: func() (map[string]interface{}, error) {
+				return nil, errors.New("read failed")
+			},
+		},
+	})
+	if err == nil || err.Error() != "read failed" {
+		t.Errorf("expected the read error to be returned, got %v", err)
+	}
+	if len(et.sent) != 1 {
+		t.Errorf("expected 1 request, got %d", len(et.sent))
+	}
+}
+
+func TestSendRequest_preconditionRebase(t *testing.T) {
+	base := map[string]interface{}{
+		"description": "old",
+		"labels":      map[string]interface{}{"env": "dev"},
+		"replicas":    2,
+	}
+
+	cases := map[string]struct {
+		Current  map[string]interface{}
+		Sent     map[string]interface{}
+		Conflict []string
+	}{
+		"unchanged fields take their current value": {
+			Current: map[string]interface{}{
+				"description": "old",
+				"labels":      map[string]interface{}{"env": "prod"},
+				"replicas":    "2",
+			},
+			Sent: map[string]interface{}{
+				"description": "new",
+				"labels":      map[string]interface{}{"env": "prod"},
+				"replicas":    "2",
+			},
+		},
+		"changed fields changed to the same value": {
+			Current: map[string]interface{}{
+				"description": "new",
+				"labels":      map[string]interface{}{"env": "dev"},
+				"replicas":    "2",
+			},
+			Sent: map[string]interface{}{
+				"description": "new",
+				"labels":      map[string]interface{}{"env": "dev"},
+				"replicas":    "2",
+			},
+		},
+		"changed fields changed concurrently": {
+			Current: map[string]interface{}{
+				"description": "other",
+				"labels":      map[string]interface{}{"env": "dev"},
+				"replicas":    "2",
+			},
+			Conflict: []string{"description"},
+		},
+	}
+
+	for tn, tc := range cases {
+		et := &etagCheckingTransport{etag: "a", conflicts: 1}
+		_, err := SendRequest(SendRequestOptions{
+			Config:  &Config{Client: &http.Client{Transport: et}},
+			Method:  "PATCH",
+			RawURL:  "https://example.googleapis.com/v1/things/t",
+			Body:    map[string]interface{}{"description": "new", "labels": map[string]interface{}{"env": "dev"}, "replicas": 2},
+			Timeout: 5 * time.Second,
+			Precondition: &Precondition{
+				Field:      "etag",
+				In:         PreconditionInBody,
+				Value:      "a",
+				Base:       base,
+				MaxRetries: 3,
+				Read: func() (map[string]interface{}, error) {
+					current := map[string]interface{}{"etag": et.etag}
+					for k, v := range tc.Current {
+						current[k] = v
+					}
+					return current, nil
+				},
+			},
+		})
+
+		if tc.Conflict != nil {
+			var conflict *PreconditionConflictError
+			if !errors.As(err, &conflict) {
+				t.Errorf("bad: %s, expected a conflict error, got %v", tn, err)
+				continue
+			}
+			if !reflect.DeepEqual(conflict.Fields, tc.Conflict) {
+				t.Errorf("bad: %s, expected conflicting fields %q, got %q", tn, tc.Conflict, conflict.Fields)
+			}
+			if len(et.bodies) != 1 {
+				t.Errorf("bad: %s, expected the request not to be retried, got %d requests", tn, len(et.bodies))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("bad: %s, unexpected error %s", tn, err)
+			continue
+		}
+		if len(et.bodies) != 2 {
+			t.Errorf("bad: %s, expected the request to be retried once, got %d requests", tn, len(et.bodies))
+			continue
+		}
+		sent := et.bodies[1]
+		delete(sent, "etag")
+		if !preconditionValuesEqual(sent, tc.Sent) {
+			t.Errorf("bad: %s, expected the retry to send %v, got %v", tn, tc.Sent, sent)
+		}
+	}
+}
+
+func TestIsPreconditionFailedError(t *testing.T) {
+	cases := map[string]struct {
+		Err      error
+		Expected bool
+	}{
+		"412": {
+			Err:      &googleapi.Error{Code: 412},
+			Expected: true,
+		},
+		"409 aborted": {
+			Err:      &googleapi.Error{Code: 409, Body: `{"error": {"code": 409, "status": "ABORTED"}}`},
+			Expected: true,
+		},
+		"409 condition not met": {
+			Err:      &googleapi.Error{Code: 409, Errors: []googleapi.ErrorItem{{Reason: "conditionNotMet"}}},
+			Expected: true,
+		},
+		"409 mentioning the etag": {
+			Err: &googleapi.Error{Code: 409, Message: "The etag of the parent changed while the operation was in progress"},
+		},
+		"409 already exists": {
+			Err: &googleapi.Error{
+				Code:   409,
+				Body:   `{"error": {"code": 409, "status": "ALREADY_EXISTS"}}`,
+				Errors: []googleapi.ErrorItem{{Reason: "alreadyExists"}},
+			},
+		},
+		"409 operation in progress": {
+			Err: &googleapi.Error{Code: 409, Message: "Another operation is in progress on the resource"},
+		},
+		"404": {
+			Err: &googleapi.Error{Code: 404},
+		},
+		"not an API error": {
+			Err: errors.New("failed"),
+		},
+	}
+
+	for tn, tc := range cases {
+		if got := IsPreconditionFailedError(tc.Err); got != tc.Expected {
+			t.Errorf("bad: %s, expected %t, got %t", tn, tc.Expected, got)
+		}
+	}
+}
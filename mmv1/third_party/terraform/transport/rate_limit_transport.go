@@ -0,0 +1,155 @@
+package transport
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimitConfig configures client-side throttling of requests to GCP APIs.
+// Limits are applied per API host (e.g. compute.googleapis.com), and
+// optionally per method within a host.
+type RateLimitConfig struct {
+	// Sustained requests per second allowed per key. 0 means unlimited for
+	// hosts without an entry in HostRequestsPerSecond.
+	RequestsPerSecond float64
+	// Maximum number of requests sent at once per key. Defaults to the
+	// limit rounded up, with a minimum of 1.
+	Burst int
+	// Whether requests are keyed by method as well as host, so that e.g.
+	// mutations and reads of an API are throttled independently.
+	PerMethod bool
+	// Overrides RequestsPerSecond for specific API hosts.
+	HostRequestsPerSecond map[string]float64
+}
+
+func (c *RateLimitConfig) enabled() bool {
+	if c == nil {
+		return false
+	}
+	return c.RequestsPerSecond > 0 || len(c.HostRequestsPerSecond) > 0
+}
+
+func (c *RateLimitConfig) limit(host string) rate.Limit {
+	if rps, ok := c.HostRequestsPerSecond[host]; ok && rps > 0 {
+		return rate.Limit(rps)
+	}
+	if c.RequestsPerSecond > 0 {
+		return rate.Limit(c.RequestsPerSecond)
+	}
+	return rate.Inf
+}
+
+func (c *RateLimitConfig) burst(limit rate.Limit) int {
+	if c.Burst > 0 {
+		return c.Burst
+	}
+	if limit == rate.Inf || limit < 1 {
+		return 1
+	}
+	return int(limit + 0.999)
+}
+
+// rateLimitKeyStats tracks time spent waiting on a single limiter, reported in
+// debug logs.
+type rateLimitKeyStats struct {
+	requests  int
+	throttled int
+	waited    time.Duration
+}
+
+type rateLimitTransport struct {
+	config   *RateLimitConfig
+	internal http.RoundTripper
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	stats    map[string]*rateLimitKeyStats
+}
+
+// NewTransportWithRateLimit returns a transport that waits for a token from a
+// per-host (and optionally per-method) token bucket before sending each request.
+// If config does not set any limit, the given transport is returned unchanged.
+func NewTransportWithRateLimit(internal http.RoundTripper, config *RateLimitConfig) http.RoundTripper {
+	if !config.enabled() {
+		return internal
+	}
+	if internal == nil {
+		internal = http.DefaultTransport
+	}
+
+	return &rateLimitTransport{
+		config:   config,
+		internal: internal,
+		limiters: make(map[string]*rate.Limiter),
+		stats:    make(map[string]*rateLimitKeyStats),
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := t.key(req)
+	limiter := t.limiter(key, req.URL.Host)
+
+	start := time.Now()
+	if err := limiter.Wait(req.Context()); err != nil {
+		return nil, fmt.Errorf("waiting for client-side rate limit on %s: %w", key, err)
+	}
+	t.recordWait(key, time.Since(start))
+
+	return t.internal.RoundTrip(req)
+}
+
+// key returns the limiter key of a request, e.g. "compute.googleapis.com" or
+// "cloudresourcemanager.googleapis.com POST:setIamPolicy" when keyed per method.
+func (t *rateLimitTransport) key(req *http.Request) string {
+	if !t.config.PerMethod {
+		return req.URL.Host
+	}
+	method := req.Method
+	// Custom methods are part of the last path segment, e.g. /v1/projects/foo:setIamPolicy
+	path := req.URL.Path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		path = path[i+1:]
+	}
+	if i := strings.LastIndex(path, ":"); i >= 0 {
+		method += path[i:]
+	}
+	return fmt.Sprintf("%s %s", req.URL.Host, method)
+}
+
+func (t *rateLimitTransport) limiter(key, host string) *rate.Limiter {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if l, ok := t.limiters[key]; ok {
+		return l
+	}
+	limit := t.config.limit(host)
+	l := rate.NewLimiter(limit, t.config.burst(limit))
+	t.limiters[key] = l
+	t.stats[key] = &rateLimitKeyStats{}
+	return l
+}
+
+// Waits shorter than this are not counted as throttling, as Wait reports the
+// time spent acquiring the limiter's lock as well.
+const minRateLimitWait = time.Millisecond
+
+func (t *rateLimitTransport) recordWait(key string, waited time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.stats[key]
+	s.requests++
+	if waited < minRateLimitWait {
+		return
+	}
+	s.throttled++
+	s.waited += waited
+	log.Printf("[DEBUG] Rate Limit Transport: waited %s for %s (total waited %s, %d of %d requests throttled)", waited, key, s.waited, s.throttled, s.requests)
+}
@@ -0,0 +1,159 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewTransportWithRateLimit_disabled(t *testing.T) {
+	cases := map[string]*RateLimitConfig{
+		"nil":   nil,
+		"empty": {},
+		"burst": {Burst: 5},
+	}
+
+	for tn, config := range cases {
+		if got := NewTransportWithRateLimit(http.DefaultTransport, config); got != http.DefaultTransport {
+			t.Errorf("bad: %s, expected the transport to be returned unchanged, got %T", tn, got)
+		}
+	}
+}
+
+func TestRateLimitTransport_key(t *testing.T) {
+	cases := map[string]struct {
+		PerMethod bool
+		Method    string
+		URL       string
+		Expected  string
+	}{
+		"per host": {
+			Method:   "POST",
+			URL:      "https://compute.googleapis.com/compute/v1/projects/p/zones/z/instances",
+			Expected: "compute.googleapis.com",
+		},
+		"per method": {
+			PerMethod: true,
+			Method:    "POST",
+			URL:       "https://compute.googleapis.com/compute/v1/projects/p/zones/z/instances",
+			Expected:  "compute.googleapis.com POST",
+		},
+		"per method with custom method": {
+			PerMethod: true,
+			Method:    "POST",
+			URL:       "https://cloudresourcemanager.googleapis.com/v1/projects/p:setIamPolicy",
+			Expected:  "cloudresourcemanager.googleapis.com POST:setIamPolicy",
+		},
+		"per method ignores colons in parent segments": {
+			PerMethod: true,
+			Method:    "GET",
+			URL:       "https://example.googleapis.com/v1/projects/p:x/things",
+			Expected:  "example.googleapis.com GET",
+		},
+	}
+
+	for tn, tc := range cases {
+		transport := NewTransportWithRateLimit(http.DefaultTransport, &RateLimitConfig{
+			RequestsPerSecond: 1,
+			PerMethod:         tc.PerMethod,
+		}).(*rateLimitTransport)
+		req, err := http.NewRequest(tc.Method, tc.URL, nil)
+		if err != nil {
+			t.Fatalf("bad: %s, %s", tn, err)
+		}
+		if got := transport.key(req); got != tc.Expected {
+			t.Errorf("bad: %s, expected key %q, got %q", tn, tc.Expected, got)
+		}
+	}
+}
+
+func TestRateLimitConfig_limit(t *testing.T) {
+	config := &RateLimitConfig{
+		RequestsPerSecond: 5,
+		HostRequestsPerSecond: map[string]float64{
+			"compute.googleapis.com": 2,
+		},
+	}
+	if got := config.limit("compute.googleapis.com"); got != 2 {
+		t.Errorf("expected host override of 2, got %v", got)
+	}
+	if got := config.limit("iam.googleapis.com"); got != 5 {
+		t.Errorf("expected default of 5, got %v", got)
+	}
+	if got := config.burst(2.5); got != 3 {
+		t.Errorf("expected default burst of 3, got %d", got)
+	}
+
+	config = &RateLimitConfig{
+		HostRequestsPerSecond: map[string]float64{
+			"compute.googleapis.com": 2,
+		},
+	}
+	if got := config.limit("iam.googleapis.com"); got < 1e9 {
+		t.Errorf("expected hosts without an override to be unlimited, got %v", got)
+	}
+}
+
+func TestRateLimitTransport_throttles(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	client := ts.Client()
+	client.Transport = NewTransportWithRateLimit(client.Transport, &RateLimitConfig{
+		RequestsPerSecond: 10,
+		Burst:             1,
+	})
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		resp, err := client.Get(ts.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		resp.Body.Close()
+	}
+
+	// The first request uses the burst, the next 3 wait 100ms each.
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("expected requests to be throttled, took %s", elapsed)
+	}
+
+	stats := client.Transport.(*rateLimitTransport).stats[ts.Listener.Addr().String()]
+	if stats.requests != 4 || stats.throttled < 2 {
+		t.Errorf("expected 4 requests with at least 2 throttled, got %+v", stats)
+	}
+}
+
+func TestRateLimitTransport_contextDeadline(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	client := ts.Client()
+	client.Transport = NewTransportWithRateLimit(client.Transport, &RateLimitConfig{
+		RequestsPerSecond: 0.1,
+		Burst:             1,
+	})
+
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+
+	// The next token is available in 10s, which exceeds the deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", ts.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := client.Do(req); err == nil {
+		t.Errorf("expected an error when the wait exceeds the request deadline")
+	}
+}
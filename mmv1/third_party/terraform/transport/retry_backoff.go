@@ -0,0 +1,139 @@
+package transport
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// Initial wait between retries. Waits grow following a Fibonacci sequence
+	// from this value - 0.5, 1, 1.5, 2.5, 4, 6.5, 10.5, ...
+	retryBackoffBase = 500 * time.Millisecond
+
+	// Cap on a single wait between retries of retryTransport.
+	retryTransportMaxBackoff = 30 * time.Second
+
+	// Cap on a single wait between retries of Retry.
+	retryMaxBackoff = 10 * time.Second
+
+	// Cap on retry delays requested by the server through a Retry-After header
+	// or google.rpc.RetryInfo error detail.
+	maxServerRetryDelay = 5 * time.Minute
+)
+
+// retryBackoff computes the waits between retries of a single request.
+//
+// Waits are capped and use full jitter (a random wait between zero and the
+// current backoff), so that many clients hitting the same quota error do not
+// retry in lock-step. If the server requested a retry delay, it is honored
+// instead, with up to one base interval of jitter added.
+type retryBackoff struct {
+	current time.Duration
+	next    time.Duration
+	max     time.Duration
+
+	// Returns a wait between 0 and the given duration. Defaults to fullJitter.
+	jitter func(time.Duration) time.Duration
+}
+
+func newRetryBackoff(max time.Duration, jitter func(time.Duration) time.Duration) *retryBackoff {
+	if jitter == nil {
+		jitter = fullJitter
+	}
+	return &retryBackoff{
+		current: retryBackoffBase,
+		next:    retryBackoffBase,
+		max:     max,
+		jitter:  jitter,
+	}
+}
+
+// Next returns how long to wait before retrying after the given error, and
+// whether the wait was requested by the server.
+func (b *retryBackoff) Next(err error) (time.Duration, bool) {
+	backoff := b.current
+	if backoff > b.max {
+		backoff = b.max
+	}
+	b.current, b.next = b.current+b.next, b.current
+
+	if delay := serverRetryDelay(err); delay > 0 {
+		if delay > maxServerRetryDelay {
+			delay = maxServerRetryDelay
+		}
+		return delay + b.jitter(retryBackoffBase), true
+	}
+	return b.jitter(backoff), false
+}
+
+func fullJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// serverRetryDelay returns the retry delay requested by the server for an
+// error, from a google.rpc.RetryInfo detail or a Retry-After header, or 0 if
+// the server did not request one.
+func serverRetryDelay(err error) time.Duration {
+	var delay time.Duration
+	errwrap.Walk(err, func(werr error) {
+		var gerr *googleapi.Error
+		if delay > 0 || !errors.As(werr, &gerr) {
+			return
+		}
+		if d := retryInfoDelay(gerr.Details); d > 0 {
+			delay = d
+			return
+		}
+		delay = retryAfterDelay(gerr.Header.Get("Retry-After"), time.Now())
+	})
+	return delay
+}
+
+// retryInfoDelay returns the retryDelay of a google.rpc.RetryInfo error detail, e.g.
+// {"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "30s"}
+func retryInfoDelay(details []interface{}) time.Duration {
+	for _, detail := range details {
+		m, ok := detail.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if t, _ := m["@type"].(string); !strings.HasSuffix(t, "google.rpc.RetryInfo") {
+			continue
+		}
+		if v, _ := m["retryDelay"].(string); v != "" {
+			if d, err := time.ParseDuration(v); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return 0
+}
+
+// retryAfterDelay parses a Retry-After header value, which is either a number
+// of seconds or an HTTP date.
+func retryAfterDelay(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
+		return 0
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,140 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"google.golang.org/api/googleapi"
+)
+
+func TestRetryBackoff_Next(t *testing.T) {
+	b := newRetryBackoff(3*time.Second, func(d time.Duration) time.Duration { return d })
+
+	expected := []time.Duration{
+		500 * time.Millisecond,
+		1 * time.Second,
+		1500 * time.Millisecond,
+		2500 * time.Millisecond,
+		3 * time.Second,
+		3 * time.Second,
+	}
+	for i, want := range expected {
+		got, fromServer := b.Next(&googleapi.Error{Code: 500})
+		if got != want || fromServer {
+			t.Errorf("retry %d: expected wait %s, got %s (from server: %t)", i+1, want, got, fromServer)
+		}
+	}
+}
+
+func TestRetryBackoff_NextServerDelay(t *testing.T) {
+	b := newRetryBackoff(retryTransportMaxBackoff, func(d time.Duration) time.Duration { return 0 })
+
+	got, fromServer := b.Next(&googleapi.Error{
+		Code:   429,
+		Header: http.Header{"Retry-After": []string{"7"}},
+	})
+	if got != 7*time.Second || !fromServer {
+		t.Errorf("expected wait of 7s from server, got %s (from server: %t)", got, fromServer)
+	}
+
+	got, fromServer = b.Next(&googleapi.Error{
+		Code:   429,
+		Header: http.Header{"Retry-After": []string{"86400"}},
+	})
+	if got != maxServerRetryDelay || !fromServer {
+		t.Errorf("expected wait capped to %s from server, got %s (from server: %t)", maxServerRetryDelay, got, fromServer)
+	}
+}
+
+func TestRetryBackoff_FullJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if got := fullJitter(time.Second); got < 0 || got > time.Second {
+			t.Fatalf("expected jitter between 0 and 1s, got %s", got)
+		}
+	}
+	if got := fullJitter(0); got != 0 {
+		t.Errorf("expected no jitter for a zero duration, got %s", got)
+	}
+}
+
+func TestServerRetryDelay(t *testing.T) {
+	retryInfo := []interface{}{
+		map[string]interface{}{
+			"@type":  "type.googleapis.com/google.rpc.ErrorInfo",
+			"reason": "RATE_LIMIT_EXCEEDED",
+		},
+		map[string]interface{}{
+			"@type":      "type.googleapis.com/google.rpc.RetryInfo",
+			"retryDelay": "1.500s",
+		},
+	}
+
+	cases := map[string]struct {
+		err      error
+		expected time.Duration
+	}{
+		"no delay": {
+			err:      &googleapi.Error{Code: 503},
+			expected: 0,
+		},
+		"not a googleapi error": {
+			err:      errors.New("connection reset"),
+			expected: 0,
+		},
+		"retry-after seconds": {
+			err:      &googleapi.Error{Code: 503, Header: http.Header{"Retry-After": []string{"30"}}},
+			expected: 30 * time.Second,
+		},
+		"retry-after invalid": {
+			err:      &googleapi.Error{Code: 503, Header: http.Header{"Retry-After": []string{"soon"}}},
+			expected: 0,
+		},
+		"retry info": {
+			err:      &googleapi.Error{Code: 429, Details: retryInfo},
+			expected: 1500 * time.Millisecond,
+		},
+		"retry info takes precedence over retry-after": {
+			err:      &googleapi.Error{Code: 429, Details: retryInfo, Header: http.Header{"Retry-After": []string{"30"}}},
+			expected: 1500 * time.Millisecond,
+		},
+		"errwrap wrapped": {
+			err:      errwrap.Wrapf("nested error: {{err}}", &googleapi.Error{Code: 429, Details: retryInfo}),
+			expected: 1500 * time.Millisecond,
+		},
+		"fmt wrapped": {
+			err:      fmt.Errorf("nested error: %w", &googleapi.Error{Code: 429, Details: retryInfo}),
+			expected: 1500 * time.Millisecond,
+		},
+	}
+
+	for tn, tc := range cases {
+		if got := serverRetryDelay(tc.err); got != tc.expected {
+			t.Errorf("bad: %s, expected %s, got %s", tn, tc.expected, got)
+		}
+	}
+}
+
+func TestRetryAfterDelay(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		value    string
+		expected time.Duration
+	}{
+		"empty":        {value: "", expected: 0},
+		"seconds":      {value: "120", expected: 2 * time.Minute},
+		"zero seconds": {value: "0", expected: 0},
+		"http date":    {value: "Mon, 01 Jan 2024 00:00:45 GMT", expected: 45 * time.Second},
+		"past date":    {value: "Sun, 31 Dec 2023 23:59:00 GMT", expected: 0},
+	}
+
+	for tn, tc := range cases {
+		if got := retryAfterDelay(tc.value, now); got != tc.expected {
+			t.Errorf("bad: %s, expected %s, got %s", tn, tc.expected, got)
+		}
+	}
+}
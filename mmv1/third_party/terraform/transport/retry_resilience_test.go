@@ -0,0 +1,529 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"google.golang.org/api/googleapi"
+)
+
+func testResilienceNoWait(time.Duration) time.Duration {
+	return 0
+}
+
+// testResilienceRoundTrip sends a request through a retryTransport wrapping a
+// faultInjectionTransport that fails it once with f, and returns the number of
+// attempts made and the resulting error.
+func testResilienceRoundTrip(t *testing.T, f injectedFault, predicates ...RetryErrorPredicateFunc) (int, error) {
+	t.Helper()
+	ft := newFaultInjectionTransport(nil, f)
+	client := &http.Client{Transport: &retryTransport{
+		internal:        ft,
+		retryPredicates: predicates,
+		jitter:          testResilienceNoWait,
+	}}
+
+	resp, err := client.Get("https://example.googleapis.com/v1/projects/p/things/t")
+	if err == nil {
+		err = googleapi.CheckResponse(resp)
+		resp.Body.Close()
+	}
+	return ft.Attempts(), err
+}
+
+// Drives each retry predicate through the retry transport. Faults are injected
+// once, so a retried request succeeds on its second attempt and an aborted
+// request fails after its first. Cases with a predicate are also run without
+// it, to check that the predicate is what makes the error retryable.
+func TestRetryPredicates_faultInjection(t *testing.T) {
+	cases := map[string]struct {
+		Fault     injectedFault
+		Predicate RetryErrorPredicateFunc
+		Retry     bool
+	}{
+		// Default predicates
+		"connection reset": {
+			Fault: injectedFault{ConnectionReset: true},
+			Retry: true,
+		},
+		"network timeout": {
+			Fault: injectedFault{Timeout: true},
+			Retry: true,
+		},
+		// The request may already have been applied when its response is cut
+		// short, so the retry transport doesn't retry it. See
+		// TestRetryTransport_truncatedBody for successful responses.
+		"truncated error body": {
+			Fault: injectedFault{Status: 400, TruncateBody: true},
+			Retry: false,
+		},
+		"delayed 503": {
+			Fault: injectedFault{Status: 503, Delay: 10 * time.Millisecond},
+			Retry: true,
+		},
+		"429": {
+			Fault: injectedFault{Status: 429, Reason: "rateLimitExceeded"},
+			Retry: true,
+		},
+		"500": {
+			Fault: injectedFault{Status: 500},
+			Retry: true,
+		},
+		"502": {
+			Fault: injectedFault{Status: 502},
+			Retry: true,
+		},
+		"503": {
+			Fault: injectedFault{Status: 503},
+			Retry: true,
+		},
+		"504": {
+			Fault: injectedFault{Status: 504},
+			Retry: false,
+		},
+		"400": {
+			Fault: injectedFault{Status: 400, Reason: "invalid"},
+			Retry: false,
+		},
+		"401": {
+			Fault: injectedFault{Status: 401},
+			Retry: false,
+		},
+		"403": {
+			Fault: injectedFault{Status: 403, Reason: "forbidden"},
+			Retry: false,
+		},
+		"404": {
+			Fault: injectedFault{Status: 404, Reason: "notFound"},
+			Retry: false,
+		},
+		"409": {
+			Fault: injectedFault{Status: 409, Reason: "alreadyExists"},
+			Retry: false,
+		},
+		"409 operation in progress": {
+			Fault: injectedFault{Status: 409, Reason: "operationInProgress"},
+			Retry: true,
+		},
+		"subnetwork not ready": {
+			Fault: injectedFault{Status: 400, Reason: "resourceNotReady", Message: "The resource 'projects/p/regions/r/subnetworks/s' is not ready"},
+			Retry: true,
+		},
+		"network not ready": {
+			Fault: injectedFault{Status: 400, Reason: "resourceNotReady", Message: "The resource 'projects/p/global/networks/n' is not ready"},
+			Retry: true,
+		},
+		"403 quota exceeded per minute": {
+			Fault: injectedFault{Status: 403, Reason: "rateLimitExceeded", Message: "Quota exceeded for quota metric 'Queries' and limit 'Queries per minute' of service 'compute.googleapis.com' for consumer 'project_number:1'."},
+			Retry: true,
+		},
+		"403 quota exceeded per day": {
+			Fault: injectedFault{Status: 403, Reason: "rateLimitExceeded", Message: "Quota exceeded for quota metric 'Queries' and limit 'Queries per day' of service 'compute.googleapis.com' for consumer 'project_number:1'."},
+			Retry: false,
+		},
+
+		// Additional predicates
+		"code repository index not ready": {
+			Fault:     injectedFault{Status: 409, Message: "parent resource not in ready state"},
+			Predicate: IsCodeRepositoryIndexUnreadyError,
+			Retry:     true,
+		},
+		"fingerprint mismatch": {
+			Fault:     injectedFault{Status: 412, Reason: "conditionNotMet", Message: "Supplied fingerprint does not match current metadata fingerprint."},
+			Predicate: IsFingerprintError,
+			Retry:     true,
+		},
+		"412 without fingerprint": {
+			Fault:     injectedFault{Status: 412, Reason: "conditionNotMet", Message: "Precondition check failed."},
+			Predicate: IsFingerprintError,
+			Retry:     false,
+		},
+		"iam member missing": {
+			Fault:     injectedFault{Status: 400, Message: "Service account does not have permission to access the bucket"},
+			Predicate: IamMemberMissing,
+			Retry:     true,
+		},
+		"sql operation in progress": {
+			Fault:     injectedFault{Status: 409, Message: "Operation failed because another operation was already in progress."},
+			Predicate: IsSqlOperationInProgressError,
+			Retry:     true,
+		},
+		"sql instance already exists": {
+			Fault:     injectedFault{Status: 409, Reason: "instanceAlreadyExists"},
+			Predicate: IsSqlOperationInProgressError,
+			Retry:     false,
+		},
+		"service usage activation in progress": {
+			Fault:     injectedFault{Status: 400, Message: "Another activation or deactivation is in progress"},
+			Predicate: ServiceUsageServiceBeingActivated,
+			Retry:     true,
+		},
+		"service usage internal error 160009": {
+			Fault:     injectedFault{Status: 400, Message: "Request encountered internal error (code 160009) with failed services [container.googleapis.com]"},
+			Predicate: ServiceUsageInternalError160009,
+			Retry:     true,
+		},
+		"bigquery iam quota": {
+			Fault:     injectedFault{Status: 403, Reason: "rateLimitExceeded", Message: "Exceeded rate limits: too many IAM policy changes"},
+			Predicate: IsBigqueryIAMQuotaError,
+			Retry:     true,
+		},
+		"repository group queue": {
+			Fault:     injectedFault{Status: 409, Message: "Unable to queue the operation"},
+			Predicate: IsRepositoryGroupQueueError,
+			Retry:     true,
+		},
+		"monitoring concurrent edits": {
+			Fault:     injectedFault{Status: 409, Message: "Too many concurrent edits to the project configuration."},
+			Predicate: IsMonitoringConcurrentEditError,
+			Retry:     true,
+		},
+		"monitoring could not fulfill": {
+			Fault:     injectedFault{Status: 409, Message: "Could not fulfill the request."},
+			Predicate: IsMonitoringConcurrentEditError,
+			Retry:     true,
+		},
+		"monitoring permission": {
+			Fault:     injectedFault{Status: 403},
+			Predicate: IsMonitoringPermissionError,
+			Retry:     true,
+		},
+		"eventarc channel": {
+			Fault:     injectedFault{Status: 403, Message: "The caller does not have permission"},
+			Predicate: EventarcChannel403Retry,
+			Retry:     true,
+		},
+		"kms pending generation": {
+			Fault:     injectedFault{Status: 400, Message: "The request cannot be fulfilled. Resource is in state PENDING_GENERATION."},
+			Predicate: IsCryptoKeyVersionsPendingGeneration,
+			Retry:     true,
+		},
+		"not found retryable": {
+			Fault:     injectedFault{Status: 404, Reason: "notFound"},
+			Predicate: IsNotFoundRetryableError("read"),
+			Retry:     true,
+		},
+		"peering operation in progress": {
+			Fault:     injectedFault{Status: 400, Message: "There is a peering operation in progress on the local or peer network."},
+			Predicate: IsPeeringOperationInProgress,
+			Retry:     true,
+		},
+		"datastore index contention": {
+			Fault:     injectedFault{Status: 409, Message: "too much contention on these datastore entities"},
+			Predicate: DatastoreIndex409Contention,
+			Retry:     true,
+		},
+		"firestore field data changed": {
+			Fault:     injectedFault{Status: 409, Message: "Please retry, underlying data changed"},
+			Predicate: FirestoreField409RetryUnderlyingDataChanged,
+			Retry:     true,
+		},
+		"firestore index contention": {
+			Fault:     injectedFault{Status: 409, Message: "Aborted due to cross-transaction contention"},
+			Predicate: FirestoreIndex409Retry,
+			Retry:     true,
+		},
+		"iap client aborted": {
+			Fault:     injectedFault{Status: 409, Message: "Operation was aborted"},
+			Predicate: IapClient409Operation,
+			Retry:     true,
+		},
+		"healthcare dataset not initialized": {
+			Fault:     injectedFault{Status: 404, Message: "Dataset not initialized"},
+			Predicate: HealthcareDatasetNotInitialized,
+			Retry:     true,
+		},
+		"cloud run creation conflict": {
+			Fault:     injectedFault{Status: 409, Reason: "alreadyExists"},
+			Predicate: IsCloudRunCreationConflict,
+			Retry:     true,
+		},
+		"iam service account not found": {
+			Fault:     injectedFault{Status: 400, Message: "Service account sa@p.iam.gserviceaccount.com does not exist."},
+			Predicate: IamServiceAccountNotFound,
+			Retry:     true,
+		},
+		"apigee resource locked": {
+			Fault:     injectedFault{Status: 400, Message: "The resource is locked by another operation"},
+			Predicate: IsApigeeRetryableError,
+			Retry:     true,
+		},
+		"dataflow job state": {
+			Fault:     injectedFault{Status: 404, Message: "Job must be in RUNNING OR DRAINING state"},
+			Predicate: IsDataflowJobUpdateRetryableError,
+			Retry:     true,
+		},
+		"pubsub project not ready": {
+			Fault:     injectedFault{Status: 400, Message: "Please retry this operation"},
+			Predicate: PubsubTopicProjectNotReady,
+			Retry:     true,
+		},
+		"app engine operation in progress": {
+			Fault:     injectedFault{Status: 409, Message: "Operation is already in progress"},
+			Predicate: IsAppEngineRetryableError,
+			Retry:     true,
+		},
+		"app engine p4sa": {
+			Fault:     injectedFault{Status: 404, Message: "Unable to retrieve P4SA from GAIA"},
+			Predicate: IsAppEngineRetryableError,
+			Retry:     true,
+		},
+		"orgpolicy parent not ready": {
+			Fault:     injectedFault{Status: 403, Message: "Permission 'orgpolicy.policy.get' denied on resource '//cloudresourcemanager.googleapis.com/projects/my-project/policies/gcp.resourceLocations' (or it may not exist)."},
+			Predicate: IsOrgpolicyRetryableError,
+			Retry:     true,
+		},
+		"swg autogen router not ready": {
+			Fault:     injectedFault{Status: 400, Message: "The resource 'projects/p/regions/r/routers/swg-autogen-router' is not ready"},
+			Predicate: IsSwgAutogenRouterRetryable,
+			Retry:     true,
+		},
+		"iam service account forbidden": {
+			Fault:     injectedFault{Status: 403, Message: "Permission 'iam.serviceAccounts.get' denied on resource (or it may not exist)."},
+			Predicate: IsForbiddenIamServiceAccountRetryableError("read"),
+			Retry:     true,
+		},
+		"external ip service not active": {
+			Fault:     injectedFault{Status: 400, Message: "External IP address network service is not active in the provided network policy"},
+			Predicate: ExternalIpServiceNotActive,
+			Retry:     true,
+		},
+		"site verification token": {
+			Fault:     injectedFault{Status: 400, Message: "The verification token could not be found"},
+			Predicate: IsSiteVerificationRetryableError,
+			Retry:     true,
+		},
+		"429 rate limit exceeded": {
+			Fault:     injectedFault{Status: 429, Reason: "RATE_LIMIT_EXCEEDED"},
+			Predicate: Is429RetryableQuotaError,
+			Retry:     true,
+		},
+	}
+
+	for tn, tc := range cases {
+		var predicates []RetryErrorPredicateFunc
+		if tc.Predicate != nil {
+			predicates = append(predicates, tc.Predicate)
+		}
+		attempts, err := testResilienceRoundTrip(t, tc.Fault, predicates...)
+		if tc.Retry && (attempts != 2 || err != nil) {
+			t.Errorf("bad: %s, expected a retry and success, got %d attempts (%v)", tn, attempts, err)
+		}
+		if !tc.Retry && (attempts != 1 || err == nil) {
+			t.Errorf("bad: %s, expected an abort, got %d attempts (%v)", tn, attempts, err)
+		}
+
+		if tc.Predicate == nil || !tc.Retry {
+			continue
+		}
+		// 429s are retried by default regardless of the predicate.
+		if tc.Fault.Status == 429 {
+			continue
+		}
+		if attempts, err := testResilienceRoundTrip(t, tc.Fault); attempts != 1 || err == nil {
+			t.Errorf("bad: %s, expected an abort without the predicate, got %d attempts (%v)", tn, attempts, err)
+		}
+	}
+}
+
+// A successful response whose body is cut short isn't retried, as the request
+// may already have been applied. Reading the body fails instead.
+func TestRetryTransport_truncatedBody(t *testing.T) {
+	ft := newFaultInjectionTransport(nil, injectedFault{TruncateBody: true})
+	client := &http.Client{Transport: &retryTransport{
+		internal: ft,
+		jitter:   testResilienceNoWait,
+	}}
+
+	resp, err := client.Post("https://example.googleapis.com/v1/projects/p/things", "application/json", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+	if ft.Attempts() != 1 {
+		t.Errorf("expected 1 attempt, got %d", ft.Attempts())
+	}
+	if _, err := io.ReadAll(resp.Body); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected an unexpected EOF reading the body, got %v", err)
+	}
+}
+
+// Checks the predicates that can't be told apart by whether the retry
+// transport retries.
+func TestRetryPredicates_faultInjectionDirect(t *testing.T) {
+	// 429s are always retried by the retry transport, so only the predicates'
+	// verdict on the error is checked.
+	client := &http.Client{Transport: newFaultInjectionTransport(nil, injectedFault{Status: 429, Reason: "RESOURCE_EXHAUSTED"})}
+	resp, err := client.Get("https://example.googleapis.com/v1/things")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	err = googleapi.CheckResponse(resp)
+	resp.Body.Close()
+	if ok, _ := Is429RetryableQuotaError(err); ok {
+		t.Errorf("expected a 429 without RATE_LIMIT_EXCEEDED not to match, got %v", err)
+	}
+	if ok, _ := Is429QuotaError(err); !ok {
+		t.Errorf("expected a 429 to match Is429QuotaError, got %v", err)
+	}
+
+	// Fingerprint errors are retried by MetadataRetryWrapper rather than a
+	// retry predicate.
+	ft := newFaultInjectionTransport(nil, injectedFault{Status: 412, Count: 2, Message: "Invalid fingerprint."})
+	client = &http.Client{Transport: ft}
+	err = MetadataRetryWrapper(func() error {
+		resp, err := client.Post("https://compute.googleapis.com/compute/v1/projects/p/setCommonInstanceMetadata", "application/json", nil)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return googleapi.CheckResponse(resp)
+	})
+	if err != nil || ft.Attempts() != 3 {
+		t.Errorf("expected success after 2 fingerprint mismatches, got %d attempts (%v)", ft.Attempts(), err)
+	}
+}
+
+// A delay longer than the request's deadline fails the request without a
+// retry, as the retry transport can't wait for another attempt.
+func TestRetryTransport_faultInjectionDeadline(t *testing.T) {
+	ft := newFaultInjectionTransport(nil, injectedFault{Status: 503, Delay: time.Second})
+	client := &http.Client{Transport: &retryTransport{
+		internal: ft,
+		jitter:   testResilienceNoWait,
+	}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://example.googleapis.com/v1/things", nil)
+	if err != nil {
+		t.Fatalf("unable to construct request: %s", err)
+	}
+	if _, err := client.Do(req); err == nil {
+		t.Errorf("expected a deadline error")
+	}
+	if ft.Attempts() != 1 {
+		t.Errorf("expected 1 attempt, got %d", ft.Attempts())
+	}
+}
+
+// SendRequest retries through Retry as well, which also honors abort
+// predicates. No retry transport is used, so Retry sees every fault.
+func TestSendRequest_faultInjection(t *testing.T) {
+	cases := map[string]struct {
+		Fault    injectedFault
+		Abort    []RetryErrorPredicateFunc
+		Retry    []RetryErrorPredicateFunc
+		Attempts int
+		Code     int
+	}{
+		"retried": {
+			Fault:    injectedFault{Status: 503},
+			Attempts: 2,
+		},
+		"aborted": {
+			Fault:    injectedFault{Status: 429},
+			Abort:    []RetryErrorPredicateFunc{Is429QuotaError},
+			Attempts: 1,
+			Code:     429,
+		},
+		"not retryable": {
+			Fault:    injectedFault{Status: 400, Message: "Please retry this operation"},
+			Attempts: 1,
+			Code:     400,
+		},
+		"additional predicate": {
+			Fault:    injectedFault{Status: 400, Message: "Please retry this operation"},
+			Retry:    []RetryErrorPredicateFunc{PubsubTopicProjectNotReady},
+			Attempts: 2,
+		},
+		"abort takes precedence": {
+			Fault:    injectedFault{Status: 404},
+			Abort:    []RetryErrorPredicateFunc{IsNotFoundRetryableError("abort")},
+			Retry:    []RetryErrorPredicateFunc{IsNotFoundRetryableError("retry")},
+			Attempts: 1,
+			Code:     404,
+		},
+		"truncated error body": {
+			Fault:    injectedFault{Status: 503, TruncateBody: true},
+			Attempts: 2,
+		},
+	}
+
+	for tn, tc := range cases {
+		ft := newFaultInjectionTransport(nil, tc.Fault)
+		_, err := SendRequest(SendRequestOptions{
+			Config:               &Config{Client: &http.Client{Transport: ft}},
+			Method:               "GET",
+			RawURL:               "https://example.googleapis.com/v1/things/t",
+			Timeout:              5 * time.Second,
+			ErrorRetryPredicates: tc.Retry,
+			ErrorAbortPredicates: tc.Abort,
+		})
+		if ft.Attempts() != tc.Attempts {
+			t.Errorf("bad: %s, expected %d attempts, got %d", tn, tc.Attempts, ft.Attempts())
+		}
+		if tc.Code == 0 && err != nil {
+			t.Errorf("bad: %s, unexpected error %s", tn, err)
+		}
+		if tc.Code != 0 && !IsGoogleApiErrorWithCode(err, tc.Code) {
+			t.Errorf("bad: %s, expected a %d error, got %v", tn, tc.Code, err)
+		}
+	}
+}
+
+func TestRetryWithTargetOccurrences_faultInjection(t *testing.T) {
+	cases := map[string]struct {
+		Faults   []injectedFault
+		Check    PollCheckResponseFunc
+		Attempts int
+		Code     int
+	}{
+		"pending then found": {
+			Faults:   []injectedFault{{Status: 404}},
+			Check:    PollCheckForExistence,
+			Attempts: 3,
+		},
+		"error aborts": {
+			Faults:   []injectedFault{{Status: 404}, {Status: 403}},
+			Check:    PollCheckForExistence,
+			Attempts: 2,
+			Code:     403,
+		},
+		"absent": {
+			Faults:   []injectedFault{{Status: 404, Count: -1}},
+			Check:    PollCheckForAbsence,
+			Attempts: 2,
+		},
+	}
+
+	for tn, tc := range cases {
+		ft := newFaultInjectionTransport(nil, tc.Faults...)
+		config := &Config{Client: &http.Client{Transport: ft}}
+		pollRead := func() (map[string]interface{}, error) {
+			return SendRequest(SendRequestOptions{
+				Config: config,
+				Method: "GET",
+				RawURL: "https://example.googleapis.com/v1/things/t",
+			})
+		}
+
+		err := RetryWithTargetOccurrences(10*time.Second, 2, func() *retry.RetryError {
+			return tc.Check(pollRead())
+		})
+		if ft.Attempts() != tc.Attempts {
+			t.Errorf("bad: %s, expected %d polls, got %d", tn, tc.Attempts, ft.Attempts())
+		}
+		if tc.Code == 0 && err != nil {
+			t.Errorf("bad: %s, unexpected error %s", tn, err)
+		}
+		if tc.Code != 0 && !IsGoogleApiErrorWithCode(err, tc.Code) {
+			t.Errorf("bad: %s, expected a %d error, got %v", tn, tc.Code, err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package transport
 
 import (
+	"net/http"
 	"net/url"
 	"testing"
 	"time"
@@ -210,3 +211,52 @@ func TestRetryWithPolling_retriedAndFailed(t *testing.T) {
 		t.Errorf("expected error function to be called exactly twice, but was called %d times", retryCount)
 	}
 }
+
+func TestRetry_honorsServerRetryDelay(t *testing.T) {
+	runCount := 0
+	retryFunc := func() error {
+		runCount++
+		if runCount == 1 {
+			return &googleapi.Error{
+				Code:   429,
+				Header: http.Header{"Retry-After": []string{"1"}},
+			}
+		}
+		return nil
+	}
+	start := time.Now()
+	err := Retry(RetryOptions{
+		RetryFunc: retryFunc,
+		Timeout:   1 * time.Minute,
+	})
+	if err != nil {
+		t.Errorf("unexpected error: got '%v' want 'nil'", err)
+	}
+	if runCount != 2 {
+		t.Errorf("expected the retryFunc to be called 2 times, instead was called %v time(s)", runCount)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected to wait at least 1s as requested by the server, waited %s", elapsed)
+	}
+}
+
+func TestRetry_serverRetryDelayExceedsTimeout(t *testing.T) {
+	runCount := 0
+	retryFunc := func() error {
+		runCount++
+		return &googleapi.Error{
+			Code:   429,
+			Header: http.Header{"Retry-After": []string{"60"}},
+		}
+	}
+	err := Retry(RetryOptions{
+		RetryFunc: retryFunc,
+		Timeout:   time.Second,
+	})
+	if err == nil || err.(*googleapi.Error).Code != 429 {
+		t.Errorf("unexpected error retrying: %v", err)
+	}
+	if runCount != 1 {
+		t.Errorf("expected the retryFunc to be called once, instead was called %v time(s)", runCount)
+	}
+}
@@ -34,6 +34,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -74,6 +75,9 @@ func (t *retryTransport) WithAddedPredicates(predicates ...RetryErrorPredicateFu
 type retryTransport struct {
 	retryPredicates []RetryErrorPredicateFunc
 	internal        http.RoundTripper
+
+	// Randomizes the wait between retries. Defaults to full jitter when nil.
+	jitter func(time.Duration) time.Duration
 }
 
 // RoundTrip implements the RoundTripper interface method.
@@ -94,8 +98,7 @@ func (t *retryTransport) RoundTrip(req *http.Request) (resp *http.Response, resp
 	}
 
 	attempts := 0
-	backoff := time.Millisecond * 500
-	nextBackoff := time.Millisecond * 500
+	backoff := newRetryBackoff(retryTransportMaxBackoff, t.jitter)
 
 	// VCR depends on the original request body being consumed, so
 	// consume here. Since this won't affect the request itself,
@@ -105,7 +108,7 @@ func (t *retryTransport) RoundTrip(req *http.Request) (resp *http.Response, resp
 		log.Printf("[WARN] Retry Transport: Consuming original request body failed: %v", err)
 	}
 
-	log.Printf("[DEBUG] Retry Transport: starting RoundTrip retry loop")
+	log.Printf("[DEBUG] Retry Transport: starting RoundTrip retry loop for %s %s", req.Method, req.URL)
 Retry:
 	for {
 		// RoundTrip contract says request body can/will be consumed, so we need to
@@ -134,22 +137,27 @@ Retry:
 			break Retry
 		}
 
-		log.Printf("[DEBUG] Retry Transport: Waiting %s before trying request again", backoff)
+		wait, fromServer := backoff.Next(retryErr.Err)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			log.Printf("[DEBUG] Retry Transport: Stopping retries, waiting %s would exceed the request deadline", wait)
+			break Retry
+		}
+		if fromServer {
+			log.Printf("[DEBUG] Retry Transport: Waiting %s as requested by the server before retry %d of %s %s", wait, attempts, req.Method, req.URL)
+		} else {
+			log.Printf("[DEBUG] Retry Transport: Waiting %s before retry %d of %s %s", wait, attempts, req.Method, req.URL)
+		}
 		select {
 		case <-ctx.Done():
 			log.Printf("[DEBUG] Retry Transport: Stopping retries, context done: %v", ctx.Err())
 			break Retry
-		case <-time.After(backoff):
-			log.Printf("[DEBUG] Retry Transport: Finished waiting %s before next retry", backoff)
-
-			// Fibonnaci backoff - 0.5, 1, 1.5, 2.5, 4, 6.5, 10.5, ...
-			lastBackoff := backoff
-			backoff = backoff + nextBackoff
-			nextBackoff = lastBackoff
+		case <-time.After(wait):
+			log.Printf("[DEBUG] Retry Transport: Finished waiting %s before next retry", wait)
+			recordAuditRetry(ctx)
 			continue
 		}
 	}
-	log.Printf("[DEBUG] Retry Transport: Returning after %d attempts", attempts)
+	log.Printf("[DEBUG] Retry Transport: Returning after %d attempts (%d retries) for %s %s", attempts, attempts-1, req.Method, req.URL)
 	return resp, respErr
 }
 
@@ -180,32 +188,59 @@ func copyHttpRequest(req *http.Request) (*http.Request, error) {
 // errors in the response, and determines whether there is a retryable error.
 // in response/response error.
 func (t *retryTransport) checkForRetryableError(resp *http.Response, respErr error) *retry.RetryError {
-	var errToCheck error
+	var errToCheck, errToReturn error
 
 	if respErr != nil {
 		errToCheck = respErr
+		errToReturn = respErr
 	} else {
 		respToCheck := *resp
+		respToReturn := *resp
 		// The RoundTrip contract states that the HTTP response/response error
 		// returned cannot be edited. We need to consume the Body to check for
-		// errors, so we need to create a copy if the Response has a body.
+		// errors, so we read it once and hand out identical copies to the
+		// returned response and the ones being checked.
 		if resp.Body != nil && resp.Body != http.NoBody {
+			body, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				// Reading the returned body fails the same way.
+				resp.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(body), errorReader{err}))
+				return retry.NonRetryableError(fmt.Errorf("unable to check response for error: %v", err))
+			}
+			resp.Body = ioutil.NopCloser(bytes.NewReader(body))
 			// Use httputil.DumpResponse since the only important info is
-			// error code and messages in the response body.
-			dumpBytes, err := httputil.DumpResponse(resp, true)
+			// error code and messages in the response body. The predicates
+			// match on its Body, so the parsed message and details aren't
+			// exposed to them.
+			respToCheck.Body = ioutil.NopCloser(bytes.NewReader(body))
+			dumpBytes, err := httputil.DumpResponse(&respToCheck, true)
 			if err != nil {
 				return retry.NonRetryableError(fmt.Errorf("unable to check response for error: %v", err))
 			}
 			respToCheck.Body = ioutil.NopCloser(bytes.NewReader(dumpBytes))
+			// The body is also parsed in full so that error details such as
+			// google.rpc.RetryInfo are available to the retry backoff.
+			respToReturn.Body = ioutil.NopCloser(bytes.NewReader(body))
 		}
 		errToCheck = googleapi.CheckResponse(&respToCheck)
+		errToReturn = googleapi.CheckResponse(&respToReturn)
 	}
 
 	if errToCheck == nil {
 		return nil
 	}
 	if IsRetryableError(errToCheck, t.retryPredicates, nil) {
-		return retry.RetryableError(errToCheck)
+		return retry.RetryableError(errToReturn)
 	}
-	return retry.NonRetryableError(errToCheck)
+	return retry.NonRetryableError(errToReturn)
+}
+
+// errorReader is a reader that always fails with err.
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read([]byte) (int, error) {
+	return 0, r.err
 }
@@ -26,6 +26,8 @@ func setUpRetryTransportServerClient(hf http.Handler) (*httptest.Server, *http.C
 	client.Transport = &retryTransport{
 		internal:        http.DefaultTransport,
 		retryPredicates: []RetryErrorPredicateFunc{testRetryTransportRetryPredicate},
+		// Wait the full backoff so that timings in tests are deterministic.
+		jitter: testRetryTransportNoJitter,
 	}
 	return ts, client
 }
@@ -124,6 +126,103 @@ func TestRetryTransport_SuccessWithBody(t *testing.T) {
 	testRetryTransport_checkBody(t, resp, body)
 }
 
+// Check that the delay requested by the server is honored
+func TestRetryTransport_HonorsRetryAfter(t *testing.T) {
+	attempts := 0
+	ts, client := setUpRetryTransportServerClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(testRetryTransportCodeRetry)
+			return
+		}
+		w.WriteHeader(testRetryTransportCodeSuccess)
+	}))
+	defer ts.Close()
+
+	ctx, cc := context.WithTimeout(context.Background(), time.Second*5)
+	defer cc()
+	req, err := http.NewRequestWithContext(ctx, "GET", ts.URL, nil)
+	if err != nil {
+		t.Fatalf("unable to construct err: %v", err)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	testRetryTransport_checkSuccess(t, resp, err)
+	if elapsed := time.Since(start); elapsed < 2*time.Second {
+		t.Errorf("expected to wait at least 2s as requested by the server, waited %s", elapsed)
+	}
+}
+
+// Check that retries stop early if the delay requested by the server exceeds the deadline
+func TestRetryTransport_RetryAfterExceedsDeadline(t *testing.T) {
+	attempts := 0
+	ts, client := setUpRetryTransportServerClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(testRetryTransportCodeRetry)
+	}))
+	defer ts.Close()
+
+	ctx, cc := context.WithTimeout(context.Background(), time.Second*2)
+	defer cc()
+	req, err := http.NewRequestWithContext(ctx, "GET", ts.URL, nil)
+	if err != nil {
+		t.Fatalf("unable to construct err: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	testRetryTransport_checkFailedWhileRetrying(t, resp, err)
+	if attempts != 1 {
+		t.Errorf("expected a single attempt, got %d", attempts)
+	}
+}
+
+// Check that predicates are given the response dump, while the error returned
+// for the backoff has the parsed message and details
+func TestRetryTransport_checkForRetryableError(t *testing.T) {
+	body := `{"error": {"code": 400, "message": "Quota exceeded", "details": [` +
+		`{"@type": "type.googleapis.com/google.rpc.ErrorInfo", "reason": "RATE_LIMIT_EXCEEDED"},` +
+		`{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "3s"}]}}`
+	var checked *googleapi.Error
+	transport := &retryTransport{
+		retryPredicates: []RetryErrorPredicateFunc{
+			func(err error) (bool, string) {
+				checked = err.(*googleapi.Error)
+				return true, "always retried"
+			},
+		},
+	}
+	resp := &http.Response{
+		StatusCode: 400,
+		Status:     "400 Bad Request",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+
+	retryErr := transport.checkForRetryableError(resp, nil)
+	if retryErr == nil || !retryErr.Retryable {
+		t.Fatalf("expected a retryable error, got %v", retryErr)
+	}
+	if checked.Message != "" || len(checked.Details) != 0 || !strings.Contains(checked.Body, "400 Bad Request") || !strings.Contains(checked.Body, "Quota exceeded") {
+		t.Errorf("expected the predicate to be given the response dump, got %#v", checked)
+	}
+	gerr, ok := retryErr.Err.(*googleapi.Error)
+	if !ok || gerr.Message != "Quota exceeded" || len(gerr.Details) != 2 {
+		t.Errorf("expected the parsed error, got %#v", retryErr.Err)
+	}
+	if delay := serverRetryDelay(retryErr.Err); delay != 3*time.Second {
+		t.Errorf("expected a server retry delay of 3s, got %s", delay)
+	}
+	// The returned response's body is unchanged
+	if b, _ := ioutil.ReadAll(resp.Body); string(b) != body {
+		t.Errorf("expected body %q, got %q", body, b)
+	}
+}
+
 // Check for no and no retries if the request has no getBody (it should only run once)
 func TestRetryTransport_DoesNotRetryEmptyGetBody(t *testing.T) {
 	msg := "non empty body"
@@ -295,6 +394,10 @@ func testRetryTransport_checkBody(t *testing.T, resp *http.Response, expectedMsg
 	}
 }
 
+func testRetryTransportNoJitter(d time.Duration) time.Duration {
+	return d
+}
+
 // ERROR RETRY PREDICATE
 // Retries 500.
 func testRetryTransportRetryPredicate(err error) (bool, string) {
@@ -52,16 +52,34 @@ func Retry(opt RetryOptions) error {
 		return err
 	}
 
-	return retry.Retry(opt.Timeout, func() *retry.RetryError {
+	// Retry with a capped, jittered backoff, honoring delays requested by the
+	// server. On timeout the last error is returned.
+	backoff := newRetryBackoff(retryMaxBackoff, nil)
+	deadline := time.Now().Add(opt.Timeout)
+	for retries := 0; ; retries++ {
 		err := opt.RetryFunc()
 		if err == nil {
+			if retries > 0 {
+				log.Printf("[DEBUG] Retry: succeeded after %d retries", retries)
+			}
 			return nil
 		}
-		if IsRetryableError(err, opt.ErrorRetryPredicates, opt.ErrorAbortPredicates) {
-			return retry.RetryableError(err)
+		if !IsRetryableError(err, opt.ErrorRetryPredicates, opt.ErrorAbortPredicates) {
+			return err
 		}
-		return retry.NonRetryableError(err)
-	})
+
+		wait, fromServer := backoff.Next(err)
+		if time.Now().Add(wait).After(deadline) {
+			log.Printf("[DEBUG] Retry: giving up after %d retries, timeout of %s reached", retries, opt.Timeout)
+			return err
+		}
+		if fromServer {
+			log.Printf("[DEBUG] Retry: waiting %s as requested by the server before retry %d", wait, retries+1)
+		} else {
+			log.Printf("[DEBUG] Retry: waiting %s before retry %d", wait, retries+1)
+		}
+		time.Sleep(wait)
+	}
 }
 
 func IsRetryableError(topErr error, retryPredicates, abortPredicates []RetryErrorPredicateFunc) bool {
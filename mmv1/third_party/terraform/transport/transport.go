@@ -2,6 +2,7 @@ package transport
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -27,12 +28,23 @@ type SendRequestOptions struct {
 	Headers              http.Header
 	ErrorRetryPredicates []RetryErrorPredicateFunc
 	ErrorAbortPredicates []RetryErrorPredicateFunc
+	// Context of the request, if any. Cancelling it aborts the request.
+	Context context.Context
+	// Address of the resource the request is made for, recorded in the audit log.
+	// See AuditResourceAddress.
+	ResourceAddress string
+	// Makes the request conditional on the resource not having changed since
+	// it was last read, if set. See Precondition.
+	Precondition *Precondition
 }
 
 func SendRequest(opt SendRequestOptions) (map[string]interface{}, error) {
 	if opt.Config == nil || opt.Config.Client == nil {
 		return nil, fmt.Errorf("client is nil for request to %s", opt.RawURL)
 	}
+	if opt.Precondition != nil {
+		return sendRequestWithPrecondition(opt)
+	}
 
 	reqHeaders := opt.Headers
 	if reqHeaders == nil {
@@ -72,7 +84,14 @@ func SendRequest(opt SendRequestOptions) (map[string]interface{}, error) {
 			if err != nil {
 				return err
 			}
-			req, err := http.NewRequest(opt.Method, u, &buf)
+			ctx := opt.Context
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			if opt.ResourceAddress != "" {
+				ctx = ContextWithAuditResource(ctx, opt.ResourceAddress)
+			}
+			req, err := http.NewRequestWithContext(ctx, opt.Method, u, &buf)
 			if err != nil {
 				return err
 			}
@@ -6,12 +6,38 @@ import (
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
 )
 
+// Severity is how a breaking change should be treated by tools consuming it.
+type Severity string
+
+const (
+	// SeverityError changes block a release.
+	SeverityError Severity = "error"
+	// SeverityWarning changes should be reviewed but don't block a release.
+	SeverityWarning Severity = "warning"
+	// SeverityInfo changes are reported for information only.
+	SeverityInfo Severity = "info"
+	// SeveritySkipped marks rules that couldn't be checked, rather than
+	// changes, as returned by SkippedRules.
+	SeveritySkipped Severity = "skipped"
+)
+
+// Severities lists every severity, from the most to the least severe.
+var Severities = []Severity{SeverityError, SeverityWarning, SeverityInfo, SeveritySkipped}
+
 type BreakingChange struct {
-	Resource               string
+	// Kind of the changed schema, such as "resource" or "data source".
+	Kind     diff.Kind `json:",omitempty"`
+	Resource string
+	// Field is the path of the changed field, if the change is about a field.
 	Field                  string
 	Message                string
 	DocumentationReference string
 	RuleName               string
+	OldValue               string `json:",omitempty"`
+	NewValue               string `json:",omitempty"`
+	Severity               Severity
+	// Reason the severity was overridden by a Config.
+	Reason string `json:",omitempty"`
 }
 
 const breakingChangesPath = "breaking-changes/breaking-changes"
@@ -20,15 +46,52 @@ func NewBreakingChange(message, identifier string) BreakingChange {
 	return BreakingChange{
 		Message:                message,
 		DocumentationReference: fmt.Sprintf("https://googlecloudplatform.github.io/magic-modules/%s#%s", breakingChangesPath, identifier),
+		RuleName:               identifier,
+		Severity:               SeverityError,
+	}
+}
+
+// SDKDetailRules are the identifiers of the rules relying on the ForceNew,
+// default, validation, diff suppression or importer of schemas, which the
+// JSON schema written by Terraform doesn't record.
+var SDKDetailRules = []string{
+	FieldNewOptionalFieldWithDefault.Identifier,
+	FieldDefaultModification.Identifier,
+	FieldRemovingDiffSuppress.Identifier,
+	FieldBecomingForceNew.Identifier,
+	FieldAddingValidation.Identifier,
+	ResourceConfigRemovingImporter.Identifier,
+}
+
+// SkippedRules returns an entry of SeveritySkipped for each of the given
+// rules, which couldn't be checked for the given reason.
+func SkippedRules(identifiers []string, reason string) []BreakingChange {
+	var skipped []BreakingChange
+	for _, identifier := range identifiers {
+		bc := NewBreakingChange(fmt.Sprintf("Rule `%s` was skipped: %s", identifier, reason), identifier)
+		bc.Severity = SeveritySkipped
+		skipped = append(skipped, bc)
 	}
+	return skipped
 }
 
 func ComputeBreakingChanges(schemaDiff diff.SchemaDiff) []BreakingChange {
+	return computeBreakingChanges(diff.KindResource, schemaDiff)
+}
+
+// computeBreakingChanges computes the breaking changes of schemas of the given
+// kind, which messages name the schemas by.
+func computeBreakingChanges(kind diff.Kind, schemaDiff diff.SchemaDiff) []BreakingChange {
 	var breakingChanges []BreakingChange
 	for resource, resourceDiff := range schemaDiff {
 		for _, rule := range ResourceConfigDiffRules {
-			for _, message := range rule.Messages(resource, resourceDiff.ResourceConfig) {
-				breakingChanges = append(breakingChanges, NewBreakingChange(message, rule.Identifier))
+			for _, message := range rule.Messages(kind, resource, resourceDiff.ResourceConfig) {
+				bc := NewBreakingChange(message, rule.Identifier)
+				bc.Resource = resource
+				if rule.Values != nil {
+					bc.OldValue, bc.NewValue = rule.Values(resourceDiff.ResourceConfig)
+				}
+				breakingChanges = append(breakingChanges, bc)
 			}
 		}
 
@@ -38,19 +101,44 @@ func ComputeBreakingChanges(schemaDiff diff.SchemaDiff) []BreakingChange {
 		}
 
 		for _, rule := range ResourceDiffRules {
-			for _, message := range rule.Messages(resource, resourceDiff) {
-				breakingChanges = append(breakingChanges, NewBreakingChange(message, rule.Identifier))
+			for _, change := range rule.Changes(kind, resource, resourceDiff) {
+				bc := NewBreakingChange(change.Message, rule.Identifier)
+				bc.Resource = resource
+				bc.Field = change.Field
+				bc.OldValue = change.OldValue
+				bc.NewValue = change.NewValue
+				breakingChanges = append(breakingChanges, bc)
 			}
 		}
 
 		for field, fieldDiff := range resourceDiff.Fields {
 			for _, rule := range FieldDiffRules {
 				rd := schemaDiff[resource]
-				for _, message := range rule.Messages(resource, field, fieldDiff, rd) {
-					breakingChanges = append(breakingChanges, NewBreakingChange(message, rule.Identifier))
+				for _, message := range rule.Messages(kind, resource, field, fieldDiff, rd) {
+					bc := NewBreakingChange(message, rule.Identifier)
+					bc.Resource = resource
+					bc.Field = field
+					if rule.Values != nil {
+						bc.OldValue, bc.NewValue = rule.Values(fieldDiff)
+					}
+					breakingChanges = append(breakingChanges, bc)
 				}
 			}
 		}
 	}
 	return breakingChanges
 }
+
+// ComputeProviderBreakingChanges computes the breaking changes of every kind
+// of schema. Rules are shared by all kinds, with messages naming the kind of
+// the changed schema.
+func ComputeProviderBreakingChanges(providerSchemaDiff diff.ProviderSchemaDiff) []BreakingChange {
+	var breakingChanges []BreakingChange
+	for _, kind := range diff.Kinds {
+		for _, bc := range computeBreakingChanges(kind, providerSchemaDiff[kind]) {
+			bc.Kind = kind
+			breakingChanges = append(breakingChanges, bc)
+		}
+	}
+	return breakingChanges
+}
@@ -93,8 +93,11 @@ func TestComputeBreakingChanges(t *testing.T) {
 			newResourceMap: map[string]*schema.Resource{},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
 					Message:                "Resource `google-x` was either removed or renamed",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#resource-map-resource-removal-or-rename",
+					RuleName:               "resource-map-resource-removal-or-rename",
+					Severity:               SeverityError,
 				},
 			},
 		},
@@ -117,8 +120,33 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-b",
 					Message:                "Field `field-b` within resource `google-x` was either removed or renamed",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#resource-schema-field-removal-or-rename",
+					RuleName:               "resource-schema-field-removal-or-rename",
+					Severity:               SeverityError,
+				},
+			},
+		},
+		{
+			name: "field renamed with deprecated alias",
+			oldResourceMap: map[string]*schema.Resource{
+				"google-x": {
+					Schema: map[string]*schema.Schema{
+						"field-a": {Description: "beep", Required: true},
+						"field-b": {Description: "beep", Optional: true},
+					},
+				},
+			},
+			newResourceMap: map[string]*schema.Resource{
+				"google-x": {
+					Schema: map[string]*schema.Schema{
+						"field-a": {Description: "beep", Optional: true, Computed: true, Deprecated: "Use `field-c` instead.", ExactlyOneOf: []string{"field-a", "field-c"}},
+						"field-b": {Description: "beep", Optional: true, Computed: true, Deprecated: "Use `field-d` instead.", ConflictsWith: []string{"field-d"}},
+						"field-c": {Description: "beep", Optional: true, Computed: true, ExactlyOneOf: []string{"field-a", "field-c"}},
+						"field-d": {Description: "beep", Optional: true, Computed: true, ConflictsWith: []string{"field-b"}},
+					},
 				},
 			},
 		},
@@ -142,8 +170,14 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-a",
 					Message:                "Field `field-a` changed from optional to required on `google-x`",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#field-optional-to-required",
+					RuleName:               "field-optional-to-required",
+					OldValue:               "optional",
+					NewValue:               "required",
+					Severity:               SeverityError,
 				},
 			},
 		},
@@ -166,12 +200,22 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-a",
 					Message:                "Field `field-a` changed from optional to required on `google-x`",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#field-optional-to-required",
+					RuleName:               "field-optional-to-required",
+					OldValue:               "optional",
+					NewValue:               "required",
+					Severity:               SeverityError,
 				},
 				{
+					Resource:               "google-x",
+					Field:                  "field-b",
 					Message:                "Field `field-b` within resource `google-x` was either removed or renamed",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#resource-schema-field-removal-or-rename",
+					RuleName:               "resource-schema-field-removal-or-rename",
+					Severity:               SeverityError,
 				},
 			},
 		},
@@ -199,16 +243,29 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-a",
 					Message:                "Field `field-a` changed from optional to required on `google-x`",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#field-optional-to-required",
+					RuleName:               "field-optional-to-required",
+					OldValue:               "optional",
+					NewValue:               "required",
+					Severity:               SeverityError,
 				},
 				{
+					Resource:               "google-x",
+					Field:                  "field-b",
 					Message:                "Field `field-b` within resource `google-x` was either removed or renamed",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#resource-schema-field-removal-or-rename",
+					RuleName:               "resource-schema-field-removal-or-rename",
+					Severity:               SeverityError,
 				},
 				{
+					Resource:               "google-y",
 					Message:                "Resource `google-y` was either removed or renamed",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#resource-map-resource-removal-or-rename",
+					RuleName:               "resource-map-resource-removal-or-rename",
+					Severity:               SeverityError,
 				},
 			},
 		},
@@ -253,8 +310,12 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-a.sub-field-2",
 					Message:                "Field `field-a.sub-field-2` within resource `google-x` was either removed or renamed",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#resource-schema-field-removal-or-rename",
+					RuleName:               "resource-schema-field-removal-or-rename",
+					Severity:               SeverityError,
 				},
 			},
 		},
@@ -298,8 +359,14 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-a.sub-field-1",
 					Message:                "Field `field-a.sub-field-1` MaxItems went from 100 to 25 on `google-x`",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#field-shrinking-max",
+					RuleName:               "field-shrinking-max",
+					OldValue:               "100",
+					NewValue:               "25",
+					Severity:               SeverityError,
 				},
 			},
 		},
@@ -343,8 +410,14 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-a.sub-field-1",
 					Message:                "Field `field-a.sub-field-1` MaxItems went from 100 to 25 on `google-x`",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#field-shrinking-max",
+					RuleName:               "field-shrinking-max",
+					OldValue:               "100",
+					NewValue:               "25",
+					Severity:               SeverityError,
 				},
 			},
 		},
@@ -374,8 +447,14 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-a",
 					Message:                "Field `field-a` MinItems went from 1 to 4 on `google-x`",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#field-growing-min",
+					RuleName:               "field-growing-min",
+					OldValue:               "1",
+					NewValue:               "4",
+					Severity:               SeverityError,
 				},
 			},
 		},
@@ -401,3 +480,85 @@ func TestComputeBreakingChanges(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeProviderBreakingChanges(t *testing.T) {
+	oldSchema := diff.ProviderSchema{
+		diff.KindResource: {
+			"google_x": {Schema: map[string]*schema.Schema{"field_a": {Optional: true}}},
+		},
+		diff.KindDataSource: {
+			"google_x": {Schema: map[string]*schema.Schema{"field_a": {Optional: true}}},
+			"google_y": {Schema: map[string]*schema.Schema{"field_a": {Optional: true}}},
+		},
+		diff.KindFunction: {
+			"name_from_id": {Schema: map[string]*schema.Schema{"parameter_0": {Type: schema.TypeString, Required: true}}},
+		},
+		diff.KindProvider: {
+			"google": {Schema: map[string]*schema.Schema{"project": {Type: schema.TypeString, Optional: true}}},
+		},
+	}
+	newSchema := diff.ProviderSchema{
+		diff.KindResource: {
+			"google_x": {Schema: map[string]*schema.Schema{"field_a": {Optional: true}}},
+		},
+		diff.KindDataSource: {
+			"google_x": {Schema: map[string]*schema.Schema{"field_a": {Required: true}}},
+		},
+		diff.KindFunction: {
+			"name_from_id": {Schema: map[string]*schema.Schema{"parameter_0": {Type: schema.TypeInt, Required: true}}},
+		},
+		diff.KindProvider: {
+			"google": {Schema: map[string]*schema.Schema{}},
+		},
+	}
+
+	violations := ComputeProviderBreakingChanges(diff.ComputeProviderSchemaDiff(oldSchema, newSchema))
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Message < violations[j].Message
+	})
+	want := []BreakingChange{
+		{
+			Kind:                   diff.KindDataSource,
+			Resource:               "google_y",
+			Message:                "Data source `google_y` was either removed or renamed",
+			DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#resource-map-resource-removal-or-rename",
+			RuleName:               "resource-map-resource-removal-or-rename",
+			Severity:               SeverityError,
+		},
+		{
+			Kind:                   diff.KindDataSource,
+			Resource:               "google_x",
+			Field:                  "field_a",
+			Message:                "Field `field_a` changed from optional to required on `google_x`",
+			DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#field-optional-to-required",
+			RuleName:               "field-optional-to-required",
+			OldValue:               "optional",
+			NewValue:               "required",
+			Severity:               SeverityError,
+		},
+		{
+			Kind:                   diff.KindFunction,
+			Resource:               "name_from_id",
+			Field:                  "parameter_0",
+			Message:                "Field `parameter_0` changed from TypeString to TypeInt on `name_from_id`",
+			DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#field-changing-type",
+			RuleName:               "field-changing-type",
+			OldValue:               "TypeString",
+			NewValue:               "TypeInt",
+			Severity:               SeverityError,
+		},
+		{
+			Kind:                   diff.KindProvider,
+			Resource:               "google",
+			Field:                  "project",
+			Message:                "Field `project` within provider `google` was either removed or renamed",
+			DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#resource-schema-field-removal-or-rename",
+			RuleName:               "resource-schema-field-removal-or-rename",
+			OldValue:               "TypeString",
+			Severity:               SeverityError,
+		},
+	}
+	if diff := cmp.Diff(want, violations); diff != "" {
+		t.Errorf("violation diff(-want, +got) = %s", diff)
+	}
+}
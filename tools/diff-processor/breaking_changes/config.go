@@ -0,0 +1,178 @@
+package breaking_changes
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"gopkg.in/yaml.v3"
+)
+
+// SeveritySuppress is used by overrides to drop matching breaking changes
+// instead of changing their severity.
+const SeveritySuppress Severity = "suppress"
+
+const expiresLayout = "2006-01-02"
+
+// Config overrides the severity of breaking changes, to let known changes
+// through review and release tooling for a limited time. It is read from YAML
+// such as:
+//
+//	overrides:
+//	  - rule: field-becoming-force-new
+//	    kind: resource
+//	    resource: google_compute_instance
+//	    field: boot_disk.*
+//	    severity: warning
+//	    expires: 2025-06-30
+//	    reason: Updates were never supported by the API.
+type Config struct {
+	Overrides []Override `yaml:"overrides"`
+}
+
+// Override changes the severity of the breaking changes found by a rule for
+// matching resources and fields.
+type Override struct {
+	// Rule is the identifier of the rule.
+	Rule string `yaml:"rule"`
+	// Kind is the kind of the changed schema, such as "resource" or "data
+	// source". An empty Kind matches every kind.
+	Kind diff.Kind `yaml:"kind,omitempty"`
+	// Resource and Field are path.Match patterns. An empty Field matches
+	// every field, including changes that aren't about a field.
+	Resource string `yaml:"resource"`
+	Field    string `yaml:"field,omitempty"`
+	// Severity is the severity to use instead, or "suppress".
+	Severity Severity `yaml:"severity"`
+	// Expires is the last day the override applies, as YYYY-MM-DD.
+	Expires string `yaml:"expires"`
+	Reason  string `yaml:"reason"`
+}
+
+// LoadConfig reads and validates a Config.
+func LoadConfig(filename string) (*Config, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	c := &Config{}
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", filename, err)
+	}
+	return c, nil
+}
+
+func (c *Config) Validate() error {
+	identifiers := ruleIdentifiers()
+	for i, o := range c.Overrides {
+		if !slices.Contains(identifiers, o.Rule) {
+			return fmt.Errorf("override %d: unknown rule %q", i, o.Rule)
+		}
+		if o.Kind != "" && !slices.Contains(diff.Kinds, o.Kind) {
+			return fmt.Errorf("override %d: unknown kind %q", i, o.Kind)
+		}
+		if o.Resource == "" {
+			return fmt.Errorf("override %d: resource is required", i)
+		}
+		for _, pattern := range []string{o.Resource, o.Field} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("override %d: invalid pattern %q: %w", i, pattern, err)
+			}
+		}
+		switch o.Severity {
+		case SeverityWarning, SeverityInfo, SeveritySuppress:
+		default:
+			return fmt.Errorf("override %d: severity must be one of %s, %s or %s, got %q", i, SeverityWarning, SeverityInfo, SeveritySuppress, o.Severity)
+		}
+		if _, err := time.Parse(expiresLayout, o.Expires); err != nil {
+			return fmt.Errorf("override %d: expires must be a date formatted as YYYY-MM-DD: %w", i, err)
+		}
+		if o.Reason == "" {
+			return fmt.Errorf("override %d: reason is required", i)
+		}
+	}
+	return nil
+}
+
+// Apply returns the breaking changes with the first override matching each
+// applied. Overrides that expired before now are ignored and returned, so
+// that they can be reported and cleaned up.
+func (c *Config) Apply(breakingChanges []BreakingChange, now time.Time) ([]BreakingChange, []Override) {
+	var active, expired []Override
+	for _, o := range c.Overrides {
+		if o.expired(now) {
+			expired = append(expired, o)
+		} else {
+			active = append(active, o)
+		}
+	}
+
+	var result []BreakingChange
+	for _, bc := range breakingChanges {
+		i := slices.IndexFunc(active, func(o Override) bool { return o.matches(bc) })
+		if i < 0 {
+			result = append(result, bc)
+			continue
+		}
+		if active[i].Severity == SeveritySuppress {
+			continue
+		}
+		bc.Severity = active[i].Severity
+		bc.Reason = active[i].Reason
+		result = append(result, bc)
+	}
+	return result, expired
+}
+
+func (o Override) expired(now time.Time) bool {
+	expires, err := time.Parse(expiresLayout, o.Expires)
+	if err != nil {
+		return true
+	}
+	return !now.Before(expires.AddDate(0, 0, 1))
+}
+
+func (o Override) matches(bc BreakingChange) bool {
+	if o.Rule != bc.RuleName {
+		return false
+	}
+	// Breaking changes computed for resources alone don't set their kind.
+	kind := bc.Kind
+	if kind == "" {
+		kind = diff.KindResource
+	}
+	if o.Kind != "" && o.Kind != kind {
+		return false
+	}
+	if ok, _ := path.Match(o.Resource, bc.Resource); !ok {
+		return false
+	}
+	if o.Field == "" {
+		return true
+	}
+	ok, _ := path.Match(o.Field, bc.Field)
+	return ok
+}
+
+func ruleIdentifiers() []string {
+	var identifiers []string
+	for _, r := range ResourceConfigDiffRules {
+		identifiers = append(identifiers, r.Identifier)
+	}
+	for _, r := range ResourceDiffRules {
+		identifiers = append(identifiers, r.Identifier)
+	}
+	for _, r := range FieldDiffRules {
+		identifiers = append(identifiers, r.Identifier)
+	}
+	return identifiers
+}
@@ -0,0 +1,159 @@
+package breaking_changes
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cases := map[string]struct {
+		config  string
+		wantErr string
+	}{
+		"valid": {
+			config: `overrides:
+  - rule: field-becoming-force-new
+    resource: google_compute_instance
+    field: boot_disk.*
+    severity: warning
+    expires: 2025-06-30
+    reason: Updates were never supported by the API.
+`,
+		},
+		"unknown rule": {
+			config: `overrides:
+  - rule: field-becoming-immutable
+    resource: google_compute_instance
+    severity: warning
+    expires: 2025-06-30
+    reason: Typo.
+`,
+			wantErr: "unknown rule",
+		},
+		"unknown key": {
+			config: `overrides:
+  - rule: field-becoming-force-new
+    resources: google_compute_instance
+`,
+			wantErr: "field resources not found",
+		},
+		"unknown kind": {
+			config: `overrides:
+  - rule: field-becoming-force-new
+    kind: datasource
+    resource: google_compute_instance
+    severity: warning
+    expires: 2025-06-30
+    reason: Misspelled.
+`,
+			wantErr: "unknown kind",
+		},
+		"missing resource": {
+			config: `overrides:
+  - rule: field-becoming-force-new
+    severity: warning
+    expires: 2025-06-30
+    reason: Everywhere.
+`,
+			wantErr: "resource is required",
+		},
+		"upgrading severity": {
+			config: `overrides:
+  - rule: field-becoming-force-new
+    resource: google_compute_instance
+    severity: error
+    expires: 2025-06-30
+    reason: Stricter.
+`,
+			wantErr: "severity must be one of",
+		},
+		"missing expiry": {
+			config: `overrides:
+  - rule: field-becoming-force-new
+    resource: google_compute_instance
+    severity: suppress
+    reason: Forever.
+`,
+			wantErr: "expires must be a date",
+		},
+		"missing reason": {
+			config: `overrides:
+  - rule: field-becoming-force-new
+    resource: google_compute_instance
+    severity: suppress
+    expires: 2025-06-30
+`,
+			wantErr: "reason is required",
+		},
+	}
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(filename, []byte(tc.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadConfig(filename)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("LoadConfig() unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("LoadConfig() got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// TestLoadConfig_checkedIn checks the overrides used when commenting on pull
+// requests.
+func TestLoadConfig_checkedIn(t *testing.T) {
+	if _, err := LoadConfig("../breaking_changes.yaml"); err != nil {
+		t.Errorf("LoadConfig() got error %v", err)
+	}
+}
+
+func TestConfigApply(t *testing.T) {
+	c := &Config{
+		Overrides: []Override{
+			{Rule: "field-becoming-force-new", Resource: "google_compute_instance", Field: "boot_disk.*", Severity: SeverityWarning, Expires: "2025-06-30", Reason: "warned"},
+			{Rule: "field-becoming-force-new", Resource: "google_compute_*", Severity: SeveritySuppress, Expires: "2025-06-30", Reason: "suppressed"},
+			{Rule: "field-removing-sensitive", Resource: "google_sql_user", Severity: SeverityInfo, Expires: "2025-01-01", Reason: "expired"},
+			{Rule: "field-changing-type", Kind: diff.KindDataSource, Resource: "google_storage_bucket", Severity: SeverityInfo, Expires: "2025-06-30", Reason: "data source only"},
+		},
+	}
+	breakingChanges := []BreakingChange{
+		{RuleName: "field-becoming-force-new", Resource: "google_compute_instance", Field: "boot_disk.device_name", Severity: SeverityError},
+		{RuleName: "field-becoming-force-new", Resource: "google_compute_disk", Field: "size", Severity: SeverityError},
+		{RuleName: "field-becoming-force-new", Resource: "google_storage_bucket", Field: "location", Severity: SeverityError},
+		{RuleName: "field-removing-sensitive", Resource: "google_sql_user", Field: "password", Severity: SeverityError},
+		{RuleName: "field-changing-type", Kind: diff.KindResource, Resource: "google_storage_bucket", Field: "location", Severity: SeverityError},
+		{RuleName: "field-changing-type", Kind: diff.KindDataSource, Resource: "google_storage_bucket", Field: "location", Severity: SeverityError},
+	}
+
+	got, expired := c.Apply(breakingChanges, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	want := []BreakingChange{
+		{RuleName: "field-becoming-force-new", Resource: "google_compute_instance", Field: "boot_disk.device_name", Severity: SeverityWarning, Reason: "warned"},
+		{RuleName: "field-becoming-force-new", Resource: "google_storage_bucket", Field: "location", Severity: SeverityError},
+		{RuleName: "field-removing-sensitive", Resource: "google_sql_user", Field: "password", Severity: SeverityError},
+		{RuleName: "field-changing-type", Kind: diff.KindResource, Resource: "google_storage_bucket", Field: "location", Severity: SeverityError},
+		{RuleName: "field-changing-type", Kind: diff.KindDataSource, Resource: "google_storage_bucket", Field: "location", Severity: SeverityInfo, Reason: "data source only"},
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Apply() diff(-want, +got) = %s", d)
+	}
+	if len(expired) != 1 || expired[0].Reason != "expired" {
+		t.Errorf("Apply() expected the sql user override to be expired, got %v", expired)
+	}
+
+	if _, expired := c.Apply(nil, time.Date(2025, 1, 1, 23, 59, 0, 0, time.UTC)); len(expired) != 0 {
+		t.Errorf("Apply() expected overrides to apply until the end of their expiry date, got %v expired", expired)
+	}
+}
@@ -13,7 +13,10 @@ import (
 // regarding field attribute changes
 type FieldDiffRule struct {
 	Identifier string
-	Messages   func(resource, field string, fieldDiff diff.FieldDiff, resourceDiff diff.ResourceDiffInterface) []string
+	Messages   func(kind diff.Kind, resource, field string, fieldDiff diff.FieldDiff, resourceDiff diff.ResourceDiffInterface) []string
+	// Values returns the old and new values of the attribute checked by the
+	// rule, for fields it reported. Optional.
+	Values func(fieldDiff diff.FieldDiff) (string, string)
 }
 
 // FieldDiffRules is a list of FieldDiffRule
@@ -28,43 +31,59 @@ var FieldDiffRules = []FieldDiffRule{
 	FieldDefaultModification,
 	FieldGrowingMin,
 	FieldShrinkingMax,
+	FieldShrinkingElemMax,
 	FieldRemovingDiffSuppress,
+	FieldBecomingForceNew,
+	FieldAddingValidation,
+	FieldRemovingSensitive,
 }
 
 var FieldChangingType = FieldDiffRule{
 	Identifier: "field-changing-type",
 	Messages:   FieldChangingTypeMessages,
+	Values:     FieldChangingTypeValues,
 }
 
-func FieldChangingTypeMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+func FieldChangingTypeMessages(kind diff.Kind, resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
 	// Type change doesn't matter for added / removed fields
 	if fieldDiff.Old == nil || fieldDiff.New == nil {
 		return nil
 	}
 	tmpl := "Field `%s` changed from %s to %s on `%s`"
-	if fieldDiff.Old.Type != fieldDiff.New.Type {
-		oldType := getValueType(fieldDiff.Old.Type)
-		newType := getValueType(fieldDiff.New.Type)
+	if oldType, newType := FieldChangingTypeValues(fieldDiff); oldType != newType {
 		return []string{fmt.Sprintf(tmpl, field, oldType, newType, resource)}
 	}
+	return nil
+}
+
+// FieldChangingTypeValues returns the types of a field, including the type of
+// their elements when those are primitives.
+func FieldChangingTypeValues(fieldDiff diff.FieldDiff) (string, string) {
+	if fieldDiff.Old == nil || fieldDiff.New == nil {
+		return "", ""
+	}
+	oldType := getValueType(fieldDiff.Old.Type)
+	newType := getValueType(fieldDiff.New.Type)
+	if oldType != newType {
+		return oldType, newType
+	}
 
 	oldCasted, _ := fieldDiff.Old.Elem.(*schema.Schema)
 	newCasted, _ := fieldDiff.New.Elem.(*schema.Schema)
-	if oldCasted != nil && newCasted != nil && oldCasted.Type != newCasted.Type {
-		oldType := getValueType(fieldDiff.Old.Type) + "." + getValueType(oldCasted.Type)
-		newType := getValueType(fieldDiff.New.Type) + "." + getValueType(newCasted.Type)
-		return []string{fmt.Sprintf(tmpl, field, oldType, newType, resource)}
+	if oldCasted != nil && newCasted != nil {
+		oldType += "." + getValueType(oldCasted.Type)
+		newType += "." + getValueType(newCasted.Type)
 	}
-
-	return nil
+	return oldType, newType
 }
 
 var FieldBecomingRequired = FieldDiffRule{
 	Identifier: "field-optional-to-required",
 	Messages:   FieldBecomingRequiredMessages,
+	Values:     fieldModeValues,
 }
 
-func FieldBecomingRequiredMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+func FieldBecomingRequiredMessages(kind diff.Kind, resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
 	// Ignore for added / removed fields
 	if fieldDiff.Old == nil || fieldDiff.New == nil {
 		return nil
@@ -80,9 +99,10 @@ func FieldBecomingRequiredMessages(resource, field string, fieldDiff diff.FieldD
 var FieldBecomingComputedOnly = FieldDiffRule{
 	Identifier: "field-becoming-computed",
 	Messages:   FieldBecomingComputedOnlyMessages,
+	Values:     fieldModeValues,
 }
 
-func FieldBecomingComputedOnlyMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+func FieldBecomingComputedOnlyMessages(kind diff.Kind, resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
 	// ignore for added / removed fields
 	if fieldDiff.Old == nil || fieldDiff.New == nil {
 		return nil
@@ -103,9 +123,10 @@ func FieldBecomingComputedOnlyMessages(resource, field string, fieldDiff diff.Fi
 var FieldOptionalComputedToOptional = FieldDiffRule{
 	Identifier: "field-oc-to-c",
 	Messages:   FieldOptionalComputedToOptionalMessages,
+	Values:     fieldModeValues,
 }
 
-func FieldOptionalComputedToOptionalMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+func FieldOptionalComputedToOptionalMessages(kind diff.Kind, resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
 	// ignore for added / removed fields
 	if fieldDiff.Old == nil || fieldDiff.New == nil {
 		return nil
@@ -120,9 +141,10 @@ func FieldOptionalComputedToOptionalMessages(resource, field string, fieldDiff d
 var FieldDefaultModification = FieldDiffRule{
 	Identifier: "field-changing-default-value",
 	Messages:   FieldDefaultModificationMessages,
+	Values:     FieldDefaultModificationValues,
 }
 
-func FieldDefaultModificationMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+func FieldDefaultModificationMessages(kind diff.Kind, resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
 	// ignore for added / removed fields
 	if fieldDiff.Old == nil || fieldDiff.New == nil {
 		return nil
@@ -138,6 +160,18 @@ func FieldDefaultModificationMessages(resource, field string, fieldDiff diff.Fie
 	return nil
 }
 
+func FieldDefaultModificationValues(fieldDiff diff.FieldDiff) (string, string) {
+	var oldDefault interface{}
+	if fieldDiff.Old != nil {
+		oldDefault = fieldDiff.Old.Default
+	}
+	var newDefault interface{}
+	if fieldDiff.New != nil {
+		newDefault = fieldDiff.New.Default
+	}
+	return formatDefaultValue(oldDefault), formatDefaultValue(newDefault)
+}
+
 // formatDefaultValue properly formats default values to distinguish between nil, empty string, and other values
 func formatDefaultValue(value interface{}) string {
 	if value == nil {
@@ -155,9 +189,10 @@ func formatDefaultValue(value interface{}) string {
 var FieldGrowingMin = FieldDiffRule{
 	Identifier: "field-growing-min",
 	Messages:   FieldGrowingMinMessages,
+	Values:     FieldGrowingMinValues,
 }
 
-func FieldGrowingMinMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+func FieldGrowingMinMessages(kind diff.Kind, resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
 	// ignore for added / removed fields
 	if fieldDiff.Old == nil || fieldDiff.New == nil {
 		return nil
@@ -174,12 +209,18 @@ func FieldGrowingMinMessages(resource, field string, fieldDiff diff.FieldDiff, _
 	return nil
 }
 
+func FieldGrowingMinValues(fieldDiff diff.FieldDiff) (string, string) {
+	return formatItems(fieldDiff.Old, func(s *schema.Schema) int { return s.MinItems }),
+		formatItems(fieldDiff.New, func(s *schema.Schema) int { return s.MinItems })
+}
+
 var FieldShrinkingMax = FieldDiffRule{
 	Identifier: "field-shrinking-max",
 	Messages:   FieldShrinkingMaxMessages,
+	Values:     FieldShrinkingMaxValues,
 }
 
-func FieldShrinkingMaxMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+func FieldShrinkingMaxMessages(kind diff.Kind, resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
 	// ignore for added / removed fields
 	if fieldDiff.Old == nil || fieldDiff.New == nil {
 		return nil
@@ -199,12 +240,69 @@ func FieldShrinkingMaxMessages(resource, field string, fieldDiff diff.FieldDiff,
 	return nil
 }
 
+func FieldShrinkingMaxValues(fieldDiff diff.FieldDiff) (string, string) {
+	return formatItems(fieldDiff.Old, func(s *schema.Schema) int { return s.MaxItems }),
+		formatItems(fieldDiff.New, func(s *schema.Schema) int { return s.MaxItems })
+}
+
+// formatItems formats a MinItems or MaxItems attribute of s, returned by
+// items, as "unset" when it is 0.
+func formatItems(s *schema.Schema, items func(*schema.Schema) int) string {
+	if s == nil {
+		return ""
+	}
+	if n := items(s); n != 0 {
+		return strconv.Itoa(n)
+	}
+	return "unset"
+}
+
+var FieldShrinkingElemMax = FieldDiffRule{
+	Identifier: "field-shrinking-elem-max",
+	Messages:   FieldShrinkingElemMaxMessages,
+	Values:     FieldShrinkingElemMaxValues,
+}
+
+// FieldShrinkingElemMaxMessages checks the MaxItems of the elements of a
+// collection of collections. Nested blocks are flattened into fields of their
+// own, so their MaxItems is checked by FieldShrinkingMax.
+func FieldShrinkingElemMaxMessages(kind diff.Kind, resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+	// ignore for added / removed fields
+	if fieldDiff.Old == nil || fieldDiff.New == nil {
+		return nil
+	}
+	oldElem, _ := fieldDiff.Old.Elem.(*schema.Schema)
+	newElem, _ := fieldDiff.New.Elem.(*schema.Schema)
+	if oldElem == nil || newElem == nil || newElem.MaxItems == 0 {
+		return nil
+	}
+	tmpl := "Field `%s` element MaxItems went from %s to %s on `%s`"
+	newMax := strconv.Itoa(newElem.MaxItems)
+	if oldElem.MaxItems == 0 {
+		return []string{fmt.Sprintf(tmpl, field, "unset", newMax, resource)}
+	}
+	if oldElem.MaxItems > newElem.MaxItems {
+		return []string{fmt.Sprintf(tmpl, field, strconv.Itoa(oldElem.MaxItems), newMax, resource)}
+	}
+	return nil
+}
+
+func FieldShrinkingElemMaxValues(fieldDiff diff.FieldDiff) (string, string) {
+	elemMax := func(s *schema.Schema) int {
+		if elem, ok := s.Elem.(*schema.Schema); ok {
+			return elem.MaxItems
+		}
+		return 0
+	}
+	return formatItems(fieldDiff.Old, elemMax), formatItems(fieldDiff.New, elemMax)
+}
+
 var FieldRemovingDiffSuppress = FieldDiffRule{
 	Identifier: "field-removing-diff-suppress",
 	Messages:   FieldRemovingDiffSuppressMessages,
 }
 
-func FieldRemovingDiffSuppressMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+func FieldRemovingDiffSuppressMessages(kind diff.Kind, resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
 	// ignore for added / removed fields
 	if fieldDiff.Old == nil || fieldDiff.New == nil {
 		return nil
@@ -220,9 +318,10 @@ func FieldRemovingDiffSuppressMessages(resource, field string, fieldDiff diff.Fi
 var FieldNewRequired = FieldDiffRule{
 	Identifier: "no-new-required",
 	Messages:   FieldNewRequiredMessages,
+	Values:     fieldModeValues,
 }
 
-func FieldNewRequiredMessages(resource, field string, fieldDiff diff.FieldDiff, resourceDiff diff.ResourceDiffInterface) []string {
+func FieldNewRequiredMessages(kind diff.Kind, resource, field string, fieldDiff diff.FieldDiff, resourceDiff diff.ResourceDiffInterface) []string {
 	if resourceDiff.IsNewResource() || resourceDiff.IsFieldInNewNestedStructure(field) {
 		return nil
 	}
@@ -230,8 +329,8 @@ func FieldNewRequiredMessages(resource, field string, fieldDiff diff.FieldDiff,
 	// This rule applies to newly added fields (Old == nil).
 	if fieldDiff.Old == nil {
 		if fieldDiff.New.Required {
-			tmpl := "Field `%s` added as required on pre-existing resource `%s`"
-			return []string{fmt.Sprintf(tmpl, field, resource)}
+			tmpl := "Field `%s` added as required on pre-existing %s `%s`"
+			return []string{fmt.Sprintf(tmpl, field, kind, resource)}
 		}
 	}
 	return nil
@@ -240,9 +339,10 @@ func FieldNewRequiredMessages(resource, field string, fieldDiff diff.FieldDiff,
 var FieldNewOptionalFieldWithDefault = FieldDiffRule{
 	Identifier: "no-new-optional-default",
 	Messages:   FieldNewOptionalFieldWithDefaultMessages,
+	Values:     FieldDefaultModificationValues,
 }
 
-func FieldNewOptionalFieldWithDefaultMessages(resource, field string, fieldDiff diff.FieldDiff, resourceDiff diff.ResourceDiffInterface) []string {
+func FieldNewOptionalFieldWithDefaultMessages(kind diff.Kind, resource, field string, fieldDiff diff.FieldDiff, resourceDiff diff.ResourceDiffInterface) []string {
 	if resourceDiff.IsNewResource() || resourceDiff.IsFieldInNewNestedStructure(field) {
 		return nil
 	}
@@ -250,10 +350,121 @@ func FieldNewOptionalFieldWithDefaultMessages(resource, field string, fieldDiff
 	// This rule applies to newly added fields (Old == nil).
 	if fieldDiff.Old == nil {
 		if fieldDiff.New.Optional && fieldDiff.New.Default != nil && fieldDiff.New.ForceNew {
-			tmpl := "Field `%s` added as optional with a default value and force new on pre-existing resource `%s`. " +
+			tmpl := "Field `%s` added as optional with a default value and force new on pre-existing %s `%s`. " +
 				"This can be allowed if there is a confirmed API-level default that matches the schema default"
-			return []string{fmt.Sprintf(tmpl, field, resource)}
+			return []string{fmt.Sprintf(tmpl, field, kind, resource)}
 		}
 	}
 	return nil
 }
+
+var FieldBecomingForceNew = FieldDiffRule{
+	Identifier: "field-becoming-force-new",
+	Messages:   FieldBecomingForceNewMessages,
+	Values:     FieldBecomingForceNewValues,
+}
+
+func FieldBecomingForceNewMessages(kind diff.Kind, resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+	// ignore for added / removed fields
+	if fieldDiff.Old == nil || fieldDiff.New == nil {
+		return nil
+	}
+	// computed only fields can't be changed by users
+	if fieldDiff.New.Computed && !fieldDiff.New.Optional {
+		return nil
+	}
+	tmpl := "Field `%s` became ForceNew on `%s`"
+	if !fieldDiff.Old.ForceNew && fieldDiff.New.ForceNew {
+		return []string{fmt.Sprintf(tmpl, field, resource)}
+	}
+	return nil
+}
+
+func FieldBecomingForceNewValues(fieldDiff diff.FieldDiff) (string, string) {
+	return formatBool(fieldDiff.Old, func(s *schema.Schema) bool { return s.ForceNew }),
+		formatBool(fieldDiff.New, func(s *schema.Schema) bool { return s.ForceNew })
+}
+
+var FieldAddingValidation = FieldDiffRule{
+	Identifier: "field-adding-validation",
+	Messages:   FieldAddingValidationMessages,
+}
+
+// FieldAddingValidationMessages only detects validation being added, as
+// validation functions can't be compared. Enum values being removed are
+// detected from resource metadata by RemovingEnumValues.
+func FieldAddingValidationMessages(kind diff.Kind, resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+	// ignore for added / removed fields
+	if fieldDiff.Old == nil || fieldDiff.New == nil {
+		return nil
+	}
+	if fieldDiff.New.Computed && !fieldDiff.New.Optional {
+		return nil
+	}
+	tmpl := "Field `%s` gained validation on `%s`"
+	if !hasValidation(fieldDiff.Old) && hasValidation(fieldDiff.New) {
+		return []string{fmt.Sprintf(tmpl, field, resource)}
+	}
+	oldElem, _ := fieldDiff.Old.Elem.(*schema.Schema)
+	newElem, _ := fieldDiff.New.Elem.(*schema.Schema)
+	if oldElem != nil && newElem != nil && !hasValidation(oldElem) && hasValidation(newElem) {
+		return []string{fmt.Sprintf("Field `%s` elements gained validation on `%s`", field, resource)}
+	}
+	return nil
+}
+
+func hasValidation(s *schema.Schema) bool {
+	return s.ValidateFunc != nil || s.ValidateDiagFunc != nil
+}
+
+var FieldRemovingSensitive = FieldDiffRule{
+	Identifier: "field-removing-sensitive",
+	Messages:   FieldRemovingSensitiveMessages,
+	Values:     FieldRemovingSensitiveValues,
+}
+
+func FieldRemovingSensitiveMessages(kind diff.Kind, resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+	// ignore for added / removed fields
+	if fieldDiff.Old == nil || fieldDiff.New == nil {
+		return nil
+	}
+	tmpl := "Field `%s` is no longer Sensitive on `%s`"
+	if fieldDiff.Old.Sensitive && !fieldDiff.New.Sensitive {
+		return []string{fmt.Sprintf(tmpl, field, resource)}
+	}
+	return nil
+}
+
+func FieldRemovingSensitiveValues(fieldDiff diff.FieldDiff) (string, string) {
+	return formatBool(fieldDiff.Old, func(s *schema.Schema) bool { return s.Sensitive }),
+		formatBool(fieldDiff.New, func(s *schema.Schema) bool { return s.Sensitive })
+}
+
+func formatBool(s *schema.Schema, attr func(*schema.Schema) bool) string {
+	if s == nil {
+		return ""
+	}
+	return strconv.FormatBool(attr(s))
+}
+
+// fieldModeValues describes whether each version of a field is required,
+// optional and/or computed.
+func fieldModeValues(fieldDiff diff.FieldDiff) (string, string) {
+	return fieldMode(fieldDiff.Old), fieldMode(fieldDiff.New)
+}
+
+func fieldMode(s *schema.Schema) string {
+	switch {
+	case s == nil:
+		return ""
+	case s.Required:
+		return "required"
+	case s.Optional && s.Computed:
+		return "optional+computed"
+	case s.Optional:
+		return "optional"
+	case s.Computed:
+		return "computed"
+	}
+	return ""
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 type fieldTestCase struct {
@@ -718,9 +719,177 @@ var FieldShrinkingMaxTestCases = []fieldTestCase{
 	},
 }
 
+func TestFieldShrinkingElemMax(t *testing.T) {
+	for _, tc := range FieldShrinkingElemMaxTestCases {
+		tc.check(FieldShrinkingElemMax, t)
+	}
+}
+
+var FieldShrinkingElemMaxTestCases = []fieldTestCase{
+	{
+		name:              "control",
+		oldField:          &schema.Schema{Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeList, MaxItems: 2}},
+		newField:          &schema.Schema{Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeList, MaxItems: 2}},
+		expectedViolation: false,
+	},
+	{
+		name:              "elem max shrinking",
+		oldField:          &schema.Schema{Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeList, MaxItems: 3}},
+		newField:          &schema.Schema{Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeList, MaxItems: 2}},
+		expectedViolation: true,
+		messageRegex:      "element MaxItems went from 3 to 2",
+	},
+	{
+		name:              "elem max unset to defined",
+		oldField:          &schema.Schema{Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeList}},
+		newField:          &schema.Schema{Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeList, MaxItems: 2}},
+		expectedViolation: true,
+		messageRegex:      "element MaxItems went from unset to 2",
+	},
+	{
+		name:              "elem max growing",
+		oldField:          &schema.Schema{Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeList, MaxItems: 2}},
+		newField:          &schema.Schema{Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeList, MaxItems: 3}},
+		expectedViolation: false,
+	},
+	{
+		name:              "nested block",
+		oldField:          &schema.Schema{Type: schema.TypeList, Elem: &schema.Resource{}},
+		newField:          &schema.Schema{Type: schema.TypeList, Elem: &schema.Resource{}},
+		expectedViolation: false,
+	},
+	{
+		name:              "field added",
+		oldField:          nil,
+		newField:          &schema.Schema{Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeList, MaxItems: 2}},
+		expectedViolation: false,
+	},
+}
+
+func TestFieldBecomingForceNew(t *testing.T) {
+	for _, tc := range FieldBecomingForceNewTestCases {
+		tc.check(FieldBecomingForceNew, t)
+	}
+}
+
+var FieldBecomingForceNewTestCases = []fieldTestCase{
+	{
+		name:              "control",
+		oldField:          &schema.Schema{Optional: true, ForceNew: true},
+		newField:          &schema.Schema{Optional: true, ForceNew: true},
+		expectedViolation: false,
+	},
+	{
+		name:              "becoming force new",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true, ForceNew: true},
+		expectedViolation: true,
+		messageRegex:      "Field `field` became ForceNew on `resource`",
+	},
+	{
+		name:              "no longer force new",
+		oldField:          &schema.Schema{Required: true, ForceNew: true},
+		newField:          &schema.Schema{Required: true},
+		expectedViolation: false,
+	},
+	{
+		name:              "computed only",
+		oldField:          &schema.Schema{Computed: true},
+		newField:          &schema.Schema{Computed: true, ForceNew: true},
+		expectedViolation: false,
+	},
+	{
+		name:              "field added",
+		oldField:          nil,
+		newField:          &schema.Schema{Optional: true, ForceNew: true},
+		expectedViolation: false,
+	},
+}
+
+func TestFieldAddingValidation(t *testing.T) {
+	for _, tc := range FieldAddingValidationTestCases {
+		tc.check(FieldAddingValidation, t)
+	}
+}
+
+var FieldAddingValidationTestCases = []fieldTestCase{
+	{
+		name:              "control",
+		oldField:          &schema.Schema{Optional: true, ValidateFunc: validation.StringInSlice([]string{"A"}, false)},
+		newField:          &schema.Schema{Optional: true, ValidateFunc: validation.StringInSlice([]string{"A"}, false)},
+		expectedViolation: false,
+	},
+	{
+		name:              "adding validate func",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true, ValidateFunc: validation.StringInSlice([]string{"A"}, false)},
+		expectedViolation: true,
+		messageRegex:      "Field `field` gained validation on `resource`",
+	},
+	{
+		name:              "adding validate diag func",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true, ValidateDiagFunc: validation.ToDiagFunc(validation.NoZeroValues)},
+		expectedViolation: true,
+	},
+	{
+		name:              "adding elem validation",
+		oldField:          &schema.Schema{Optional: true, Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}},
+		newField:          &schema.Schema{Optional: true, Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.NoZeroValues}},
+		expectedViolation: true,
+		messageRegex:      "Field `field` elements gained validation",
+	},
+	{
+		name:              "removing validation",
+		oldField:          &schema.Schema{Optional: true, ValidateFunc: validation.NoZeroValues},
+		newField:          &schema.Schema{Optional: true},
+		expectedViolation: false,
+	},
+	{
+		name:              "computed only",
+		oldField:          &schema.Schema{Computed: true},
+		newField:          &schema.Schema{Computed: true, ValidateFunc: validation.NoZeroValues},
+		expectedViolation: false,
+	},
+}
+
+func TestFieldRemovingSensitive(t *testing.T) {
+	for _, tc := range FieldRemovingSensitiveTestCases {
+		tc.check(FieldRemovingSensitive, t)
+	}
+}
+
+var FieldRemovingSensitiveTestCases = []fieldTestCase{
+	{
+		name:              "control",
+		oldField:          &schema.Schema{Optional: true, Sensitive: true},
+		newField:          &schema.Schema{Optional: true, Sensitive: true},
+		expectedViolation: false,
+	},
+	{
+		name:              "removing sensitive",
+		oldField:          &schema.Schema{Optional: true, Sensitive: true},
+		newField:          &schema.Schema{Optional: true},
+		expectedViolation: true,
+		messageRegex:      "Field `field` is no longer Sensitive on `resource`",
+	},
+	{
+		name:              "adding sensitive",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true, Sensitive: true},
+		expectedViolation: false,
+	},
+	{
+		name:              "field removed",
+		oldField:          &schema.Schema{Optional: true, Sensitive: true},
+		newField:          nil,
+		expectedViolation: false,
+	},
+}
+
 // Extended check method that also validates message content when expected
 func (tc *fieldTestCase) check(rule FieldDiffRule, t *testing.T) {
-	messages := rule.Messages("resource", "field", diff.FieldDiff{Old: tc.oldField, New: tc.newField}, tc.resourceDiff)
+	messages := rule.Messages(diff.KindResource, "resource", "field", diff.FieldDiff{Old: tc.oldField, New: tc.newField}, tc.resourceDiff)
 	violation := len(messages) > 0
 
 	// Check violation expectation
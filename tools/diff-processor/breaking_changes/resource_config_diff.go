@@ -2,6 +2,9 @@ package breaking_changes
 
 import (
 	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
 )
@@ -10,22 +13,82 @@ import (
 // structure for rules regarding resource config changes
 type ResourceConfigDiffRule struct {
 	Identifier string
-	Messages   func(resource string, resourceConfigDiff diff.ResourceConfigDiff) []string
+	Messages   func(kind diff.Kind, resource string, resourceConfigDiff diff.ResourceConfigDiff) []string
+	// Values returns the old and new values of the attribute checked by the
+	// rule, for resources it reported. Optional.
+	Values func(resourceConfigDiff diff.ResourceConfigDiff) (string, string)
 }
 
 // ResourceConfigDiffRules is a list of ResourceConfigDiffRule
 // guarding against provider breaking changes
-var ResourceConfigDiffRules = []ResourceConfigDiffRule{ResourceConfigRemovingAResource}
+var ResourceConfigDiffRules = []ResourceConfigDiffRule{
+	ResourceConfigRemovingAResource,
+	ResourceConfigRemovingImporter,
+	ResourceConfigDecreasingSchemaVersion,
+}
 
 var ResourceConfigRemovingAResource = ResourceConfigDiffRule{
 	Identifier: "resource-map-resource-removal-or-rename",
 	Messages:   ResourceConfigRemovingAResourceMessages,
 }
 
-func ResourceConfigRemovingAResourceMessages(resource string, resourceConfigDiff diff.ResourceConfigDiff) []string {
+func ResourceConfigRemovingAResourceMessages(kind diff.Kind, resource string, resourceConfigDiff diff.ResourceConfigDiff) []string {
 	if resourceConfigDiff.New == nil && resourceConfigDiff.Old != nil {
-		tmpl := "Resource `%s` was either removed or renamed"
-		return []string{fmt.Sprintf(tmpl, resource)}
+		tmpl := "%s `%s` was either removed or renamed"
+		return []string{fmt.Sprintf(tmpl, kind.Title(), resource)}
+	}
+	return nil
+}
+
+var ResourceConfigRemovingImporter = ResourceConfigDiffRule{
+	Identifier: "resource-removing-importer",
+	Messages:   ResourceConfigRemovingImporterMessages,
+	Values:     ResourceConfigRemovingImporterValues,
+}
+
+func ResourceConfigRemovingImporterMessages(kind diff.Kind, resource string, resourceConfigDiff diff.ResourceConfigDiff) []string {
+	// ignore for added / removed resources
+	if resourceConfigDiff.Old == nil || resourceConfigDiff.New == nil {
+		return nil
+	}
+	if resourceConfigDiff.Old.Importer != nil && resourceConfigDiff.New.Importer == nil {
+		tmpl := "%s `%s` can no longer be imported"
+		return []string{fmt.Sprintf(tmpl, kind.Title(), resource)}
+	}
+	return nil
+}
+
+func ResourceConfigRemovingImporterValues(resourceConfigDiff diff.ResourceConfigDiff) (string, string) {
+	importable := func(r *schema.Resource) string {
+		if r == nil {
+			return ""
+		}
+		return strconv.FormatBool(r.Importer != nil)
+	}
+	return importable(resourceConfigDiff.Old), importable(resourceConfigDiff.New)
+}
+
+var ResourceConfigDecreasingSchemaVersion = ResourceConfigDiffRule{
+	Identifier: "resource-decreasing-schema-version",
+	Messages:   ResourceConfigDecreasingSchemaVersionMessages,
+	Values:     ResourceConfigDecreasingSchemaVersionValues,
+}
+
+func ResourceConfigDecreasingSchemaVersionMessages(kind diff.Kind, resource string, resourceConfigDiff diff.ResourceConfigDiff) []string {
+	// ignore for added / removed resources
+	if resourceConfigDiff.Old == nil || resourceConfigDiff.New == nil {
+		return nil
+	}
+	if resourceConfigDiff.Old.SchemaVersion > resourceConfigDiff.New.SchemaVersion {
+		tmpl := "%s `%s` schema version decreased from %d to %d"
+		return []string{fmt.Sprintf(tmpl, kind.Title(), resource, resourceConfigDiff.Old.SchemaVersion, resourceConfigDiff.New.SchemaVersion)}
 	}
 	return nil
 }
+
+func ResourceConfigDecreasingSchemaVersionValues(resourceConfigDiff diff.ResourceConfigDiff) (string, string) {
+	if resourceConfigDiff.Old == nil || resourceConfigDiff.New == nil {
+		return "", ""
+	}
+	return strconv.Itoa(resourceConfigDiff.Old.SchemaVersion), strconv.Itoa(resourceConfigDiff.New.SchemaVersion)
+}
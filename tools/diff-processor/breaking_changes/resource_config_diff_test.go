@@ -17,7 +17,7 @@ type resourceInventoryTestCase struct {
 
 func TestResourceInventoryRule_RemovingAResource(t *testing.T) {
 	for _, tc := range resourceConfigRemovingAResourceTestCases {
-		got := ResourceConfigRemovingAResource.Messages("resource", diff.ResourceConfigDiff{Old: tc.old, New: tc.new})
+		got := ResourceConfigRemovingAResource.Messages(diff.KindResource, "resource", diff.ResourceConfigDiff{Old: tc.old, New: tc.new})
 		gotViolations := len(got) > 0
 		if tc.wantViolations != gotViolations {
 			t.Errorf("ResourceConfigRemovingAResource.Messages(%v) violations not expected. Got %v, want %v", tc.name, gotViolations, tc.wantViolations)
@@ -45,3 +45,77 @@ var resourceConfigRemovingAResourceTestCases = []resourceInventoryTestCase{
 		wantViolations: true,
 	},
 }
+
+func TestResourceConfigRemovingImporter(t *testing.T) {
+	for _, tc := range resourceConfigRemovingImporterTestCases {
+		got := ResourceConfigRemovingImporter.Messages(diff.KindResource, "resource", diff.ResourceConfigDiff{Old: tc.old, New: tc.new})
+		gotViolations := len(got) > 0
+		if tc.wantViolations != gotViolations {
+			t.Errorf("ResourceConfigRemovingImporter.Messages(%v) violations not expected. Got %v, want %v", tc.name, gotViolations, tc.wantViolations)
+		}
+	}
+}
+
+var resourceConfigRemovingImporterTestCases = []resourceInventoryTestCase{
+	{
+		name:           "control",
+		old:            &schema.Resource{Importer: &schema.ResourceImporter{}},
+		new:            &schema.Resource{Importer: &schema.ResourceImporter{}},
+		wantViolations: false,
+	},
+	{
+		name:           "importer removed",
+		old:            &schema.Resource{Importer: &schema.ResourceImporter{}},
+		new:            &schema.Resource{},
+		wantViolations: true,
+	},
+	{
+		name:           "importer added",
+		old:            &schema.Resource{},
+		new:            &schema.Resource{Importer: &schema.ResourceImporter{}},
+		wantViolations: false,
+	},
+	{
+		name:           "resource removed",
+		old:            &schema.Resource{Importer: &schema.ResourceImporter{}},
+		new:            nil,
+		wantViolations: false,
+	},
+}
+
+func TestResourceConfigDecreasingSchemaVersion(t *testing.T) {
+	for _, tc := range resourceConfigDecreasingSchemaVersionTestCases {
+		got := ResourceConfigDecreasingSchemaVersion.Messages(diff.KindResource, "resource", diff.ResourceConfigDiff{Old: tc.old, New: tc.new})
+		gotViolations := len(got) > 0
+		if tc.wantViolations != gotViolations {
+			t.Errorf("ResourceConfigDecreasingSchemaVersion.Messages(%v) violations not expected. Got %v, want %v", tc.name, gotViolations, tc.wantViolations)
+		}
+	}
+}
+
+var resourceConfigDecreasingSchemaVersionTestCases = []resourceInventoryTestCase{
+	{
+		name:           "control",
+		old:            &schema.Resource{SchemaVersion: 1},
+		new:            &schema.Resource{SchemaVersion: 1},
+		wantViolations: false,
+	},
+	{
+		name:           "schema version increased",
+		old:            &schema.Resource{SchemaVersion: 1},
+		new:            &schema.Resource{SchemaVersion: 2},
+		wantViolations: false,
+	},
+	{
+		name:           "schema version decreased",
+		old:            &schema.Resource{SchemaVersion: 2},
+		new:            &schema.Resource{SchemaVersion: 1},
+		wantViolations: true,
+	},
+	{
+		name:           "resource added",
+		old:            nil,
+		new:            &schema.Resource{SchemaVersion: 1},
+		wantViolations: false,
+	},
+}
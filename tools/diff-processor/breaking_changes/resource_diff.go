@@ -2,6 +2,10 @@ package breaking_changes
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
 )
@@ -9,41 +13,68 @@ import (
 // ResourceDiffRule is a rule that operates on an entire ResourceDiff
 type ResourceDiffRule struct {
 	Identifier string
-	Messages   func(resource string, resourceDiff diff.ResourceDiff) []string
+	Changes    func(kind diff.Kind, resource string, resourceDiff diff.ResourceDiff) []ResourceChange
+}
+
+// ResourceChange is a change found by a ResourceDiffRule, with the field it
+// applies to if any.
+type ResourceChange struct {
+	Field    string
+	Message  string
+	OldValue string
+	NewValue string
+}
+
+func resourceChangeMessages(changes []ResourceChange) []string {
+	var messages []string
+	for _, c := range changes {
+		messages = append(messages, c.Message)
+	}
+	return messages
 }
 
 // ResourceDiffRules is a list of all ResourceDiff rules
-var ResourceDiffRules = []ResourceDiffRule{RemovingAField, AddingExactlyOneOf}
+var ResourceDiffRules = []ResourceDiffRule{RemovingAField, AddingExactlyOneOf, RemovingEnumValues, ChangingIdFormat}
 
 var RemovingAField = ResourceDiffRule{
 	Identifier: "resource-schema-field-removal-or-rename",
-	Messages:   RemovingAFieldMessages,
+	Changes:    RemovingAFieldChanges,
+}
+
+func RemovingAFieldMessages(kind diff.Kind, resource string, resourceDiff diff.ResourceDiff) []string {
+	return resourceChangeMessages(RemovingAFieldChanges(kind, resource, resourceDiff))
 }
 
 // TODO: Make field removal a FieldDiffRule b/300124253
-func RemovingAFieldMessages(resource string, resourceDiff diff.ResourceDiff) []string {
-	fieldsRemoved := []string{}
+func RemovingAFieldChanges(kind diff.Kind, resource string, resourceDiff diff.ResourceDiff) []ResourceChange {
+	tmpl := "Field `%s` within %s `%s` was either removed or renamed"
+	var changes []ResourceChange
 	for field, fieldDiff := range resourceDiff.Fields {
 		if fieldDiff.Old != nil && fieldDiff.New == nil {
-			fieldsRemoved = append(fieldsRemoved, field)
+			change := ResourceChange{
+				Field:   field,
+				Message: fmt.Sprintf(tmpl, field, kind, resource),
+			}
+			if fieldDiff.Old.Type != schema.TypeInvalid {
+				change.OldValue = getValueType(fieldDiff.Old.Type)
+			}
+			changes = append(changes, change)
 		}
 	}
-
-	tmpl := "Field `%s` within resource `%s` was either removed or renamed"
-	var messages []string
-	for _, field := range fieldsRemoved {
-		messages = append(messages, fmt.Sprintf(tmpl, field, resource))
-	}
-	return messages
+	return changes
 }
 
 var AddingExactlyOneOf = ResourceDiffRule{
 	Identifier: "resource-schema-field-addition-of-exactly-one-of",
-	Messages:   AddingExactlyOneOfMessages,
+	Changes:    AddingExactlyOneOfChanges,
 }
 
-func AddingExactlyOneOfMessages(resource string, resourceDiff diff.ResourceDiff) []string {
-	var messages []string
+func AddingExactlyOneOfMessages(kind diff.Kind, resource string, resourceDiff diff.ResourceDiff) []string {
+	return resourceChangeMessages(AddingExactlyOneOfChanges(kind, resource, resourceDiff))
+}
+
+func AddingExactlyOneOfChanges(kind diff.Kind, resource string, resourceDiff diff.ResourceDiff) []ResourceChange {
+	var changes []ResourceChange
 	newFieldSets := make(map[string]diff.FieldSet) // Set of field sets in new and not in old.
 	oldFieldSets := make(map[string]diff.FieldSet) // Set of field sets in old and not in new.
 	for key, fieldSet := range resourceDiff.FieldSets.New.ExactlyOneOf {
@@ -57,7 +88,7 @@ func AddingExactlyOneOfMessages(resource string, resourceDiff diff.ResourceDiff)
 		}
 	}
 	// Find old field sets which are subsets of new field sets.
-	for _, newFieldSet := range newFieldSets {
+	for key, newFieldSet := range newFieldSets {
 		var addedFields diff.FieldSet
 		found := false
 		for _, oldFieldSet := range oldFieldSets {
@@ -72,9 +103,81 @@ func AddingExactlyOneOfMessages(resource string, resourceDiff diff.ResourceDiff)
 		}
 		for field := range addedFields {
 			if fieldDiff, ok := resourceDiff.Fields[field]; ok && fieldDiff.Old != nil && !fieldDiff.Old.Required {
-				messages = append(messages, fmt.Sprintf("Field `%s` within resource `%s` was added to exactly one of", field, resource))
+				changes = append(changes, ResourceChange{
+					Field:    field,
+					Message:  fmt.Sprintf("Field `%s` within %s `%s` was added to exactly one of", field, kind, resource),
+					NewValue: key,
+				})
 			}
 		}
 	}
-	return messages
+	return changes
+}
+
+var RemovingEnumValues = ResourceDiffRule{
+	Identifier: "field-removing-enum-values",
+	Changes:    RemovingEnumValuesChanges,
+}
+
+func RemovingEnumValuesMessages(kind diff.Kind, resource string, resourceDiff diff.ResourceDiff) []string {
+	return resourceChangeMessages(RemovingEnumValuesChanges(kind, resource, resourceDiff))
+}
+
+// RemovingEnumValuesChanges compares the enum values recorded in resource
+// metadata, as the validation functions that enforce them can't be compared.
+func RemovingEnumValuesChanges(kind diff.Kind, resource string, resourceDiff diff.ResourceDiff) []ResourceChange {
+	md := resourceDiff.Metadata
+	if md.Old == nil || md.New == nil {
+		return nil
+	}
+	tmpl := "Field `%s` no longer accepts %s on `%s`"
+	var changes []ResourceChange
+	for _, f := range md.Old.Fields {
+		newValues := md.New.EnumValues(f.Field)
+		// Fields that were removed or are no longer enums are checked by other rules.
+		if len(f.EnumValues) == 0 || len(newValues) == 0 {
+			continue
+		}
+		var removed []string
+		for _, v := range f.EnumValues {
+			if !slices.Contains(newValues, v) {
+				removed = append(removed, fmt.Sprintf("`%s`", v))
+			}
+		}
+		if len(removed) > 0 {
+			changes = append(changes, ResourceChange{
+				Field:    f.Field,
+				Message:  fmt.Sprintf(tmpl, f.Field, strings.Join(removed, ", "), resource),
+				OldValue: strings.Join(f.EnumValues, ", "),
+				NewValue: strings.Join(newValues, ", "),
+			})
+		}
+	}
+	return changes
+}
+
+var ChangingIdFormat = ResourceDiffRule{
+	Identifier: "resource-id",
+	Changes:    ChangingIdFormatChanges,
+}
+
+func ChangingIdFormatMessages(kind diff.Kind, resource string, resourceDiff diff.ResourceDiff) []string {
+	return resourceChangeMessages(ChangingIdFormatChanges(kind, resource, resourceDiff))
+}
+
+func ChangingIdFormatChanges(kind diff.Kind, resource string, resourceDiff diff.ResourceDiff) []ResourceChange {
+	md := resourceDiff.Metadata
+	// Handwritten resources don't record their ID format.
+	if md.Old == nil || md.New == nil || md.Old.IdFormat == "" || md.New.IdFormat == "" {
+		return nil
+	}
+	if md.Old.IdFormat != md.New.IdFormat {
+		tmpl := "%s `%s` ID format changed from `%s` to `%s`"
+		return []ResourceChange{{
+			Message:  fmt.Sprintf(tmpl, kind.Title(), resource, md.Old.IdFormat, md.New.IdFormat),
+			OldValue: md.Old.IdFormat,
+			NewValue: md.New.IdFormat,
+		}}
+	}
+	return nil
 }
@@ -11,7 +11,7 @@ import (
 
 func TestRemovingAFieldMessages(t *testing.T) {
 	for _, tc := range resourceSchemaRule_RemovingAField_TestCases {
-		gotMessages := RemovingAFieldMessages("resource", tc.resourceDiff)
+		gotMessages := RemovingAFieldMessages(diff.KindResource, "resource", tc.resourceDiff)
 
 		if len(gotMessages) != len(tc.expectedFields) {
 			t.Errorf("RemovingAFieldMessages(%v) got %d messages; want %d", tc.name, len(gotMessages), len(tc.expectedFields))
@@ -30,7 +30,7 @@ func TestRemovingAFieldMessages(t *testing.T) {
 
 func TestAddingExactlyOneOfMessages(t *testing.T) {
 	for _, tc := range resourceSchemaRule_AddingExactlyOneOf_TestCases {
-		gotMessages := AddingExactlyOneOfMessages("resource", tc.resourceDiff)
+		gotMessages := AddingExactlyOneOfMessages(diff.KindResource, "resource", tc.resourceDiff)
 		if len(gotMessages) != len(tc.expectedFields) {
 			t.Errorf("AddingExactlyOneOfMessages(%v) got %d messages; want %d", tc.name, len(gotMessages), len(tc.expectedFields))
 			continue
@@ -47,6 +47,56 @@ func TestAddingExactlyOneOfMessages(t *testing.T) {
 
 }
 
+func TestRemovingEnumValuesMessages(t *testing.T) {
+	for _, tc := range resourceSchemaRule_RemovingEnumValues_TestCases {
+		gotMessages := RemovingEnumValuesMessages(diff.KindResource, "resource", tc.resourceDiff)
+		if len(gotMessages) != len(tc.expectedFields) {
+			t.Errorf("RemovingEnumValuesMessages(%v) got %d messages; want %d", tc.name, len(gotMessages), len(tc.expectedFields))
+			continue
+		}
+		wantFields := tc.expectedFields
+		sort.Strings(wantFields)
+		sort.Strings(gotMessages)
+		for i, field := range wantFields {
+			if !strings.Contains(gotMessages[i], field) {
+				t.Errorf("RemovingEnumValuesMessages(%v) got message %q; want field %q", tc.name, gotMessages[i], field)
+			}
+		}
+	}
+}
+
+func TestChangingIdFormatMessages(t *testing.T) {
+	cases := map[string]struct {
+		oldIdFormat   string
+		newIdFormat   string
+		wantViolation bool
+	}{
+		"unchanged": {
+			oldIdFormat: "projects/{{project}}/things/{{name}}",
+			newIdFormat: "projects/{{project}}/things/{{name}}",
+		},
+		"changed": {
+			oldIdFormat:   "projects/{{project}}/things/{{name}}",
+			newIdFormat:   "{{name}}",
+			wantViolation: true,
+		},
+		"newly recorded": {
+			newIdFormat: "{{name}}",
+		},
+	}
+	for tn, tc := range cases {
+		rd := diff.ResourceDiff{
+			Metadata: diff.ResourceMetadataDiff{
+				Old: &diff.ResourceMetadata{IdFormat: tc.oldIdFormat},
+				New: &diff.ResourceMetadata{IdFormat: tc.newIdFormat},
+			},
+		}
+		if got := ChangingIdFormatMessages(diff.KindResource, "resource", rd); (len(got) > 0) != tc.wantViolation {
+			t.Errorf("ChangingIdFormatMessages(%v) got %v; want violation %v", tn, got, tc.wantViolation)
+		}
+	}
+}
+
 type resourceSchemaTestCase struct {
 	name           string
 	resourceDiff   diff.ResourceDiff
@@ -427,3 +477,63 @@ var resourceSchemaRule_AddingExactlyOneOf_TestCases = []resourceSchemaTestCase{
 		},
 	},
 }
+
+func enumMetadata(values map[string][]string) *diff.ResourceMetadata {
+	m := &diff.ResourceMetadata{}
+	for field, enumValues := range values {
+		m.Fields = append(m.Fields, diff.FieldMetadata{Field: field, EnumValues: enumValues})
+	}
+	return m
+}
+
+var resourceSchemaRule_RemovingEnumValues_TestCases = []resourceSchemaTestCase{
+	{
+		name: "control",
+		resourceDiff: diff.ResourceDiff{
+			Metadata: diff.ResourceMetadataDiff{
+				Old: enumMetadata(map[string][]string{"field-a": {"A", "B"}}),
+				New: enumMetadata(map[string][]string{"field-a": {"A", "B"}}),
+			},
+		},
+		expectedFields: []string{},
+	},
+	{
+		name: "adding a value",
+		resourceDiff: diff.ResourceDiff{
+			Metadata: diff.ResourceMetadataDiff{
+				Old: enumMetadata(map[string][]string{"field-a": {"A"}}),
+				New: enumMetadata(map[string][]string{"field-a": {"B", "A"}}),
+			},
+		},
+		expectedFields: []string{},
+	},
+	{
+		name: "removing values",
+		resourceDiff: diff.ResourceDiff{
+			Metadata: diff.ResourceMetadataDiff{
+				Old: enumMetadata(map[string][]string{"field-a": {"A", "B", "C"}, "block.field-b": {"X", "Y"}, "field-c": {"Z"}}),
+				New: enumMetadata(map[string][]string{"field-a": {"A"}, "block.field-b": {"X"}, "field-c": {"Z"}}),
+			},
+		},
+		expectedFields: []string{"field-a", "block.field-b"},
+	},
+	{
+		name: "field removed",
+		resourceDiff: diff.ResourceDiff{
+			Metadata: diff.ResourceMetadataDiff{
+				Old: enumMetadata(map[string][]string{"field-a": {"A", "B"}}),
+				New: enumMetadata(map[string][]string{}),
+			},
+		},
+		expectedFields: []string{},
+	},
+	{
+		name: "no metadata",
+		resourceDiff: diff.ResourceDiff{
+			Metadata: diff.ResourceMetadataDiff{
+				Old: enumMetadata(map[string][]string{"field-a": {"A", "B"}}),
+			},
+		},
+		expectedFields: []string{},
+	},
+}
@@ -3,10 +3,11 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-
 	"io"
 	"os"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/breaking_changes"
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
@@ -16,18 +17,28 @@ import (
 const breakingChangesDesc = `Check for breaking changes between the new / old Terraform provider versions.`
 
 type breakingChangesOptions struct {
-	rootOptions       *rootOptions
-	computeSchemaDiff func() diff.SchemaDiff
-	stdout            io.Writer
+	rootOptions               *rootOptions
+	computeProviderSchemaDiff func() diff.ProviderSchemaDiff
+	missingSDKDetails         func() bool
+	now                       func() time.Time
+	format                    string
+	configPath                string
+	stdout                    io.Writer
+	stderr                    io.Writer
 }
 
 func newBreakingChangesCmd(rootOptions *rootOptions) *cobra.Command {
 	o := &breakingChangesOptions{
 		rootOptions: rootOptions,
-		computeSchemaDiff: func() diff.SchemaDiff {
-			return schemaDiff
+		computeProviderSchemaDiff: func() diff.ProviderSchemaDiff {
+			return rootOptions.providerSchemaDiff()
+		},
+		missingSDKDetails: func() bool {
+			return rootOptions.providerSchemas().missingSDKDetails
 		},
+		now:    time.Now,
 		stdout: os.Stdout,
+		stderr: os.Stderr,
 	}
 	cmd := &cobra.Command{
 		Use:   "breaking-changes",
@@ -37,15 +48,60 @@ func newBreakingChangesCmd(rootOptions *rootOptions) *cobra.Command {
 			return o.run()
 		},
 	}
+	cmd.Flags().StringVar(&o.format, "format", "json", "output format: json, sarif or markdown")
+	cmd.Flags().StringVar(&o.configPath, "config", "", "path to a YAML file overriding the severity of breaking changes")
 	return cmd
 }
 func (o *breakingChangesOptions) run() error {
-	schemaDiff := o.computeSchemaDiff()
-	breakingChanges := breaking_changes.ComputeBreakingChanges(schemaDiff)
+	format := o.format
+	if format == "" {
+		format = "json"
+	}
+	write, ok := breakingChangesWriters[format]
+	if !ok {
+		return fmt.Errorf("unknown format %q, expected json, sarif or markdown", format)
+	}
+	var config *breaking_changes.Config
+	if o.configPath != "" {
+		c, err := breaking_changes.LoadConfig(o.configPath)
+		if err != nil {
+			return err
+		}
+		config = c
+	}
+
+	providerSchemaDiff := o.computeProviderSchemaDiff()
+	breakingChanges := breaking_changes.ComputeProviderBreakingChanges(providerSchemaDiff)
+	if config != nil {
+		var expired []breaking_changes.Override
+		breakingChanges, expired = config.Apply(breakingChanges, o.now())
+		for _, e := range expired {
+			fmt.Fprintf(o.stderr, "warning: ignoring the override of %s for %s, which expired on %s\n", e.Rule, e.Resource, e.Expires)
+		}
+	}
 	sort.Slice(breakingChanges, func(i, j int) bool {
-		return breakingChanges[i].Message < breakingChanges[j].Message
+		if breakingChanges[i].Message != breakingChanges[j].Message {
+			return breakingChanges[i].Message < breakingChanges[j].Message
+		}
+		return breakingChanges[i].Kind < breakingChanges[j].Kind
 	})
-	if err := json.NewEncoder(o.stdout).Encode(breakingChanges); err != nil {
+	if o.missingSDKDetails != nil && o.missingSDKDetails() {
+		fmt.Fprintf(o.stderr, "warning: %s, skipping the rules relying on them: %s\n", missingSDKDetailsReason, strings.Join(breaking_changes.SDKDetailRules, ", "))
+		breakingChanges = append(breakingChanges, breaking_changes.SkippedRules(breaking_changes.SDKDetailRules, missingSDKDetailsReason)...)
+	}
+	return write(o.stdout, breakingChanges)
+}
+
+const missingSDKDetailsReason = "the schema JSON doesn't record ForceNew, defaults, validation, diff suppression or importers, write it with export-schema to include them"
+
+var breakingChangesWriters = map[string]func(io.Writer, []breaking_changes.BreakingChange) error{
+	"json":     writeBreakingChangesJSON,
+	"sarif":    writeBreakingChangesSARIF,
+	"markdown": writeBreakingChangesMarkdown,
+}
+
+func writeBreakingChangesJSON(w io.Writer, breakingChanges []breaking_changes.BreakingChange) error {
+	if err := json.NewEncoder(w).Encode(breakingChanges); err != nil {
 		return fmt.Errorf("error encoding json: %w", err)
 	}
 	return nil
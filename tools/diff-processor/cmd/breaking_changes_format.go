@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/breaking_changes"
+)
+
+// The subset of SARIF 2.1.0 needed to report breaking changes, see
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID      string `json:"id"`
+	HelpURI string `json:"helpUri"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+var sarifLevels = map[breaking_changes.Severity]string{
+	breaking_changes.SeverityError:   "error",
+	breaking_changes.SeverityWarning: "warning",
+	breaking_changes.SeverityInfo:    "note",
+	breaking_changes.SeveritySkipped: "none",
+}
+
+func writeBreakingChangesSARIF(w io.Writer, breakingChanges []breaking_changes.BreakingChange) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "diff-processor",
+			InformationURI: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	seenRules := make(map[string]bool)
+	for _, bc := range breakingChanges {
+		if !seenRules[bc.RuleName] {
+			seenRules[bc.RuleName] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: bc.RuleName, HelpURI: bc.DocumentationReference})
+		}
+		locations := []sarifLocation{}
+		if bc.Resource != "" {
+			location := sarifLogicalLocation{FullyQualifiedName: bc.Resource, Kind: "resource"}
+			if bc.Field != "" {
+				location = sarifLogicalLocation{FullyQualifiedName: bc.Resource + "." + bc.Field, Kind: "member"}
+			}
+			locations = append(locations, sarifLocation{LogicalLocations: []sarifLogicalLocation{location}})
+		}
+		properties := make(map[string]string)
+		for k, v := range map[string]string{
+			"kind":     string(bc.Kind),
+			"oldValue": bc.OldValue,
+			"newValue": bc.NewValue,
+			"reason":   bc.Reason,
+		} {
+			if v != "" {
+				properties[k] = v
+			}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:     bc.RuleName,
+			Level:      sarifLevels[bc.Severity],
+			Message:    sarifMessage{Text: bc.Message},
+			Locations:  locations,
+			Properties: properties,
+		})
+	}
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(log); err != nil {
+		return fmt.Errorf("error encoding sarif: %w", err)
+	}
+	return nil
+}
+
+func writeBreakingChangesMarkdown(w io.Writer, breakingChanges []breaking_changes.BreakingChange) error {
+	var sb strings.Builder
+	sb.WriteString("## Breaking changes\n\n")
+	detected := false
+	for _, bc := range breakingChanges {
+		detected = detected || bc.Severity != breaking_changes.SeveritySkipped
+	}
+	if !detected {
+		sb.WriteString("No breaking changes detected.\n")
+		if len(breakingChanges) > 0 {
+			sb.WriteString("\n")
+		}
+	}
+	for _, severity := range breaking_changes.Severities {
+		var lines []string
+		for _, bc := range breakingChanges {
+			if bc.Severity != severity {
+				continue
+			}
+			line := fmt.Sprintf("- %s - [reference](%s)", bc.Message, bc.DocumentationReference)
+			if bc.Reason != "" {
+				line += fmt.Sprintf(" (overridden: %s)", bc.Reason)
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "### %s%s (%d)\n\n", strings.ToUpper(string(severity[:1])), severity[1:], len(lines))
+		sb.WriteString(strings.Join(lines, "\n"))
+		sb.WriteString("\n\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
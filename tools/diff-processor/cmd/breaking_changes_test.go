@@ -3,11 +3,17 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/breaking_changes"
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -80,8 +86,10 @@ func TestBreakingChangesCmd(t *testing.T) {
 
 			var buf bytes.Buffer
 			o := breakingChangesOptions{
-				computeSchemaDiff: func() diff.SchemaDiff {
-					return diff.ComputeSchemaDiff(tc.oldResourceMap, tc.newResourceMap)
+				computeProviderSchemaDiff: func() diff.ProviderSchemaDiff {
+					return diff.ProviderSchemaDiff{
+						diff.KindResource: diff.ComputeSchemaDiff(tc.oldResourceMap, tc.newResourceMap),
+					}
 				},
 				stdout: &buf,
 			}
@@ -105,3 +113,187 @@ func TestBreakingChangesCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestBreakingChangesCmd_formats(t *testing.T) {
+	oldResourceMap := map[string]*schema.Resource{
+		"google_x": {
+			Schema: map[string]*schema.Schema{
+				"field_a": {Type: schema.TypeString, Optional: true},
+				"field_b": {Type: schema.TypeString, Optional: true},
+			},
+		},
+	}
+	newResourceMap := map[string]*schema.Resource{
+		"google_x": {
+			Schema: map[string]*schema.Schema{
+				"field_a": {Type: schema.TypeString, Required: true},
+			},
+		},
+	}
+	run := func(t *testing.T, format string) string {
+		var buf bytes.Buffer
+		o := breakingChangesOptions{
+			computeProviderSchemaDiff: func() diff.ProviderSchemaDiff {
+				return diff.ProviderSchemaDiff{
+					diff.KindResource: diff.ComputeSchemaDiff(oldResourceMap, newResourceMap),
+				}
+			},
+			format: format,
+			stdout: &buf,
+		}
+		if err := o.run(); err != nil {
+			t.Fatalf("Error running command: %s", err)
+		}
+		return buf.String()
+	}
+
+	t.Run("sarif", func(t *testing.T) {
+		var got sarifLog
+		if err := json.Unmarshal([]byte(run(t, "sarif")), &got); err != nil {
+			t.Fatalf("Failed to unmarshall output: %s", err)
+		}
+		if len(got.Runs) != 1 || len(got.Runs[0].Results) != 2 || len(got.Runs[0].Tool.Driver.Rules) != 2 {
+			t.Fatalf("Expected 1 run with 2 results and 2 rules, got %+v", got)
+		}
+		result := got.Runs[0].Results[0]
+		want := sarifResult{
+			RuleID:  "field-optional-to-required",
+			Level:   "error",
+			Message: sarifMessage{Text: "Field `field_a` changed from optional to required on `google_x`"},
+			Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{
+				{FullyQualifiedName: "google_x.field_a", Kind: "member"},
+			}}},
+			Properties: map[string]string{"kind": "resource", "oldValue": "optional", "newValue": "required"},
+		}
+		if diff := cmp.Diff(want, result); diff != "" {
+			t.Errorf("Unexpected result (-want, +got): %s", diff)
+		}
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		got := run(t, "markdown")
+		for _, want := range []string{
+			"### Error (2)",
+			"- Field `field_a` changed from optional to required on `google_x` - [reference](https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#field-optional-to-required)",
+			"- Field `field_b` within resource `google_x` was either removed or renamed",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("Expected output to contain %q, got:\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		o := breakingChangesOptions{format: "xml"}
+		if err := o.run(); err == nil {
+			t.Errorf("Expected an error for an unknown format")
+		}
+	})
+}
+
+func TestBreakingChangesCmd_config(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(config, []byte(`overrides:
+  - rule: field-optional-to-required
+    resource: google_x
+    field: field_a
+    severity: warning
+    expires: 2025-01-31
+    reason: The API never accepted requests without field_a.
+  - rule: resource-schema-field-removal-or-rename
+    resource: google_*
+    severity: suppress
+    expires: 2025-01-31
+    reason: Renamed before the release.
+  - rule: resource-map-resource-removal-or-rename
+    resource: google_y
+    severity: suppress
+    expires: 2024-12-31
+    reason: Expired.
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	oldResourceMap := map[string]*schema.Resource{
+		"google_x": {
+			Schema: map[string]*schema.Schema{
+				"field_a": {Type: schema.TypeString, Optional: true},
+				"field_b": {Type: schema.TypeString, Optional: true},
+			},
+		},
+		"google_y": {},
+	}
+	newResourceMap := map[string]*schema.Resource{
+		"google_x": {
+			Schema: map[string]*schema.Schema{
+				"field_a": {Type: schema.TypeString, Required: true},
+			},
+		},
+	}
+
+	var stdout, stderr bytes.Buffer
+	o := breakingChangesOptions{
+		computeProviderSchemaDiff: func() diff.ProviderSchemaDiff {
+			return diff.ProviderSchemaDiff{
+				diff.KindResource: diff.ComputeSchemaDiff(oldResourceMap, newResourceMap),
+			}
+		},
+		now: func() time.Time {
+			return time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+		},
+		format:     "json",
+		configPath: config,
+		stdout:     &stdout,
+		stderr:     &stderr,
+	}
+	if err := o.run(); err != nil {
+		t.Fatalf("Error running command: %s", err)
+	}
+
+	var got []breaking_changes.BreakingChange
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to unmarshall output: %s", err)
+	}
+	var severities []string
+	for _, bc := range got {
+		severities = append(severities, fmt.Sprintf("%s %s %s", bc.RuleName, bc.Severity, bc.Reason))
+	}
+	want := []string{
+		"field-optional-to-required warning The API never accepted requests without field_a.",
+		"resource-map-resource-removal-or-rename error ",
+	}
+	if diff := cmp.Diff(want, severities); diff != "" {
+		t.Errorf("Unexpected breaking changes (-want, +got): %s", diff)
+	}
+	if !strings.Contains(stderr.String(), "resource-map-resource-removal-or-rename for google_y, which expired on 2024-12-31") {
+		t.Errorf("Expected a warning about the expired override, got %q", stderr.String())
+	}
+}
+
+func TestBreakingChangesCmd_missingSDKDetails(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	o := breakingChangesOptions{
+		computeProviderSchemaDiff: func() diff.ProviderSchemaDiff {
+			return diff.ProviderSchemaDiff{}
+		},
+		missingSDKDetails: func() bool { return true },
+		format:            "markdown",
+		stdout:            &stdout,
+		stderr:            &stderr,
+	}
+	if err := o.run(); err != nil {
+		t.Fatalf("Error running command: %s", err)
+	}
+	if got := stderr.String(); !strings.Contains(got, "warning: the schema JSON doesn't record ForceNew") {
+		t.Errorf("Expected a warning, got %q", got)
+	}
+	got := stdout.String()
+	for _, want := range []string{
+		"No breaking changes detected.",
+		fmt.Sprintf("### Skipped (%d)", len(breaking_changes.SDKDetailRules)),
+		"- Rule `field-becoming-force-new` was skipped: the schema JSON doesn't record ForceNew",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+}
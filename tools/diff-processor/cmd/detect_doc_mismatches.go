@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/detector"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+)
+
+const detectDocMismatchesDesc = `Compare the documents of all the new provider's resources in the given repository with their schemas.
+
+Reports fields whose documented Required / Optional / Output marker, default value, nested block or possible values don't match the schema.`
+
+type detectDocMismatchesOptions struct {
+	rootOptions  *rootOptions
+	newResources func() (map[string]*schema.Resource, map[string]*diff.ResourceMetadata)
+	stdout       io.Writer
+}
+
+func newDetectDocMismatchesCmd(rootOptions *rootOptions) *cobra.Command {
+	o := &detectDocMismatchesOptions{
+		rootOptions: rootOptions,
+		newResources: func() (map[string]*schema.Resource, map[string]*diff.ResourceMetadata) {
+			ps := rootOptions.providerSchemas()
+			return ps.new[diff.KindResource], ps.newMetadata
+		},
+		stdout: os.Stdout,
+	}
+	cmd := &cobra.Command{
+		Use:   "detect-doc-mismatches REPO_PATH",
+		Short: "Compute list of fields whose documents don't match the schema",
+		Long:  detectDocMismatchesDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.run(args)
+		},
+	}
+	return cmd
+}
+
+func (o *detectDocMismatchesOptions) run(args []string) error {
+	resources, metadata := o.newResources()
+	detected, err := detector.DetectDocMismatches(resources, metadata, args[0])
+	if err != nil {
+		return err
+	}
+
+	names := maps.Keys(detected)
+	slices.Sort(names)
+	arr := []detector.DocMismatchDetails{}
+	for _, name := range names {
+		arr = append(arr, detected[name])
+	}
+	if err := json.NewEncoder(o.stdout).Encode(arr); err != nil {
+		return fmt.Errorf("error encoding json: %w", err)
+	}
+	return nil
+}
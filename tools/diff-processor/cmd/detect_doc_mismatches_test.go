@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/detector"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDetectDocMismatches(t *testing.T) {
+	cases := []struct {
+		name      string
+		resources map[string]*schema.Resource
+		want      []detector.DocMismatchDetails
+	}{
+		{
+			name: "no resources",
+			want: []detector.DocMismatchDetails{},
+		},
+		{
+			name: "sorted by resource",
+			resources: map[string]*schema.Resource{
+				"b_resource": {
+					Schema: map[string]*schema.Schema{
+						"name": {Type: schema.TypeString, Required: true},
+					},
+				},
+				"a_resource": {
+					Schema: map[string]*schema.Schema{
+						"field_one": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"a": {Type: schema.TypeString, Required: true},
+							},
+						}},
+					},
+				},
+			},
+			want: []detector.DocMismatchDetails{
+				{
+					Name:       "a_resource",
+					FilePath:   "/website/docs/r/a_resource.html.markdown",
+					Mismatches: []detector.DocMismatch{{Field: "field_one.a", Message: "documented as Optional but Required"}},
+				},
+				{
+					Name:       "b_resource",
+					FilePath:   "/website/docs/r/b_resource.html.markdown",
+					Mismatches: []detector.DocMismatch{{Field: "name", Message: "documented as Optional but Required"}},
+				},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			o := detectDocMismatchesOptions{
+				newResources: func() (map[string]*schema.Resource, map[string]*diff.ResourceMetadata) {
+					return tc.resources, nil
+				},
+				stdout: &buf,
+			}
+
+			if err := o.run([]string{"../testdata"}); err != nil {
+				t.Fatalf("Error running command: %s", err)
+			}
+
+			var got []detector.DocMismatchDetails
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("Failed to unmarshall output: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected result, diff(-want, got) = %s", diff)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/detector"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
 	"github.com/GoogleCloudPlatform/magic-modules/tools/test-reader/reader"
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
@@ -42,7 +43,7 @@ func (o *detectMissingTestsOptions) run(args []string) error {
 		glog.Infof("error reading path: %s, err: %v", path, err)
 	}
 
-	missingTests, err := detector.DetectMissingTests(schemaDiff, allTests)
+	missingTests, err := detector.DetectMissingTests(o.rootOptions.providerSchemaDiff()[diff.KindResource], allTests)
 	if err != nil {
 		return fmt.Errorf("error detecting missing tests: %v", err)
 	}
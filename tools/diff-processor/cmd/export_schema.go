@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+const exportSchemaDesc = `Write the schema of the new provider in the format of terraform providers schema -json.
+
+Unlike Terraform, it records the ForceNew, default, validation, diff suppression and importer of SDK fields and resources, so that the rules relying on them are checked when diffing schema directories.`
+
+type exportSchemaOptions struct {
+	rootOptions *rootOptions
+	address     string
+	schemaJSON  func(address string) ([]byte, error)
+	stdout      io.Writer
+}
+
+func newExportSchemaCmd(rootOptions *rootOptions) *cobra.Command {
+	o := &exportSchemaOptions{
+		rootOptions: rootOptions,
+		schemaJSON:  builtProviderSchemaJSON,
+		stdout:      os.Stdout,
+	}
+	cmd := &cobra.Command{
+		Use:   "export-schema",
+		Short: "Write the schema of the new provider as JSON",
+		Long:  exportSchemaDesc,
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.run()
+		},
+	}
+	cmd.Flags().StringVar(&o.address, "address", "registry.terraform.io/hashicorp/google", "provider address to write the schema under, such as registry.terraform.io/hashicorp/google-beta")
+	return cmd
+}
+
+func (o *exportSchemaOptions) run() error {
+	b, err := o.schemaJSON(o.address)
+	if err != nil {
+		return fmt.Errorf("error writing the schema: %w", err)
+	}
+	_, err = fmt.Fprintln(o.stdout, string(b))
+	return err
+}
@@ -0,0 +1,90 @@
+//go:build !no_providers
+
+package cmd
+
+import (
+	newFwProvider "google/provider/new/google/fwprovider"
+	newProvider "google/provider/new/google/provider"
+	oldFwProvider "google/provider/old/google/fwprovider"
+	oldProvider "google/provider/old/google/provider"
+
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/golang/glog"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// loadBuiltProviderSchemas loads the schemas of the old and new providers
+// compiled into the diff-processor.
+func loadBuiltProviderSchemas() (providerSchemas, error) {
+	return providerSchemas{
+		old:         mustLoadProviderSchema("old", oldProvider.Provider(), oldFwProvider.New),
+		new:         mustLoadProviderSchema("new", newProvider.Provider(), newFwProvider.New),
+		oldMetadata: mustLoadResourceMetadata("old"),
+		newMetadata: mustLoadResourceMetadata("new"),
+	}, nil
+}
+
+func mustLoadProviderSchema(version string, sdk *schema.Provider, newFw func(*schema.Provider) provider.ProviderWithMetaSchema) diff.ProviderSchema {
+	ps, err := loadProviderSchema(sdk, newFw(sdk))
+	if err != nil {
+		glog.Fatalf("error loading the %s provider schema: %s", version, err)
+	}
+	return ps
+}
+
+// mustLoadResourceMetadata reads the metadata files of the provider copied to
+// dir. The directory only exists when running from the diff-processor's
+// root, so it is skipped otherwise, such as in tests.
+func mustLoadResourceMetadata(dir string) map[string]*diff.ResourceMetadata {
+	metadata, err := diff.LoadResourceMetadata(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		glog.Fatalf("error loading the %s resource metadata: %s", dir, err)
+	}
+	return metadata
+}
+
+// builtProviderSchemaJSON writes the schema of the new provider compiled into
+// the diff-processor, under the given provider address.
+func builtProviderSchemaJSON(address string) ([]byte, error) {
+	sdk := newProvider.Provider()
+	resp, err := frameworkProviderSchema(newFwProvider.New(sdk))
+	if err != nil {
+		return nil, err
+	}
+	return diff.MarshalProviderSchemaJSON(address, sdk, resp)
+}
+
+// loadProviderSchema returns the schema of a provider muxing an SDK provider
+// and a plugin framework provider, reading the latter from its schema
+// response like Terraform does.
+func loadProviderSchema(sdk *schema.Provider, fw provider.Provider) (diff.ProviderSchema, error) {
+	resp, err := frameworkProviderSchema(fw)
+	if err != nil {
+		return nil, err
+	}
+	return diff.NewProviderSchema(sdk, resp)
+}
+
+func frameworkProviderSchema(fw provider.Provider) (*tfprotov5.GetProviderSchemaResponse, error) {
+	resp, err := providerserver.NewProtocol5(fw)().GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			return nil, fmt.Errorf("%s: %s", d.Summary, d.Detail)
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,17 @@
+//go:build no_providers
+
+package cmd
+
+import "errors"
+
+// loadBuiltProviderSchemas fails when the diff-processor is built without
+// the old and new providers, which must then be read from schema directories.
+func loadBuiltProviderSchemas() (providerSchemas, error) {
+	return providerSchemas{}, errors.New("the diff-processor was built without providers, set --old-schema-dir and --new-schema-dir")
+}
+
+// builtProviderSchemaJSON fails when the diff-processor is built without the
+// old and new providers.
+func builtProviderSchemaJSON(address string) ([]byte, error) {
+	return nil, errors.New("the diff-processor was built without providers, which export-schema writes the schema of")
+}
@@ -3,13 +3,34 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sync"
 
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/golang/glog"
 	"github.com/spf13/cobra"
 )
 
 const rootCmdDesc = "Utilities for interacting with diffs between Terraform schema versions."
 
 type rootOptions struct {
+	oldSchemaDir string
+	newSchemaDir string
+
+	schemasOnce sync.Once
+	schemas     providerSchemas
+
+	schemaDiffOnce sync.Once
+	schemaDiff     diff.ProviderSchemaDiff
+}
+
+// providerSchemas are the schemas and resource metadata of the old and new
+// providers.
+type providerSchemas struct {
+	old, new                 diff.ProviderSchema
+	oldMetadata, newMetadata map[string]*diff.ResourceMetadata
+	// missingSDKDetails is set when either schema was read from a JSON schema
+	// without the SDK details, so that rules relying on them find nothing.
+	missingSDKDetails bool
 }
 
 func newRootCmd() (*cobra.Command, *rootOptions, error) {
@@ -21,13 +42,65 @@ func newRootCmd() (*cobra.Command, *rootOptions, error) {
 		SilenceUsage:  true,
 		SilenceErrors: true,
 	}
+	cmd.PersistentFlags().StringVar(&o.oldSchemaDir, "old-schema-dir", "", "directory with the old provider's schema.json and metadata files, used instead of the built provider")
+	cmd.PersistentFlags().StringVar(&o.newSchemaDir, "new-schema-dir", "", "directory with the new provider's schema.json and metadata files, used instead of the built provider")
 	cmd.AddCommand(newBreakingChangesCmd(o))
 	cmd.AddCommand(newDetectMissingTestsCmd(o))
 	cmd.AddCommand(newSchemaDiffCmd(o))
 	cmd.AddCommand(newDetectMissingDocsCmd(o))
+	cmd.AddCommand(newDetectDocMismatchesCmd(o))
+	cmd.AddCommand(newUpgradeImpactCmd(o))
+	cmd.AddCommand(newTestCoverageCmd(o))
+	cmd.AddCommand(newExportSchemaCmd(o))
 	return cmd, o, nil
 }
 
+// providerSchemas returns the schemas of the old and new providers, loading
+// them on first use. They're read from the schema directories when they are
+// set, and from the providers built into the diff-processor otherwise.
+func (o *rootOptions) providerSchemas() providerSchemas {
+	o.schemasOnce.Do(func() {
+		var err error
+		switch {
+		case o.oldSchemaDir != "" && o.newSchemaDir != "":
+			o.schemas, err = loadSchemaDirs(o.oldSchemaDir, o.newSchemaDir)
+		case o.oldSchemaDir != "" || o.newSchemaDir != "":
+			err = fmt.Errorf("--old-schema-dir and --new-schema-dir must be set together")
+		default:
+			o.schemas, err = loadBuiltProviderSchemas()
+		}
+		if err != nil {
+			glog.Fatalf("error loading the provider schemas: %s", err)
+		}
+	})
+	return o.schemas
+}
+
+func loadSchemaDirs(oldDir, newDir string) (providerSchemas, error) {
+	var ps providerSchemas
+	var oldDetails, newDetails bool
+	var err error
+	if ps.old, ps.oldMetadata, oldDetails, err = diff.LoadSchemaDir(oldDir); err != nil {
+		return ps, err
+	}
+	if ps.new, ps.newMetadata, newDetails, err = diff.LoadSchemaDir(newDir); err != nil {
+		return ps, err
+	}
+	ps.missingSDKDetails = !oldDetails || !newDetails
+	return ps, nil
+}
+
+// providerSchemaDiff returns the diff between the old and new providers,
+// computing it on first use.
+func (o *rootOptions) providerSchemaDiff() diff.ProviderSchemaDiff {
+	o.schemaDiffOnce.Do(func() {
+		ps := o.providerSchemas()
+		o.schemaDiff = diff.ComputeProviderSchemaDiff(ps.old, ps.new)
+		o.schemaDiff[diff.KindResource].AddMetadata(ps.oldMetadata, ps.newMetadata)
+	})
+	return o.schemaDiff
+}
+
 // Execute is the entry-point for all commands.
 // This lets us keep all new command functions private.
 func Execute() {
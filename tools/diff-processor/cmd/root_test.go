@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/breaking_changes"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestRootOptionsProviderSchemaDiff_schemaDirs(t *testing.T) {
+	writeSchema := func(attributes string) string {
+		dir := t.TempDir()
+		b := `{"provider_schemas": {"registry.terraform.io/hashicorp/google": {"resource_schemas": {
+  "google_x": {"block": {"attributes": {` + attributes + `}}}
+}}}}`
+		if err := os.WriteFile(filepath.Join(dir, diff.SchemaJSONFile), []byte(b), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	_, o, err := newRootCmd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.oldSchemaDir = writeSchema(`"name": {"type": "string", "required": true}`)
+	o.newSchemaDir = writeSchema(`"name": {"type": "string", "required": true}, "zone": {"type": "string", "optional": true}`)
+
+	if !o.providerSchemas().missingSDKDetails {
+		t.Error("providerSchemas() didn't report missing SDK details for schemas written by Terraform")
+	}
+	psd := o.providerSchemaDiff()
+	if got := psd[diff.KindResource]["google_x"].Fields["zone"]; got.Old != nil || got.New == nil {
+		t.Errorf("google_x.zone diff = %+v, want an added field", got)
+	}
+	if got := o.providerSchemaDiff(); len(got[diff.KindResource]) != len(psd[diff.KindResource]) {
+		t.Errorf("second providerSchemaDiff() = %v, want the cached diff", got)
+	}
+}
+
+func TestRootOptionsProviderSchemas_exportedSchemaDirs(t *testing.T) {
+	writeSchema := func(forceNew bool) string {
+		dir := t.TempDir()
+		sdk := &schema.Provider{ResourcesMap: map[string]*schema.Resource{
+			"google_x": {Schema: map[string]*schema.Schema{
+				"name": {Type: schema.TypeString, Required: true, ForceNew: forceNew},
+			}},
+		}}
+		b, err := diff.MarshalProviderSchemaJSON("registry.terraform.io/hashicorp/google", sdk, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, diff.SchemaJSONFile), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	_, o, err := newRootCmd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.oldSchemaDir = writeSchema(false)
+	o.newSchemaDir = writeSchema(true)
+
+	if o.providerSchemas().missingSDKDetails {
+		t.Error("providerSchemas() reported missing SDK details for exported schemas")
+	}
+	var stdout, stderr bytes.Buffer
+	bo := breakingChangesOptions{
+		computeProviderSchemaDiff: o.providerSchemaDiff,
+		missingSDKDetails:         func() bool { return o.providerSchemas().missingSDKDetails },
+		stdout:                    &stdout,
+		stderr:                    &stderr,
+	}
+	if err := bo.run(); err != nil {
+		t.Fatalf("Error running command: %s", err)
+	}
+	var got []breaking_changes.BreakingChange
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to unmarshall output: %s", err)
+	}
+	if len(got) != 1 || got[0].RuleName != breaking_changes.FieldBecomingForceNew.Identifier {
+		t.Errorf("breaking changes = %+v, want google_x.name becoming ForceNew", got)
+	}
+	if stderr.Len() > 0 {
+		t.Errorf("unexpected warnings: %s", stderr.String())
+	}
+}
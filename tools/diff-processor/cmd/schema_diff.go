@@ -1,9 +1,6 @@
 package cmd
 
 import (
-	newProvider "google/provider/new/google/provider"
-	oldProvider "google/provider/old/google/provider"
-
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,23 +13,32 @@ import (
 
 const schemaDiffDesc = `Return a simple summary of the schema diff for this build.`
 
-var schemaDiff = diff.ComputeSchemaDiff(oldProvider.ResourceMap(), newProvider.ResourceMap())
-
 type simpleSchemaDiff struct {
 	AddedResources, ModifiedResources, RemovedResources []string
+	// Suggested deprecation release notes for fields that were renamed and
+	// kept as deprecated aliases. They need to be prefixed with the product.
+	RenamedFieldReleaseNotes []string `json:",omitempty"`
+	// Changes of every kind, including resources, sorted by kind and name.
+	Changes []schemaChange `json:",omitempty"`
+}
+
+type schemaChange struct {
+	Kind   diff.Kind
+	Name   string
+	Change string // "added", "modified" or "removed"
 }
 
 type schemaDiffOptions struct {
-	rootOptions       *rootOptions
-	computeSchemaDiff func() diff.SchemaDiff
-	stdout            io.Writer
+	rootOptions               *rootOptions
+	computeProviderSchemaDiff func() diff.ProviderSchemaDiff
+	stdout                    io.Writer
 }
 
 func newSchemaDiffCmd(rootOptions *rootOptions) *cobra.Command {
 	o := &schemaDiffOptions{
 		rootOptions: rootOptions,
-		computeSchemaDiff: func() diff.SchemaDiff {
-			return schemaDiff
+		computeProviderSchemaDiff: func() diff.ProviderSchemaDiff {
+			return rootOptions.providerSchemaDiff()
 		},
 		stdout: os.Stdout,
 	}
@@ -48,23 +54,44 @@ func newSchemaDiffCmd(rootOptions *rootOptions) *cobra.Command {
 	return cmd
 }
 func (o *schemaDiffOptions) run() error {
-	schemaDiff := o.computeSchemaDiff()
+	providerSchemaDiff := o.computeProviderSchemaDiff()
 
 	simple := simpleSchemaDiff{}
 
-	for k, d := range schemaDiff {
+	for k, d := range providerSchemaDiff[diff.KindResource] {
 		if d.ResourceConfig.Old == nil {
 			simple.AddedResources = append(simple.AddedResources, k)
 		} else if d.ResourceConfig.New == nil {
 			simple.RemovedResources = append(simple.RemovedResources, k)
 		} else {
 			simple.ModifiedResources = append(simple.ModifiedResources, k)
+			for alias, field := range d.RenamedFields() {
+				simple.RenamedFieldReleaseNotes = append(simple.RenamedFieldReleaseNotes, fmt.Sprintf("deprecated `%s` field in `%s` resource. Use `%s` instead.", alias, k, field))
+			}
 		}
 	}
 
 	sort.Strings(simple.AddedResources)
 	sort.Strings(simple.ModifiedResources)
 	sort.Strings(simple.RemovedResources)
+	sort.Strings(simple.RenamedFieldReleaseNotes)
+
+	for _, kind := range diff.Kinds {
+		names := make([]string, 0, len(providerSchemaDiff[kind]))
+		for name := range providerSchemaDiff[kind] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			change := "modified"
+			if d := providerSchemaDiff[kind][name]; d.ResourceConfig.Old == nil {
+				change = "added"
+			} else if d.ResourceConfig.New == nil {
+				change = "removed"
+			}
+			simple.Changes = append(simple.Changes, schemaChange{Kind: kind, Name: name, Change: change})
+		}
+	}
 
 	if err := json.NewEncoder(o.stdout).Encode(simple); err != nil {
 		return fmt.Errorf("Error encoding json: %w", err)
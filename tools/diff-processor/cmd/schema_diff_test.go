@@ -40,6 +40,9 @@ func TestSchemaDiffCmdRun(t *testing.T) {
 			},
 			want: simpleSchemaDiff{
 				AddedResources: []string{"google_x_resource"},
+				Changes: []schemaChange{
+					{Kind: diff.KindResource, Name: "google_x_resource", Change: "added"},
+				},
 			},
 		},
 		{
@@ -60,6 +63,10 @@ func TestSchemaDiffCmdRun(t *testing.T) {
 			},
 			want: simpleSchemaDiff{
 				AddedResources: []string{"google_x_resource", "google_z_resource"},
+				Changes: []schemaChange{
+					{Kind: diff.KindResource, Name: "google_x_resource", Change: "added"},
+					{Kind: diff.KindResource, Name: "google_z_resource", Change: "added"},
+				},
 			},
 		},
 		{
@@ -76,6 +83,9 @@ func TestSchemaDiffCmdRun(t *testing.T) {
 			newResourceMap: map[string]*schema.Resource{},
 			want: simpleSchemaDiff{
 				RemovedResources: []string{"google_x_resource"},
+				Changes: []schemaChange{
+					{Kind: diff.KindResource, Name: "google_x_resource", Change: "removed"},
+				},
 			},
 		},
 		{
@@ -98,6 +108,10 @@ func TestSchemaDiffCmdRun(t *testing.T) {
 			newResourceMap: map[string]*schema.Resource{},
 			want: simpleSchemaDiff{
 				RemovedResources: []string{"google_x_resource", "google_z_resource"},
+				Changes: []schemaChange{
+					{Kind: diff.KindResource, Name: "google_x_resource", Change: "removed"},
+					{Kind: diff.KindResource, Name: "google_z_resource", Change: "removed"},
+				},
 			},
 		},
 		{
@@ -141,6 +155,9 @@ func TestSchemaDiffCmdRun(t *testing.T) {
 			},
 			want: simpleSchemaDiff{
 				ModifiedResources: []string{"google_x_resource"},
+				Changes: []schemaChange{
+					{Kind: diff.KindResource, Name: "google_x_resource", Change: "modified"},
+				},
 			},
 		},
 		{
@@ -174,6 +191,10 @@ func TestSchemaDiffCmdRun(t *testing.T) {
 			},
 			want: simpleSchemaDiff{
 				ModifiedResources: []string{"google_x_resource", "google_z_resource"},
+				Changes: []schemaChange{
+					{Kind: diff.KindResource, Name: "google_x_resource", Change: "modified"},
+					{Kind: diff.KindResource, Name: "google_z_resource", Change: "modified"},
+				},
 			},
 		},
 		{
@@ -208,6 +229,35 @@ func TestSchemaDiffCmdRun(t *testing.T) {
 			},
 			want: simpleSchemaDiff{
 				ModifiedResources: []string{"google_x_resource"},
+				Changes: []schemaChange{
+					{Kind: diff.KindResource, Name: "google_x_resource", Change: "modified"},
+				},
+			},
+		},
+		{
+			name: "field is renamed with a deprecated alias",
+			args: []string{"12345"},
+			oldResourceMap: map[string]*schema.Resource{
+				"google_x_resource": {
+					Schema: map[string]*schema.Schema{
+						"field_a": {Description: "beep", Optional: true},
+					},
+				},
+			},
+			newResourceMap: map[string]*schema.Resource{
+				"google_x_resource": {
+					Schema: map[string]*schema.Schema{
+						"field_a": {Description: "beep", Optional: true, Computed: true, Deprecated: "Use `field_b` instead.", ConflictsWith: []string{"field_b"}},
+						"field_b": {Description: "beep", Optional: true, Computed: true, ConflictsWith: []string{"field_a"}},
+					},
+				},
+			},
+			want: simpleSchemaDiff{
+				ModifiedResources:        []string{"google_x_resource"},
+				RenamedFieldReleaseNotes: []string{"deprecated `field_a` field in `google_x_resource` resource. Use `field_b` instead."},
+				Changes: []schemaChange{
+					{Kind: diff.KindResource, Name: "google_x_resource", Change: "modified"},
+				},
 			},
 		},
 		{
@@ -245,6 +295,11 @@ func TestSchemaDiffCmdRun(t *testing.T) {
 				AddedResources:    []string{"google_x_resource"},
 				ModifiedResources: []string{"google_y_resource"},
 				RemovedResources:  []string{"google_z_resource"},
+				Changes: []schemaChange{
+					{Kind: diff.KindResource, Name: "google_x_resource", Change: "added"},
+					{Kind: diff.KindResource, Name: "google_y_resource", Change: "modified"},
+					{Kind: diff.KindResource, Name: "google_z_resource", Change: "removed"},
+				},
 			},
 		},
 	}
@@ -256,8 +311,10 @@ func TestSchemaDiffCmdRun(t *testing.T) {
 
 			var buf bytes.Buffer
 			o := schemaDiffOptions{
-				computeSchemaDiff: func() diff.SchemaDiff {
-					return diff.ComputeSchemaDiff(tc.oldResourceMap, tc.newResourceMap)
+				computeProviderSchemaDiff: func() diff.ProviderSchemaDiff {
+					return diff.ProviderSchemaDiff{
+						diff.KindResource: diff.ComputeSchemaDiff(tc.oldResourceMap, tc.newResourceMap),
+					}
 				},
 				stdout: &buf,
 			}
@@ -280,3 +337,54 @@ func TestSchemaDiffCmdRun(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaDiffCmdRun_kinds(t *testing.T) {
+	oldSchema := diff.ProviderSchema{
+		diff.KindDataSource: {
+			"google_x_resource": {Schema: map[string]*schema.Schema{"field_a": {Optional: true}}},
+		},
+		diff.KindFunction: {
+			"name_from_id": {Schema: map[string]*schema.Schema{"parameter_0": {Type: schema.TypeString, Required: true}}},
+		},
+		diff.KindProvider: {
+			"google": {Schema: map[string]*schema.Schema{"project": {Type: schema.TypeString, Optional: true}}},
+		},
+	}
+	newSchema := diff.ProviderSchema{
+		diff.KindDataSource: {
+			"google_x_resource": {Schema: map[string]*schema.Schema{"field_a": {Required: true}}},
+		},
+		diff.KindEphemeralResource: {
+			"google_x_token": {Schema: map[string]*schema.Schema{"token": {Type: schema.TypeString, Computed: true}}},
+		},
+		diff.KindProvider: {
+			"google": {Schema: map[string]*schema.Schema{"project": {Type: schema.TypeString, Optional: true}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	o := schemaDiffOptions{
+		computeProviderSchemaDiff: func() diff.ProviderSchemaDiff {
+			return diff.ComputeProviderSchemaDiff(oldSchema, newSchema)
+		},
+		stdout: &buf,
+	}
+	if err := o.run(); err != nil {
+		t.Fatalf("Error running command: %s", err)
+	}
+
+	var got simpleSchemaDiff
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Unable to unmarshal simple diff (%q): %s", buf.Bytes(), err)
+	}
+	want := simpleSchemaDiff{
+		Changes: []schemaChange{
+			{Kind: diff.KindDataSource, Name: "google_x_resource", Change: "modified"},
+			{Kind: diff.KindEphemeralResource, Name: "google_x_token", Change: "added"},
+			{Kind: diff.KindFunction, Name: "name_from_id", Change: "removed"},
+		},
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("Unexpected simple diff. Want %q, got %q", want, got)
+	}
+}
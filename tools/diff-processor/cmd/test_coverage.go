@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/coverage"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/test-reader/reader"
+	"github.com/golang/glog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/spf13/cobra"
+)
+
+const testCoverageDesc = `Report which fields of the new provider's resources are set by the acceptance tests in the given services directory.
+
+Fields are tested, only tested by tests skipped in VCR, or untested. With --baseline, fails if the coverage of a resource changed between the old / new Terraform provider versions is lower than in the baseline report.`
+
+type testCoverageOptions struct {
+	rootOptions       *rootOptions
+	newResources      func() (map[string]*schema.Resource, map[string]*diff.ResourceMetadata)
+	computeSchemaDiff func() diff.SchemaDiff
+	format            string
+	baselinePath      string
+	stdout            io.Writer
+	stderr            io.Writer
+}
+
+func newTestCoverageCmd(rootOptions *rootOptions) *cobra.Command {
+	o := &testCoverageOptions{
+		rootOptions: rootOptions,
+		newResources: func() (map[string]*schema.Resource, map[string]*diff.ResourceMetadata) {
+			ps := rootOptions.providerSchemas()
+			return ps.new[diff.KindResource], ps.newMetadata
+		},
+		computeSchemaDiff: func() diff.SchemaDiff {
+			return rootOptions.providerSchemaDiff()[diff.KindResource]
+		},
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}
+	cmd := &cobra.Command{
+		Use:   "test-coverage SERVICES_DIR",
+		Short: "Report the coverage of resource fields by acceptance tests",
+		Long:  testCoverageDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.run(args)
+		},
+	}
+	cmd.Flags().StringVar(&o.format, "format", "json", "output format: json or html")
+	cmd.Flags().StringVar(&o.baselinePath, "baseline", "", "path to a JSON coverage report to compare the coverage of changed resources with")
+	return cmd
+}
+
+func (o *testCoverageOptions) run(args []string) error {
+	write, ok := testCoverageWriters[o.format]
+	if !ok {
+		return fmt.Errorf("unknown format %q, expected json or html", o.format)
+	}
+	var baseline *coverage.Report
+	if o.baselinePath != "" {
+		b, err := os.ReadFile(o.baselinePath)
+		if err != nil {
+			return err
+		}
+		baseline = &coverage.Report{}
+		if err := json.Unmarshal(b, baseline); err != nil {
+			return fmt.Errorf("error parsing baseline %s: %w", o.baselinePath, err)
+		}
+	}
+
+	allTests, errs := reader.ReadAllTests(args[0])
+	for path, err := range errs {
+		glog.Infof("error reading path: %s, err: %v", path, err)
+	}
+	resources, metadata := o.newResources()
+	report := coverage.Compute(resources, metadata, allTests)
+	if err := write(o.stdout, report); err != nil {
+		return err
+	}
+	if baseline == nil {
+		return nil
+	}
+
+	var changed []string
+	for resource := range o.computeSchemaDiff() {
+		changed = append(changed, resource)
+	}
+	sort.Strings(changed)
+	drops := coverage.Drops(baseline, report, changed)
+	for _, d := range drops {
+		fmt.Fprintf(o.stderr, "coverage of %s dropped from %.1f%% to %.1f%%\n", d.Resource, d.OldPercent, d.NewPercent)
+	}
+	if len(drops) > 0 {
+		return fmt.Errorf("coverage dropped for %d changed resources", len(drops))
+	}
+	return nil
+}
+
+var testCoverageWriters = map[string]func(io.Writer, *coverage.Report) error{
+	"json": writeTestCoverageJSON,
+	"html": writeTestCoverageHTML,
+}
+
+func writeTestCoverageJSON(w io.Writer, report *coverage.Report) error {
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		return fmt.Errorf("error encoding json: %w", err)
+	}
+	return nil
+}
+
+var testCoverageHTML = template.Must(template.New("coverage").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Test coverage</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 2px 8px; text-align: left; }
+.tested { color: #1a7f37; }
+.vcr-skipped { color: #9a6700; }
+.untested { color: #cf222e; }
+tr.highlighted { background: #ffebe9; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Test coverage: {{.Percent}}%</h1>
+<p>{{.Tested}} of {{.Total}} fields tested, {{.VcrSkipped}} only by tests skipped in VCR, {{.Untested}} untested.</p>
+<table>
+<tr><th>Service</th><th>Coverage</th><th>Fields</th><th>Tested</th><th>VCR skipped</th><th>Untested</th></tr>
+{{- range .Services}}
+<tr><td><a href="#{{.Service}}">{{.Service}}</a></td><td>{{.Percent}}%</td><td>{{.Total}}</td><td>{{.Tested}}</td><td>{{.VcrSkipped}}</td><td>{{.Untested}}</td></tr>
+{{- end}}
+</table>
+{{- range .Services}}
+<h2 id="{{.Service}}">{{.Service}}: {{.Percent}}%</h2>
+{{- range .Resources}}
+<details{{if .Highlighted}} open{{end}}>
+<summary id="{{.Resource}}">{{.Resource}}: {{.Percent}}% ({{len .Tests}} tests){{if .Highlighted}}, {{len .Highlighted}} untested required or updatable fields{{end}}</summary>
+<table>
+<tr><th>Field</th><th>Status</th><th>Required</th><th>Updatable</th></tr>
+{{- range .Fields}}
+<tr{{if and (eq .Status "untested") (or .Required .Updatable)}} class="highlighted"{{end}}><td>{{.Field}}</td><td class="{{.Status}}">{{.Status}}</td><td>{{if .Required}}yes{{end}}</td><td>{{if .Updatable}}yes{{end}}</td></tr>
+{{- end}}
+</table>
+</details>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+func writeTestCoverageHTML(w io.Writer, report *coverage.Report) error {
+	if err := testCoverageHTML.Execute(w, report); err != nil {
+		return fmt.Errorf("error writing html: %w", err)
+	}
+	return nil
+}
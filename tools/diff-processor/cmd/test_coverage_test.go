@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/coverage"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func testCoverageOptionsForTest(format, baselinePath string, stdout, stderr *bytes.Buffer) testCoverageOptions {
+	return testCoverageOptions{
+		newResources: func() (map[string]*schema.Resource, map[string]*diff.ResourceMetadata) {
+			return map[string]*schema.Resource{
+				"covered_resource": {Schema: map[string]*schema.Schema{
+					"field_one":   {Type: schema.TypeString, Optional: true},
+					"field_eight": {Type: schema.TypeString, Required: true},
+				}},
+				"vcr_skipped_resource": {Schema: map[string]*schema.Schema{
+					"field_one": {Type: schema.TypeString, Optional: true, ForceNew: true},
+				}},
+			}, map[string]*diff.ResourceMetadata{"covered_resource": {Resource: "covered_resource", Service: "service"}}
+		},
+		computeSchemaDiff: func() diff.SchemaDiff {
+			return diff.SchemaDiff{"covered_resource": diff.ResourceDiff{}}
+		},
+		format:       format,
+		baselinePath: baselinePath,
+		stdout:       stdout,
+		stderr:       stderr,
+	}
+}
+
+func TestTestCoverageCmd(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	o := testCoverageOptionsForTest("json", "", &stdout, &stderr)
+	if err := o.run([]string{"../../test-reader/reader/testdata"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var report coverage.Report
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("error parsing output %s: %s", stdout.String(), err)
+	}
+	got := map[string]coverage.Counts{}
+	for _, s := range report.Services {
+		got[s.Service] = s.Counts
+		for _, r := range s.Resources {
+			got[r.Resource] = r.Counts
+		}
+	}
+	want := map[string]coverage.Counts{
+		"service":               {Total: 2, Tested: 1, Untested: 1, Percent: 50},
+		"covered_resource":      {Total: 2, Tested: 1, Untested: 1, Percent: 50},
+		coverage.UnknownService: {Total: 1, VcrSkipped: 1, Percent: 0},
+		"vcr_skipped_resource":  {Total: 1, VcrSkipped: 1, Percent: 0},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("coverage diff(-want, +got) = %s", diff)
+	}
+	if got := report.Resource("covered_resource").Highlighted; !cmp.Equal(got, []string{"field_eight"}) {
+		t.Errorf("covered_resource highlighted fields = %v, want [field_eight]", got)
+	}
+
+	stdout.Reset()
+	o = testCoverageOptionsForTest("html", "", &stdout, &stderr)
+	if err := o.run([]string{"../../test-reader/reader/testdata"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, s := range []string{
+		"<h1>Test coverage: 33.3%</h1>",
+		`<tr class="highlighted"><td>field_eight</td><td class="untested">untested</td>`,
+		`<td>field_one</td><td class="vcr-skipped">vcr-skipped</td>`,
+	} {
+		if !strings.Contains(stdout.String(), s) {
+			t.Errorf("html output doesn't contain %q: %s", s, stdout.String())
+		}
+	}
+}
+
+func TestTestCoverageCmd_baseline(t *testing.T) {
+	cases := map[string]struct {
+		percent float64
+		wantErr bool
+	}{
+		"same coverage":      {percent: 50},
+		"increased coverage": {percent: 40},
+		"dropped coverage":   {percent: 75, wantErr: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			baseline := &coverage.Report{Services: []*coverage.Service{{
+				Service: "service",
+				Resources: []*coverage.Resource{
+					{Resource: "covered_resource", Counts: coverage.Counts{Percent: tc.percent}},
+					// Unchanged resources can drop, as tests of other resources changed.
+					{Resource: "vcr_skipped_resource", Counts: coverage.Counts{Percent: 100}},
+				},
+			}}}
+			b, err := json.Marshal(baseline)
+			if err != nil {
+				t.Fatal(err)
+			}
+			baselinePath := filepath.Join(t.TempDir(), "baseline.json")
+			if err := os.WriteFile(baselinePath, b, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var stdout, stderr bytes.Buffer
+			o := testCoverageOptionsForTest("json", baselinePath, &stdout, &stderr)
+			err = o.run([]string{"../../test-reader/reader/testdata"})
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("run() error = %v, want error: %t", err, tc.wantErr)
+			}
+			if tc.wantErr && !strings.Contains(stderr.String(), "coverage of covered_resource dropped from 75.0% to 50.0%") {
+				t.Errorf("unexpected stderr: %s", stderr.String())
+			}
+			if stdout.Len() == 0 {
+				t.Error("report wasn't written")
+			}
+		})
+	}
+}
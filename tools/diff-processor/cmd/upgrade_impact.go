@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/upgrade_impact"
+	"github.com/spf13/cobra"
+)
+
+const upgradeImpactDesc = `Report the resource instances in Terraform state files that are affected by the changes between the old / new Terraform provider versions.
+
+Accepts terraform.tfstate files and the output of terraform show -json.`
+
+type upgradeImpactOptions struct {
+	rootOptions       *rootOptions
+	computeSchemaDiff func() diff.SchemaDiff
+	stdout            io.Writer
+}
+
+func newUpgradeImpactCmd(rootOptions *rootOptions) *cobra.Command {
+	o := &upgradeImpactOptions{
+		rootOptions: rootOptions,
+		computeSchemaDiff: func() diff.SchemaDiff {
+			return rootOptions.providerSchemaDiff()[diff.KindResource]
+		},
+		stdout: os.Stdout,
+	}
+	return &cobra.Command{
+		Use:   "upgrade-impact STATE_FILE...",
+		Short: "Report resource instances affected by a provider upgrade",
+		Long:  upgradeImpactDesc,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.run(args)
+		},
+	}
+}
+
+// upgradeImpactFile holds the impacts on the instances of a state file.
+type upgradeImpactFile struct {
+	File      string
+	Instances int
+	Impacts   []upgrade_impact.Impact
+}
+
+func (o *upgradeImpactOptions) run(args []string) error {
+	schemaDiff := o.computeSchemaDiff()
+	results := []upgradeImpactFile{}
+	for _, filename := range args {
+		instances, err := upgrade_impact.LoadInstances(filename)
+		if err != nil {
+			return err
+		}
+		impacts := upgrade_impact.ComputeImpacts(schemaDiff, instances)
+		if impacts == nil {
+			impacts = []upgrade_impact.Impact{}
+		}
+		results = append(results, upgradeImpactFile{
+			File:      filename,
+			Instances: len(instances),
+			Impacts:   impacts,
+		})
+	}
+	if err := json.NewEncoder(o.stdout).Encode(results); err != nil {
+		return fmt.Errorf("error encoding json: %w", err)
+	}
+	return nil
+}
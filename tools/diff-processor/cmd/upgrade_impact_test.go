@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/upgrade_impact"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestUpgradeImpactCmd(t *testing.T) {
+	dir := t.TempDir()
+	state := filepath.Join(dir, "terraform.tfstate")
+	if err := os.WriteFile(state, []byte(`{
+  "version": 4,
+  "resources": [
+    {"mode": "managed", "type": "google_x", "name": "a", "instances": [{"attributes": {"name": "a", "alias": "b"}}]},
+    {"mode": "managed", "type": "google_x", "name": "c", "instances": [{"attributes": {"name": "c", "alias": ""}}]}
+  ]
+}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	emptyState := filepath.Join(dir, "empty.json")
+	if err := os.WriteFile(emptyState, []byte(`{"format_version": "1.0"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	o := upgradeImpactOptions{
+		computeSchemaDiff: func() diff.SchemaDiff {
+			return diff.ComputeSchemaDiff(
+				map[string]*schema.Resource{"google_x": {Schema: map[string]*schema.Schema{
+					"name":  {Type: schema.TypeString, Required: true},
+					"alias": {Type: schema.TypeString, Optional: true},
+				}}},
+				map[string]*schema.Resource{"google_x": {Schema: map[string]*schema.Schema{
+					"name": {Type: schema.TypeString, Required: true},
+				}}},
+			)
+		},
+		stdout: &buf,
+	}
+	if err := o.run([]string{state, emptyState}); err != nil {
+		t.Fatalf("Error running command: %s", err)
+	}
+
+	var got []upgradeImpactFile
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to unmarshall output: %s", err)
+	}
+	want := []upgradeImpactFile{
+		{
+			File:      state,
+			Instances: 2,
+			Impacts: []upgrade_impact.Impact{{
+				Address:  "google_x.a",
+				Resource: "google_x",
+				Field:    "alias",
+				Kind:     upgrade_impact.KindRemovedField,
+				Value:    "b",
+				Message:  "`google_x.a` sets `alias`, which was either removed or renamed",
+			}},
+		},
+		{
+			File:    emptyState,
+			Impacts: []upgrade_impact.Impact{},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected output (-want, +got): %s", diff)
+	}
+
+	if err := o.run([]string{filepath.Join(dir, "missing.tfstate")}); err == nil {
+		t.Errorf("Expected an error for a missing state file")
+	}
+}
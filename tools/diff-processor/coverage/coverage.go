@@ -0,0 +1,244 @@
+// Package coverage reports which resource fields the provider's acceptance
+// tests set.
+package coverage
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/test-reader/reader"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Status is whether the tests of a field run.
+type Status string
+
+const (
+	// StatusTested fields are set by a test that runs in VCR.
+	StatusTested Status = "tested"
+	// StatusVcrSkipped fields are only set by tests skipped in VCR, which only
+	// run nightly.
+	StatusVcrSkipped Status = "vcr-skipped"
+	// StatusUntested fields aren't set by any test.
+	StatusUntested Status = "untested"
+)
+
+// UnknownService is the service of resources without metadata.
+const UnknownService = "unknown"
+
+type Field struct {
+	Field     string
+	Status    Status
+	Required  bool
+	Updatable bool
+}
+
+// Counts of the fields of a resource or service, in total and by status.
+// Percent is the percentage of fields tested in VCR.
+type Counts struct {
+	Total      int
+	Tested     int
+	VcrSkipped int
+	Untested   int
+	Percent    float64
+}
+
+type Resource struct {
+	Resource string
+	Service  string
+	Counts
+	// Names of the tests using the resource.
+	Tests  []string
+	Fields []Field
+	// Untested fields that are required or updatable, which are the most
+	// likely to break unnoticed.
+	Highlighted []string `json:",omitempty"`
+}
+
+type Service struct {
+	Service string
+	Counts
+	Resources []*Resource
+}
+
+type Report struct {
+	Counts
+	Services []*Service
+}
+
+// Resource returns the coverage of a resource, or nil if it isn't in r.
+func (r *Report) Resource(name string) *Resource {
+	for _, s := range r.Services {
+		for _, resource := range s.Resources {
+			if resource.Resource == name {
+				return resource
+			}
+		}
+	}
+	return nil
+}
+
+// Compute returns the coverage of the fields of resources by tests. Services
+// are read from the resources' metadata. Like for missing test detection,
+// output-only fields, parent fields and project fields aren't counted.
+func Compute(resources map[string]*schema.Resource, metadata map[string]*diff.ResourceMetadata, tests []*reader.Test) *Report {
+	fieldTests := make(map[string]map[string]Status) // resource to field to best status
+	resourceTests := make(map[string][]string)
+	for _, test := range tests {
+		status := StatusTested
+		if test.VcrSkipped {
+			status = StatusVcrSkipped
+		}
+		used := make(map[string]bool)
+		for _, step := range test.Steps {
+			for resourceName, configs := range step {
+				if _, ok := resources[resourceName]; !ok {
+					continue
+				}
+				used[resourceName] = true
+				if fieldTests[resourceName] == nil {
+					fieldTests[resourceName] = make(map[string]Status)
+				}
+				for _, config := range configs {
+					for field := range config {
+						if fieldTests[resourceName][field] != StatusTested {
+							fieldTests[resourceName][field] = status
+						}
+					}
+				}
+			}
+		}
+		for resourceName := range used {
+			resourceTests[resourceName] = append(resourceTests[resourceName], test.Name)
+		}
+	}
+
+	services := make(map[string]*Service)
+	for name, r := range resources {
+		resource := &Resource{Resource: name, Service: UnknownService, Tests: resourceTests[name]}
+		if m, ok := metadata[name]; ok && m.Service != "" {
+			resource.Service = m.Service
+		}
+		sort.Strings(resource.Tests)
+		resource.Fields = fields(name, r.Schema, "", false)
+		for i, f := range resource.Fields {
+			if status, ok := fieldTests[name][f.Field]; ok {
+				resource.Fields[i].Status = status
+			} else {
+				resource.Fields[i].Status = StatusUntested
+				if f.Required || f.Updatable {
+					resource.Highlighted = append(resource.Highlighted, f.Field)
+				}
+			}
+			resource.Counts.add(resource.Fields[i].Status)
+		}
+		resource.Counts.percent()
+
+		s, ok := services[resource.Service]
+		if !ok {
+			s = &Service{Service: resource.Service}
+			services[resource.Service] = s
+		}
+		s.Resources = append(s.Resources, resource)
+	}
+
+	report := &Report{}
+	for _, s := range services {
+		sort.Slice(s.Resources, func(i, j int) bool { return s.Resources[i].Resource < s.Resources[j].Resource })
+		for _, r := range s.Resources {
+			s.Counts.merge(r.Counts)
+		}
+		s.Counts.percent()
+		report.Counts.merge(s.Counts)
+		report.Services = append(report.Services, s)
+	}
+	sort.Slice(report.Services, func(i, j int) bool { return report.Services[i].Service < report.Services[j].Service })
+	report.Counts.percent()
+	return report
+}
+
+// fields returns the sorted fields of a resource that tests can set.
+func fields(resource string, s map[string]*schema.Schema, parent string, forceNew bool) []Field {
+	var result []Field
+	for name, field := range s {
+		key := parent + name
+		if key == "project" {
+			// The project is usually set by the provider.
+			continue
+		}
+		if strings.Contains(resource, "iam") && key == "condition" {
+			// Some iam resources don't support conditions.
+			continue
+		}
+		if field.Computed && !field.Optional {
+			continue
+		}
+		if r, ok := field.Elem.(*schema.Resource); ok {
+			result = append(result, fields(resource, r.Schema, key+".", forceNew || field.ForceNew)...)
+			continue
+		}
+		result = append(result, Field{
+			Field:     key,
+			Required:  field.Required,
+			Updatable: !forceNew && !field.ForceNew,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Field < result[j].Field })
+	return result
+}
+
+func (c *Counts) add(status Status) {
+	c.Total++
+	switch status {
+	case StatusTested:
+		c.Tested++
+	case StatusVcrSkipped:
+		c.VcrSkipped++
+	default:
+		c.Untested++
+	}
+}
+
+func (c *Counts) merge(o Counts) {
+	c.Total += o.Total
+	c.Tested += o.Tested
+	c.VcrSkipped += o.VcrSkipped
+	c.Untested += o.Untested
+}
+
+// percent sets the percentage of tested fields, rounded to one decimal. It's
+// 100 when there are no fields to test.
+func (c *Counts) percent() {
+	if c.Total == 0 {
+		c.Percent = 100
+		return
+	}
+	c.Percent = math.Round(float64(c.Tested)/float64(c.Total)*1000) / 10
+}
+
+// Drop is a decrease of the coverage of a resource.
+type Drop struct {
+	Resource   string
+	OldPercent float64
+	NewPercent float64
+}
+
+// Drops returns the resources whose coverage in report is lower than in
+// baseline, among the given ones. Resources missing from either report are
+// skipped.
+func Drops(baseline, report *Report, resources []string) []Drop {
+	var drops []Drop
+	for _, name := range resources {
+		oldResource, newResource := baseline.Resource(name), report.Resource(name)
+		if oldResource == nil || newResource == nil {
+			continue
+		}
+		if newResource.Percent < oldResource.Percent {
+			drops = append(drops, Drop{Resource: name, OldPercent: oldResource.Percent, NewPercent: newResource.Percent})
+		}
+	}
+	sort.Slice(drops, func(i, j int) bool { return drops[i].Resource < drops[j].Resource })
+	return drops
+}
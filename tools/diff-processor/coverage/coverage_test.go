@@ -0,0 +1,123 @@
+package coverage
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/test-reader/reader"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCompute(t *testing.T) {
+	resources := map[string]*schema.Resource{
+		"google_x": {
+			Schema: map[string]*schema.Schema{
+				"name":    {Type: schema.TypeString, Required: true, ForceNew: true},
+				"labels":  {Type: schema.TypeMap, Optional: true},
+				"project": {Type: schema.TypeString, Optional: true},
+				"id_out":  {Type: schema.TypeString, Computed: true},
+				"config": {
+					Type:     schema.TypeList,
+					Optional: true,
+					ForceNew: true,
+					Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+						"size": {Type: schema.TypeInt, Optional: true},
+					}},
+				},
+			},
+		},
+		"google_y": {
+			Schema: map[string]*schema.Schema{
+				"name": {Type: schema.TypeString, Required: true},
+			},
+		},
+		"google_z": {
+			Schema: map[string]*schema.Schema{
+				"id_out": {Type: schema.TypeString, Computed: true},
+			},
+		},
+	}
+	metadata := map[string]*diff.ResourceMetadata{
+		"google_x": {Resource: "google_x", Service: "compute"},
+		"google_y": {Resource: "google_y", Service: "compute"},
+	}
+	tests := []*reader.Test{
+		{
+			Name: "TestAccX",
+			Steps: []reader.Step{
+				{"google_x": {"primary": {"name": `"a"`}}},
+				{"google_x": {"primary": {"name": `"a"`, "config.size": "1"}}},
+			},
+		},
+		{
+			Name:       "TestAccXNightly",
+			VcrSkipped: true,
+			Steps: []reader.Step{
+				{"google_x": {"primary": {"name": `"a"`, "labels": "{}"}}},
+			},
+		},
+	}
+
+	got := Compute(resources, metadata, tests)
+	want := &Report{
+		Counts: Counts{Total: 4, Tested: 2, VcrSkipped: 1, Untested: 1, Percent: 50},
+		Services: []*Service{
+			{
+				Service: "compute",
+				Counts:  Counts{Total: 4, Tested: 2, VcrSkipped: 1, Untested: 1, Percent: 50},
+				Resources: []*Resource{
+					{
+						Resource: "google_x",
+						Service:  "compute",
+						Counts:   Counts{Total: 3, Tested: 2, VcrSkipped: 1, Percent: 66.7},
+						Tests:    []string{"TestAccX", "TestAccXNightly"},
+						Fields: []Field{
+							{Field: "config.size", Status: StatusTested},
+							{Field: "labels", Status: StatusVcrSkipped, Updatable: true},
+							{Field: "name", Status: StatusTested, Required: true},
+						},
+					},
+					{
+						Resource:    "google_y",
+						Service:     "compute",
+						Counts:      Counts{Total: 1, Untested: 1, Percent: 0},
+						Fields:      []Field{{Field: "name", Status: StatusUntested, Required: true, Updatable: true}},
+						Highlighted: []string{"name"},
+					},
+				},
+			},
+			{
+				Service: UnknownService,
+				Counts:  Counts{Percent: 100},
+				Resources: []*Resource{
+					{Resource: "google_z", Service: UnknownService, Counts: Counts{Percent: 100}},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Compute() diff(-want, +got) = %s", diff)
+	}
+}
+
+func TestDrops(t *testing.T) {
+	report := func(percents map[string]float64) *Report {
+		s := &Service{Service: "compute"}
+		for name, percent := range percents {
+			s.Resources = append(s.Resources, &Resource{Resource: name, Counts: Counts{Percent: percent}})
+		}
+		return &Report{Services: []*Service{s}}
+	}
+	baseline := report(map[string]float64{"google_a": 50, "google_b": 80, "google_c": 100, "google_d": 60})
+	current := report(map[string]float64{"google_a": 40, "google_b": 90, "google_c": 75, "google_e": 0})
+
+	got := Drops(baseline, current, []string{"google_c", "google_a", "google_b", "google_e"})
+	want := []Drop{
+		{Resource: "google_a", OldPercent: 50, NewPercent: 40},
+		{Resource: "google_c", OldPercent: 100, NewPercent: 75},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Drops() diff(-want, +got) = %s", diff)
+	}
+}
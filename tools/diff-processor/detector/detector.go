@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -17,8 +18,11 @@ import (
 
 type MissingTestInfo struct {
 	UntestedFields []string
-	SuggestedTest  string
-	Tests          []string
+	// Mutable fields that tests set, but never to two different values in
+	// consecutive steps, so their update path isn't exercised.
+	NotUpdatedFields []string `json:",omitempty"`
+	SuggestedTest    string
+	Tests            []string
 }
 
 type FieldSet map[string]struct{}
@@ -33,8 +37,14 @@ type Field struct {
 	Added bool
 	// Changed is true when the field type has changed between oldProvider and newProvider.
 	Changed bool
+	// Mutable is true when the field can be updated in place, so tests need to
+	// change its value.
+	Mutable bool
 	// Tested is true when a test has been found that includes the field.
 	Tested bool
+	// Updated is true when a test has been found that changes the field's
+	// value between consecutive steps, including by unsetting it.
+	Updated bool
 }
 
 // MissingDocDetails denotes the doc file path and the fields that are not shown up in the corresponding doc.
@@ -44,6 +54,19 @@ type MissingDocDetails struct {
 	Fields   []string
 }
 
+// DocMismatch is a difference between what a resource document says about a
+// field and the field's schema.
+type DocMismatch struct {
+	Field   string
+	Message string
+}
+
+type DocMismatchDetails struct {
+	Name       string
+	FilePath   string
+	Mismatches []DocMismatch
+}
+
 // Detect missing tests for the given resource changes map in the given slice of tests.
 // Return a map of resource names to missing test info about that resource.
 func DetectMissingTests(schemaDiff diff.SchemaDiff, allTests []*reader.Test) (map[string]*MissingTestInfo, error) {
@@ -78,10 +101,11 @@ func getChangedFieldsFromSchemaDiff(schemaDiff diff.SchemaDiff) map[string]Resou
 				// Skip parent fields.
 				continue
 			}
+			mutable := isMutable(resourceDiff, field)
 			if fieldDiff.Old == nil {
-				resourceChanges[field] = &Field{Added: true}
+				resourceChanges[field] = &Field{Added: true, Mutable: mutable}
 			} else {
-				resourceChanges[field] = &Field{Changed: true}
+				resourceChanges[field] = &Field{Changed: true, Mutable: mutable}
 			}
 		}
 		if len(resourceChanges) > 0 {
@@ -91,18 +115,38 @@ func getChangedFieldsFromSchemaDiff(schemaDiff diff.SchemaDiff) map[string]Resou
 	return changedFields
 }
 
+// isMutable returns whether a field of the new resource can be updated, which
+// it can't when it or one of its parent fields forces replacement.
+func isMutable(resourceDiff diff.ResourceDiff, field string) bool {
+	for path := field; ; {
+		if fieldDiff, ok := resourceDiff.Fields[path]; ok && fieldDiff.New != nil && fieldDiff.New.ForceNew {
+			return false
+		}
+		i := strings.LastIndex(path, ".")
+		if i < 0 {
+			return true
+		}
+		path = path[:i]
+	}
+}
+
 func getMissingTestsForChanges(changedFields map[string]ResourceChanges, allTests []*reader.Test) (map[string]*MissingTestInfo, error) {
 	resourceNamesToTests := make(map[string][]string)
 	for _, test := range allTests {
-		for _, step := range test.Steps {
+		for i, step := range test.Steps {
 			for resourceName, resourceMap := range step {
 				if changedResourceFields, ok := changedFields[resourceName]; ok {
 					// This resource type has changed fields.
 					resourceNamesToTests[resourceName] = append(resourceNamesToTests[resourceName], test.Name)
-					for _, resourceConfig := range resourceMap {
+					for name, resourceConfig := range resourceMap {
 						if err := markCoverage(changedResourceFields, resourceConfig); err != nil {
 							return nil, err
 						}
+						if i > 0 {
+							if previousConfig, ok := test.Steps[i-1][resourceName][name]; ok {
+								markUpdates(changedResourceFields, previousConfig, resourceConfig)
+							}
+						}
 					}
 				}
 			}
@@ -110,13 +154,13 @@ func getMissingTestsForChanges(changedFields map[string]ResourceChanges, allTest
 	}
 	missingTests := make(map[string]*MissingTestInfo)
 	for resourceName, fieldCoverage := range changedFields {
-		untested := untestedFields(fieldCoverage)
-		sort.Strings(untested)
-		if len(untested) > 0 {
+		untested, notUpdated := untestedFields(fieldCoverage)
+		if len(untested) > 0 || len(notUpdated) > 0 {
 			missingTests[resourceName] = &MissingTestInfo{
-				UntestedFields: untested,
-				SuggestedTest:  suggestedTest(resourceName, untested),
-				Tests:          resourceNamesToTests[resourceName],
+				UntestedFields:   untested,
+				NotUpdatedFields: notUpdated,
+				SuggestedTest:    suggestedTest(resourceName, fieldCoverage),
+				Tests:            resourceNamesToTests[resourceName],
 			}
 		}
 	}
@@ -132,21 +176,63 @@ func markCoverage(fieldCoverage ResourceChanges, config reader.Resource) error {
 	return nil
 }
 
-func untestedFields(fieldCoverage ResourceChanges) []string {
-	fields := make([]string, 0)
+// markUpdates marks the fields whose values differ between the configs of the
+// same resource in consecutive steps. A field set in only one of them counts
+// as updated, as setting or unsetting it also updates the resource.
+func markUpdates(fieldCoverage ResourceChanges, previous, current reader.Resource) {
+	for fieldName, field := range fieldCoverage {
+		previousValue, inPrevious := previous[fieldName]
+		currentValue, inCurrent := current[fieldName]
+		if inPrevious != inCurrent || inPrevious && !reflect.DeepEqual(previousValue, currentValue) {
+			field.Updated = true
+		}
+	}
+}
+
+// untestedFields returns the sorted fields that no test sets, and the mutable
+// fields that tests set but never update.
+func untestedFields(fieldCoverage ResourceChanges) ([]string, []string) {
+	untested := make([]string, 0)
+	var notUpdated []string
 	for key, field := range fieldCoverage {
 		if !field.Tested {
+			untested = append(untested, key)
+		} else if field.Mutable && !field.Updated {
+			notUpdated = append(notUpdated, key)
+		}
+	}
+	sort.Strings(untested)
+	sort.Strings(notUpdated)
+	return untested, notUpdated
+}
+
+// suggestedTest returns the config of a test setting the untested fields. When
+// some of the fields are mutable, it is followed by the config of an update
+// step changing them.
+func suggestedTest(resourceName string, fieldCoverage ResourceChanges) string {
+	var fields, updates []string
+	for key, field := range fieldCoverage {
+		needsUpdate := field.Mutable && !field.Updated
+		if !field.Tested || needsUpdate {
 			fields = append(fields, key)
 		}
+		if needsUpdate {
+			updates = append(updates, key)
+		}
 	}
-	return fields
+	sort.Strings(fields)
+	if len(updates) == 0 {
+		return suggestedConfig(resourceName, fields, nil)
+	}
+	return "# Step 1\n" + suggestedConfig(resourceName, fields, nil) +
+		"\n# Step 2: update\n" + suggestedConfig(resourceName, fields, listToMap(updates))
 }
 
-func suggestedTest(resourceName string, untested []string) string {
+func suggestedConfig(resourceName string, fields []string, updates map[string]bool) string {
 	f := hclwrite.NewEmptyFile()
 	rootBody := f.Body()
 	resourceBlock := rootBody.AppendNewBlock("resource", []string{resourceName, "primary"})
-	for _, field := range untested {
+	for _, field := range fields {
 		body := resourceBlock.Body()
 		path := strings.Split(field, ".")
 		for i, step := range path {
@@ -156,12 +242,20 @@ func suggestedTest(resourceName string, untested []string) string {
 					block = body.AppendNewBlock(step, nil)
 				}
 				body = block.Body()
-			} else {
+			} else if updates == nil {
 				body.SetAttributeValue(step, cty.StringVal("VALUE"))
+			} else if updates[field] {
+				body.SetAttributeValue(step, cty.StringVal("UPDATED_VALUE"))
+			} else {
+				body.SetAttributeValue(step, cty.StringVal("SAME_VALUE"))
 			}
 		}
 	}
-	return strings.ReplaceAll(string(f.Bytes()), `"VALUE"`, "# value needed")
+	return strings.NewReplacer(
+		`"VALUE"`, "# value needed",
+		`"UPDATED_VALUE"`, "# updated value needed",
+		`"SAME_VALUE"`, "# same value as step 1",
+	).Replace(string(f.Bytes()))
 }
 
 // DetectMissingDocs detect new fields that are missing docs given the schema diffs.
@@ -248,6 +342,138 @@ func DetectMissingDocsForDatasource(schemaDiff diff.SchemaDiff, repoPath string)
 	return ret, nil
 }
 
+// DetectDocMismatches compares the documents of all the given resources with their schemas.
+// Return a map of resource names to the fields whose documented marker, default value,
+// nesting or possible values don't match the schema.
+// Resources without a document are skipped; DetectMissingDocs reports them.
+func DetectDocMismatches(resources map[string]*schema.Resource, metadata map[string]*diff.ResourceMetadata, repoPath string) (map[string]DocMismatchDetails, error) {
+	ret := make(map[string]DocMismatchDetails)
+	for resource, r := range resources {
+		docFilePath, err := resourceToDocFile(resource, repoPath)
+		if err != nil {
+			continue
+		}
+		content, err := os.ReadFile(docFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read resource doc %s: %w", docFilePath, err)
+		}
+		parser := documentparser.NewParser()
+		if err := parser.Parse(content); err != nil {
+			return nil, fmt.Errorf("failed to parse document %s: %w", docFilePath, err)
+		}
+		fields := make(map[string]*schema.Schema)
+		flattenSchemaFields(fields, "", r.Schema)
+		docFields := parser.Fields()
+		// Arguments are checked against the arguments section, which fully
+		// documents them, rather than against the attributes section, which
+		// may list them again.
+		arguments := make(map[string]int)
+		attributes := make(map[string]int)
+		for _, docField := range docFields {
+			if docField.Argument {
+				arguments[docField.Path]++
+			} else {
+				attributes[docField.Path]++
+			}
+		}
+		var mismatches []DocMismatch
+		for _, docField := range docFields {
+			field, ok := fields[docField.Path]
+			if !ok || (!docField.Argument && arguments[docField.Path] > 0) {
+				continue
+			}
+			// A field documented more than once in a section is usually a nested
+			// field the parser couldn't attach to its block, so what it says is
+			// ambiguous.
+			if (docField.Argument && arguments[docField.Path] > 1) || (!docField.Argument && attributes[docField.Path] > 1) {
+				continue
+			}
+			for _, message := range docFieldMismatches(docField, field, metadata[resource].EnumValues(docField.Path)) {
+				mismatches = append(mismatches, DocMismatch{Field: docField.Path, Message: message})
+			}
+		}
+		if len(mismatches) > 0 {
+			ret[resource] = DocMismatchDetails{
+				Name:       resource,
+				FilePath:   strings.ReplaceAll(docFilePath, repoPath, ""),
+				Mismatches: mismatches,
+			}
+		}
+	}
+	return ret, nil
+}
+
+func docFieldMismatches(docField documentparser.Field, field *schema.Schema, enumValues []string) []string {
+	var messages []string
+	if actual := schemaMarker(field); docField.Marker != "" && docField.Marker != actual {
+		messages = append(messages, fmt.Sprintf("documented as %s but %s", docField.Marker, actual))
+	}
+	if docField.Default != "" && field.Default != nil {
+		if actual := fmt.Sprint(field.Default); strings.Trim(docField.Default, `"`) != actual {
+			messages = append(messages, fmt.Sprintf("documented default is %q but the default is %q", docField.Default, actual))
+		}
+	}
+	if block, ok := field.Elem.(*schema.Resource); ok && !docField.Nested && len(block.Schema) > 0 {
+		messages = append(messages, "documented without nested fields but is a block")
+	}
+	if _, ok := field.Elem.(*schema.Resource); docField.Nested && !ok {
+		if elem, ok := field.Elem.(*schema.Schema); ok && (field.Type == schema.TypeList || field.Type == schema.TypeSet) {
+			messages = append(messages, fmt.Sprintf("documented with nested fields but is a %s of %s", typeName(field.Type), typeName(elem.Type)))
+		} else {
+			messages = append(messages, "documented with nested fields but isn't a block")
+		}
+	}
+	if len(docField.PossibleValues) > 0 && len(enumValues) > 0 {
+		documented := nonEmptySorted(docField.PossibleValues)
+		actual := nonEmptySorted(enumValues)
+		if !reflect.DeepEqual(documented, actual) {
+			messages = append(messages, fmt.Sprintf("documented possible values are %s but the possible values are %s", strings.Join(documented, ", "), strings.Join(actual, ", ")))
+		}
+	}
+	return messages
+}
+
+// schemaMarker returns the marker the document should use for the field.
+func schemaMarker(field *schema.Schema) string {
+	switch {
+	case field.Required:
+		return "Required"
+	case field.Optional:
+		return "Optional"
+	default:
+		return "Output"
+	}
+}
+
+// typeName returns the name of a schema type, e.g. list for schema.TypeList.
+func typeName(t schema.ValueType) string {
+	return strings.ToLower(strings.TrimPrefix(t.String(), "Type"))
+}
+
+func flattenSchemaFields(fields map[string]*schema.Schema, parent string, s map[string]*schema.Schema) {
+	for name, field := range s {
+		path := name
+		if parent != "" {
+			path = parent + "." + name
+		}
+		fields[path] = field
+		if r, ok := field.Elem.(*schema.Resource); ok {
+			flattenSchemaFields(fields, path, r.Schema)
+		}
+	}
+}
+
+func nonEmptySorted(values []string) []string {
+	var ret []string
+	for _, v := range values {
+		if v != "" {
+			ret = append(ret, v)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
 func isNewField(fieldDiff diff.FieldDiff) bool {
 	return fieldDiff.Old == nil && fieldDiff.New != nil
 }
@@ -23,7 +23,7 @@ func TestGetChangedFieldsFromSchemaDiff(t *testing.T) {
 				"covered_resource": diff.ResourceDiff{
 					Fields: map[string]diff.FieldDiff{
 						"field_one": {
-							New: &schema.Schema{},
+							New: &schema.Schema{ForceNew: true},
 						},
 						"field_two.field_three": {
 							New: &schema.Schema{},
@@ -56,8 +56,29 @@ func TestGetChangedFieldsFromSchemaDiff(t *testing.T) {
 			changedFields: map[string]ResourceChanges{
 				"covered_resource": {
 					"field_one":                       &Field{Added: true},
-					"field_two.field_three":           &Field{Changed: true},
-					"field_four.field_five.field_six": &Field{Added: true},
+					"field_two.field_three":           &Field{Changed: true, Mutable: true},
+					"field_four.field_five.field_six": &Field{Added: true, Mutable: true},
+				},
+			},
+		},
+		{
+			name: "force-new-parent",
+			schemaDiff: diff.SchemaDiff{
+				"covered_resource": diff.ResourceDiff{
+					Fields: map[string]diff.FieldDiff{
+						"field_four": {
+							Old: &schema.Schema{Elem: &schema.Resource{}},
+							New: &schema.Schema{Elem: &schema.Resource{}, ForceNew: true},
+						},
+						"field_four.field_five": {
+							New: &schema.Schema{},
+						},
+					},
+				},
+			},
+			changedFields: map[string]ResourceChanges{
+				"covered_resource": {
+					"field_four.field_five": &Field{Added: true},
 				},
 			},
 		},
@@ -109,6 +130,47 @@ func TestGetMissingTestsForChanges(t *testing.T) {
   }
   field_one = # value needed
 }
+`,
+				},
+			},
+		},
+		{
+			name: "updated-resource",
+			changedFields: map[string]ResourceChanges{
+				"covered_resource": {
+					"field_one":                       &Field{Added: true, Mutable: true},
+					"field_two.field_three":           &Field{Changed: true, Mutable: true},
+					"field_four.field_five.field_six": &Field{Added: true, Mutable: true},
+				},
+			},
+		},
+		{
+			name: "not-updated-resource",
+			changedFields: map[string]ResourceChanges{
+				"uncovered_resource": {
+					"field_one":             &Field{Added: true},
+					"field_two.field_three": &Field{Added: true, Mutable: true},
+				},
+			},
+			expectedMissingTests: map[string]MissingTestInfo{
+				"uncovered_resource": {
+					UntestedFields:   []string{"field_one"},
+					NotUpdatedFields: []string{"field_two.field_three"},
+					SuggestedTest: `# Step 1
+resource "uncovered_resource" "primary" {
+  field_one = # value needed
+  field_two {
+    field_three = # value needed
+  }
+}
+
+# Step 2: update
+resource "uncovered_resource" "primary" {
+  field_one = # same value as step 1
+  field_two {
+    field_three = # updated value needed
+  }
+}
 `,
 				},
 			},
@@ -192,6 +254,11 @@ func TestGetMissingTestsForChanges(t *testing.T) {
 							"did not find expected untested fields in %s, found %v, expected %v",
 							test.name, missingTest.UntestedFields, expectedMissingTest.UntestedFields)
 					}
+					if !reflect.DeepEqual(missingTest.NotUpdatedFields, expectedMissingTest.NotUpdatedFields) {
+						t.Errorf(
+							"did not find expected not updated fields in %s, found %v, expected %v",
+							test.name, missingTest.NotUpdatedFields, expectedMissingTest.NotUpdatedFields)
+					}
 					if missingTest.SuggestedTest != expectedMissingTest.SuggestedTest {
 						t.Errorf("did not find expected suggested test in %s, found %s, expected %s",
 							test.name, missingTest.SuggestedTest, expectedMissingTest.SuggestedTest)
@@ -446,3 +513,121 @@ func TestDetectMissingDocsForDatasource(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectDocMismatches(t *testing.T) {
+	// The doc file points to tools/diff-processor/testdata/website/docs/r/b_resource.html.markdown.
+	for _, test := range []struct {
+		name      string
+		resources map[string]*schema.Resource
+		metadata  map[string]*diff.ResourceMetadata
+		want      map[string]DocMismatchDetails
+	}{
+		{
+			name: "doc matches schema",
+			resources: map[string]*schema.Resource{
+				"b_resource": {
+					Schema: map[string]*schema.Schema{
+						"name": {Type: schema.TypeString, Optional: true},
+						"tier": {Type: schema.TypeString, Optional: true, Default: "BASIC"},
+						"size": {Type: schema.TypeInt, Optional: true, Default: 10},
+						"labels": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"key": {Type: schema.TypeString, Required: true},
+							},
+						}},
+						"config": {Type: schema.TypeList, Required: true, Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"enabled": {Type: schema.TypeBool, Optional: true},
+							},
+						}},
+						"tags": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"value": {Type: schema.TypeString, Optional: true},
+							},
+						}},
+						"network":     {Type: schema.TypeString, Optional: true},
+						"create_time": {Type: schema.TypeString, Computed: true},
+						"state":       {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			metadata: map[string]*diff.ResourceMetadata{
+				"b_resource": {
+					Fields: []diff.FieldMetadata{
+						{Field: "tier", EnumValues: []string{"STANDARD", "BASIC", ""}},
+					},
+				},
+			},
+			want: map[string]DocMismatchDetails{},
+		},
+		{
+			name: "doc doesn't match schema",
+			resources: map[string]*schema.Resource{
+				"b_resource": {
+					Schema: map[string]*schema.Schema{
+						"name": {Type: schema.TypeString, Required: true},
+						"tier": {Type: schema.TypeString, Optional: true, Default: "STANDARD"},
+						"size": {Type: schema.TypeInt, Optional: true, Default: 20},
+						"labels": {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{
+							Type: schema.TypeString,
+						}},
+						"config": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"enabled": {Type: schema.TypeBool, Computed: true},
+							},
+						}},
+						"tags": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{
+							Type: schema.TypeString,
+						}},
+						"network": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"name": {Type: schema.TypeString, Optional: true},
+							},
+						}},
+						"create_time": {Type: schema.TypeString, Optional: true},
+					},
+				},
+				"no_doc_resource": {
+					Schema: map[string]*schema.Schema{
+						"name": {Type: schema.TypeString, Required: true},
+					},
+				},
+			},
+			metadata: map[string]*diff.ResourceMetadata{
+				"b_resource": {
+					Fields: []diff.FieldMetadata{
+						{Field: "tier", EnumValues: []string{"BASIC", "STANDARD", "PREMIUM"}},
+					},
+				},
+			},
+			want: map[string]DocMismatchDetails{
+				"b_resource": {
+					Name:     "b_resource",
+					FilePath: "/website/docs/r/b_resource.html.markdown",
+					Mismatches: []DocMismatch{
+						{Field: "config", Message: "documented as Required but Optional"},
+						{Field: "config.enabled", Message: "documented as Optional but Output"},
+						{Field: "create_time", Message: "documented as Output but Optional"},
+						{Field: "labels", Message: "documented with nested fields but isn't a block"},
+						{Field: "name", Message: "documented as Optional but Required"},
+						{Field: "network", Message: "documented without nested fields but is a block"},
+						{Field: "size", Message: `documented default is "10" but the default is "20"`},
+						{Field: "tags", Message: "documented with nested fields but is a list of string"},
+						{Field: "tier", Message: `documented default is "BASIC" but the default is "STANDARD"`},
+						{Field: "tier", Message: "documented possible values are BASIC, STANDARD but the possible values are BASIC, PREMIUM, STANDARD"},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := DetectDocMismatches(test.resources, test.metadata, "../testdata")
+			if err != nil {
+				t.Fatalf("DetectDocMismatches = %v, want = nil", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("DetectDocMismatches got unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
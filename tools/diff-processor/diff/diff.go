@@ -21,6 +21,7 @@ type ResourceDiff struct {
 	FlattenedSchema FlattenedSchemaRaw
 	Fields          map[string]FieldDiff
 	FieldSets       ResourceFieldSetsDiff
+	Metadata        ResourceMetadataDiff
 }
 
 type ResourceFieldSetsDiff struct {
@@ -56,21 +57,19 @@ func ComputeSchemaDiff(oldResourceMap, newResourceMap map[string]*schema.Resourc
 	schemaDiff := make(SchemaDiff)
 	for resource := range union(oldResourceMap, newResourceMap) {
 		// Compute diff between old and new resources and fields.
-		// TODO: add support for computing diff between resource configs, not just whether the
-		// resource was added/removed. b/300114839
 		resourceDiff := ResourceDiff{}
 		var flattenedOldSchema map[string]*schema.Schema
 		if oldResource, ok := oldResourceMap[resource]; ok {
 			flattenedOldSchema = flattenSchema("", oldResource.Schema)
 			resourceDiff.FlattenedSchema.Old = flattenedOldSchema
-			resourceDiff.ResourceConfig.Old = &schema.Resource{}
+			resourceDiff.ResourceConfig.Old = resourceConfig(oldResource)
 		}
 
 		var flattenedNewSchema map[string]*schema.Schema
 		if newResource, ok := newResourceMap[resource]; ok {
 			flattenedNewSchema = flattenSchema("", newResource.Schema)
 			resourceDiff.FlattenedSchema.New = flattenedNewSchema
-			resourceDiff.ResourceConfig.New = &schema.Resource{}
+			resourceDiff.ResourceConfig.New = resourceConfig(newResource)
 		}
 
 		resourceDiff.Fields = make(map[string]FieldDiff)
@@ -89,6 +88,17 @@ func ComputeSchemaDiff(oldResourceMap, newResourceMap map[string]*schema.Resourc
 	return schemaDiff
 }
 
+// resourceConfig returns the resource-level settings of r that are compared
+// between versions: its schema version and whether it can be imported. Other
+// settings are functions, which can't be compared, or are covered by fields.
+func resourceConfig(r *schema.Resource) *schema.Resource {
+	rc := &schema.Resource{SchemaVersion: r.SchemaVersion}
+	if r.Importer != nil {
+		rc.Importer = &schema.ResourceImporter{}
+	}
+	return rc
+}
+
 func flattenSchema(parentKey string, schemaObj map[string]*schema.Schema) map[string]*schema.Schema {
 	flattened := make(map[string]*schema.Schema)
 
@@ -356,3 +366,27 @@ func (rd ResourceDiff) IsFieldInNewNestedStructure(fieldPath string) bool {
 
 	return !parentExistsInOld && parentExistsInNew
 }
+
+// RenamedFields returns the top-level fields of the resource that were
+// renamed, keyed by their previous name. A field is considered renamed if it
+// became deprecated in favor of a newly added field of the same type that it
+// conflicts with, which keeps the previous name working as an alias.
+func (rd ResourceDiff) RenamedFields() map[string]string {
+	renamed := make(map[string]string)
+	for alias, aliasDiff := range rd.Fields {
+		if strings.Contains(alias, ".") || aliasDiff.Old == nil || aliasDiff.New == nil {
+			continue
+		}
+		if aliasDiff.Old.Deprecated != "" || aliasDiff.New.Deprecated == "" {
+			continue
+		}
+		for _, field := range append(aliasDiff.New.ConflictsWith, aliasDiff.New.ExactlyOneOf...) {
+			fieldDiff, ok := rd.Fields[field]
+			if ok && fieldDiff.Old == nil && fieldDiff.New != nil && fieldDiff.New.Type == aliasDiff.New.Type {
+				renamed[alias] = field
+				break
+			}
+		}
+	}
+	return renamed
+}
@@ -1766,3 +1766,92 @@ func TestIsFieldInNewNestedStructure(t *testing.T) {
 		})
 	}
 }
+
+func TestRenamedFields(t *testing.T) {
+	cases := map[string]struct {
+		oldResourceMap map[string]*schema.Resource
+		newResourceMap map[string]*schema.Resource
+		expected       map[string]string
+	}{
+		"field renamed with alias": {
+			oldResourceMap: map[string]*schema.Resource{
+				"google_resource": {
+					Schema: map[string]*schema.Schema{
+						"old_name": {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			newResourceMap: map[string]*schema.Resource{
+				"google_resource": {
+					Schema: map[string]*schema.Schema{
+						"old_name": {Type: schema.TypeString, Optional: true, Computed: true, Deprecated: "Use `new_name` instead.", ConflictsWith: []string{"new_name"}},
+						"new_name": {Type: schema.TypeString, Optional: true, Computed: true, ConflictsWith: []string{"old_name"}},
+					},
+				},
+			},
+			expected: map[string]string{"old_name": "new_name"},
+		},
+		"required field renamed with alias": {
+			oldResourceMap: map[string]*schema.Resource{
+				"google_resource": {
+					Schema: map[string]*schema.Schema{
+						"old_name": {Type: schema.TypeString, Required: true},
+					},
+				},
+			},
+			newResourceMap: map[string]*schema.Resource{
+				"google_resource": {
+					Schema: map[string]*schema.Schema{
+						"old_name": {Type: schema.TypeString, Optional: true, Computed: true, Deprecated: "Use `new_name` instead.", ExactlyOneOf: []string{"new_name", "old_name"}},
+						"new_name": {Type: schema.TypeString, Optional: true, Computed: true, ExactlyOneOf: []string{"new_name", "old_name"}},
+					},
+				},
+			},
+			expected: map[string]string{"old_name": "new_name"},
+		},
+		"field deprecated without replacement": {
+			oldResourceMap: map[string]*schema.Resource{
+				"google_resource": {
+					Schema: map[string]*schema.Schema{
+						"old_name": {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			newResourceMap: map[string]*schema.Resource{
+				"google_resource": {
+					Schema: map[string]*schema.Schema{
+						"old_name": {Type: schema.TypeString, Optional: true, Deprecated: "This field is going away."},
+					},
+				},
+			},
+			expected: map[string]string{},
+		},
+		"replacement of a different type": {
+			oldResourceMap: map[string]*schema.Resource{
+				"google_resource": {
+					Schema: map[string]*schema.Schema{
+						"old_name": {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			newResourceMap: map[string]*schema.Resource{
+				"google_resource": {
+					Schema: map[string]*schema.Schema{
+						"old_name":  {Type: schema.TypeString, Optional: true, Deprecated: "Use `new_names` instead.", ConflictsWith: []string{"new_names"}},
+						"new_names": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}, ConflictsWith: []string{"old_name"}},
+					},
+				},
+			},
+			expected: map[string]string{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			schemaDiff := ComputeSchemaDiff(tc.oldResourceMap, tc.newResourceMap)
+			if diff := cmp.Diff(tc.expected, schemaDiff["google_resource"].RenamedFields()); diff != "" {
+				t.Errorf("RenamedFields() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package diff
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// ResourceMetadata is the content of a resource's metadata file, such as
+// `resource_compute_address_generated_meta.yaml`. It records details of the
+// resource that its schema doesn't, like the values accepted by enum fields.
+type ResourceMetadata struct {
+	Resource            string          `yaml:"resource"`
+	GenerationType      string          `yaml:"generation_type"`
+	SourceFile          string          `yaml:"source_file"`
+	ApiServiceName      string          `yaml:"api_service_name"`
+	ApiVersion          string          `yaml:"api_version"`
+	ApiResourceTypeKind string          `yaml:"api_resource_type_kind"`
+	IdFormat            string          `yaml:"id_format"`
+	Fields              []FieldMetadata `yaml:"fields"`
+	// Service is the name of the directory holding the metadata file, such as
+	// `compute` for the provider's `google/services/compute`.
+	Service string `yaml:"-"`
+}
+
+type FieldMetadata struct {
+	// Field is the path of the field, in the same format as the keys of a
+	// ResourceDiff's Fields.
+	Field        string   `yaml:"field"`
+	ApiField     string   `yaml:"api_field"`
+	ProviderOnly bool     `yaml:"provider_only"`
+	EnumValues   []string `yaml:"enum_values"`
+}
+
+type ResourceMetadataDiff struct {
+	Old *ResourceMetadata
+	New *ResourceMetadata
+}
+
+// EnumValues returns the values accepted by field, or nil if it isn't an enum.
+func (m *ResourceMetadata) EnumValues(field string) []string {
+	if m == nil {
+		return nil
+	}
+	for _, f := range m.Fields {
+		if f.Field == field {
+			return f.EnumValues
+		}
+	}
+	return nil
+}
+
+// LoadResourceMetadata reads every metadata file under dir, keyed by resource.
+func LoadResourceMetadata(dir string) (map[string]*ResourceMetadata, error) {
+	metadata := make(map[string]*ResourceMetadata)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, "_meta.yaml") {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		m := &ResourceMetadata{}
+		if err := yaml.Unmarshal(b, m); err != nil {
+			return fmt.Errorf("parsing %s: %w", path, err)
+		}
+		if m.Resource == "" {
+			return fmt.Errorf("%s has no resource", path)
+		}
+		m.Service = filepath.Base(filepath.Dir(path))
+		metadata[m.Resource] = m
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// AddMetadata sets the metadata of the resources in sd. Resources whose schema
+// didn't change but whose ID format or enum values did are added to sd, as
+// validation functions can't be compared.
+func (sd SchemaDiff) AddMetadata(oldMetadata, newMetadata map[string]*ResourceMetadata) {
+	for resource := range union(oldMetadata, newMetadata) {
+		md := ResourceMetadataDiff{Old: oldMetadata[resource], New: newMetadata[resource]}
+		if rd, ok := sd[resource]; ok {
+			rd.Metadata = md
+			sd[resource] = rd
+			continue
+		}
+		if md.Old == nil || md.New == nil || !md.changed() {
+			continue
+		}
+		sd[resource] = ResourceDiff{
+			ResourceConfig: ResourceConfigDiff{Old: &schema.Resource{}, New: &schema.Resource{}},
+			Fields:         make(map[string]FieldDiff),
+			Metadata:       md,
+		}
+	}
+}
+
+// changed reports whether the ID format or enum values recorded in both old
+// and new metadata differ. Values missing from either side are ignored, so that
+// metadata files gaining new keys don't mark every resource as modified.
+func (md ResourceMetadataDiff) changed() bool {
+	if md.Old.IdFormat != "" && md.New.IdFormat != "" && md.Old.IdFormat != md.New.IdFormat {
+		return true
+	}
+	for _, f := range md.Old.Fields {
+		newValues := md.New.EnumValues(f.Field)
+		if len(f.EnumValues) > 0 && len(newValues) > 0 && !slices.Equal(f.EnumValues, newValues) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestLoadResourceMetadata(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "services", "compute"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"services/compute/resource_compute_address_generated_meta.yaml": `resource: 'google_compute_address'
+generation_type: 'mmv1'
+id_format: 'projects/{{project}}/regions/{{region}}/addresses/{{name}}'
+fields:
+  - field: 'address_type'
+    enum_values:
+      - 'INTERNAL'
+      - 'EXTERNAL'
+  - field: 'effective_labels'
+    provider_only: true
+`,
+		"services/compute/resource_compute_attached_disk_meta.yaml": `resource: 'google_compute_attached_disk'
+generation_type: 'handwritten'
+`,
+		"services/compute/resource_compute_address.go": "package compute",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := LoadResourceMetadata(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]*ResourceMetadata{
+		"google_compute_address": {
+			Resource:       "google_compute_address",
+			GenerationType: "mmv1",
+			IdFormat:       "projects/{{project}}/regions/{{region}}/addresses/{{name}}",
+			Service:        "compute",
+			Fields: []FieldMetadata{
+				{Field: "address_type", EnumValues: []string{"INTERNAL", "EXTERNAL"}},
+				{Field: "effective_labels", ProviderOnly: true},
+			},
+		},
+		"google_compute_attached_disk": {
+			Resource:       "google_compute_attached_disk",
+			GenerationType: "handwritten",
+			Service:        "compute",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LoadResourceMetadata() diff(-want, +got) = %s", diff)
+	}
+	if values := got["google_compute_address"].EnumValues("effective_labels"); values != nil {
+		t.Errorf("expected no enum values for effective_labels, got %v", values)
+	}
+}
+
+func TestSchemaDiffAddMetadata(t *testing.T) {
+	oldMetadata := map[string]*ResourceMetadata{
+		"google_x":         {Resource: "google_x", IdFormat: "{{name}}"},
+		"google_enum":      {Resource: "google_enum", Fields: []FieldMetadata{{Field: "type", EnumValues: []string{"A", "B"}}}},
+		"google_unchanged": {Resource: "google_unchanged", Fields: []FieldMetadata{{Field: "type", EnumValues: []string{"A"}}}},
+		"google_recorded":  {Resource: "google_recorded", Fields: []FieldMetadata{{Field: "type"}}},
+	}
+	newMetadata := map[string]*ResourceMetadata{
+		"google_x":         {Resource: "google_x", IdFormat: "{{name}}"},
+		"google_enum":      {Resource: "google_enum", Fields: []FieldMetadata{{Field: "type", EnumValues: []string{"A"}}}},
+		"google_unchanged": {Resource: "google_unchanged", Fields: []FieldMetadata{{Field: "type", EnumValues: []string{"A"}}}},
+		"google_recorded":  {Resource: "google_recorded", IdFormat: "{{name}}", Fields: []FieldMetadata{{Field: "type", EnumValues: []string{"A"}}}},
+		"google_new":       {Resource: "google_new", IdFormat: "{{name}}"},
+	}
+	sd := ComputeSchemaDiff(
+		map[string]*schema.Resource{"google_x": {Schema: map[string]*schema.Schema{"a": {Type: schema.TypeString, Optional: true}}}},
+		map[string]*schema.Resource{"google_x": {Schema: map[string]*schema.Schema{"a": {Type: schema.TypeString, Required: true}}}},
+	)
+	sd.AddMetadata(oldMetadata, newMetadata)
+
+	if len(sd) != 2 {
+		t.Errorf("expected google_x and google_enum to be modified, got %v", sd)
+	}
+	if md := sd["google_x"].Metadata; md.Old != oldMetadata["google_x"] || md.New != newMetadata["google_x"] {
+		t.Errorf("expected google_x's metadata to be set, got %v", md)
+	}
+	rd, ok := sd["google_enum"]
+	if !ok {
+		t.Fatalf("expected google_enum to be added")
+	}
+	if rd.IsNewResource() || rd.ResourceConfig.New == nil {
+		t.Errorf("expected google_enum to be a modified resource, got %v", rd.ResourceConfig)
+	}
+}
+
+func TestComputeSchemaDiff_resourceConfig(t *testing.T) {
+	importer := &schema.ResourceImporter{StateContext: schema.ImportStatePassthroughContext}
+	cases := map[string]struct {
+		oldResource *schema.Resource
+		newResource *schema.Resource
+		wantChanged bool
+	}{
+		"unchanged": {
+			oldResource: &schema.Resource{SchemaVersion: 1, Importer: importer},
+			newResource: &schema.Resource{SchemaVersion: 1, Importer: &schema.ResourceImporter{StateContext: schema.ImportStatePassthroughContext}},
+		},
+		"importer removed": {
+			oldResource: &schema.Resource{Importer: importer},
+			newResource: &schema.Resource{},
+			wantChanged: true,
+		},
+		"schema version changed": {
+			oldResource: &schema.Resource{SchemaVersion: 1},
+			newResource: &schema.Resource{SchemaVersion: 0},
+			wantChanged: true,
+		},
+	}
+	for tn, tc := range cases {
+		sd := ComputeSchemaDiff(map[string]*schema.Resource{"google_x": tc.oldResource}, map[string]*schema.Resource{"google_x": tc.newResource})
+		if _, changed := sd["google_x"]; changed != tc.wantChanged {
+			t.Errorf("%s: expected changed %v, got %v", tn, tc.wantChanged, changed)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Kind is the kind of schema a SchemaDiff is for.
+type Kind string
+
+const (
+	KindResource          Kind = "resource"
+	KindDataSource        Kind = "data source"
+	KindEphemeralResource Kind = "ephemeral resource"
+	KindFunction          Kind = "function"
+	KindProvider          Kind = "provider"
+)
+
+// Title returns the kind capitalized for the start of a sentence, such as
+// "Data source".
+func (k Kind) Title() string {
+	if k == "" {
+		return ""
+	}
+	return strings.ToUpper(string(k[:1])) + string(k[1:])
+}
+
+// Kinds lists every kind, in the order they are reported.
+var Kinds = []Kind{KindResource, KindDataSource, KindEphemeralResource, KindFunction, KindProvider}
+
+// ProviderSchema holds everything a provider exposes to configurations, keyed
+// by kind and then by name. Plugin framework schemas and function signatures
+// are converted to SDK schemas so that they can be diffed the same way:
+//   - framework attributes and blocks become fields, with blocks nested as
+//     resources
+//   - function parameters become required fields named
+//     `parameter_<position>`, its variadic parameter an optional list named
+//     `variadic_parameter` and its return value a computed `return` field
+//   - the provider configuration is stored under the name "google"
+type ProviderSchema map[Kind]map[string]*schema.Resource
+
+// ProviderSchemaDiff holds a SchemaDiff for each kind.
+type ProviderSchemaDiff map[Kind]SchemaDiff
+
+// NewProviderSchema merges the schemas of an SDK provider with those of a
+// plugin framework provider, as returned by its protocol 5 server. fw may be
+// nil for providers without a framework implementation.
+func NewProviderSchema(sdk *schema.Provider, fw *tfprotov5.GetProviderSchemaResponse) (ProviderSchema, error) {
+	ps := make(ProviderSchema)
+	for _, k := range Kinds {
+		ps[k] = make(map[string]*schema.Resource)
+	}
+	if sdk != nil {
+		for name, r := range sdk.ResourcesMap {
+			ps[KindResource][name] = r
+		}
+		for name, r := range sdk.DataSourcesMap {
+			ps[KindDataSource][name] = r
+		}
+		ps[KindProvider]["google"] = &schema.Resource{Schema: sdk.Schema}
+	}
+	if fw == nil {
+		return ps, nil
+	}
+
+	for kind, schemas := range map[Kind]map[string]*tfprotov5.Schema{
+		KindResource:          fw.ResourceSchemas,
+		KindDataSource:        fw.DataSourceSchemas,
+		KindEphemeralResource: fw.EphemeralResourceSchemas,
+	} {
+		for name, s := range schemas {
+			if _, ok := ps[kind][name]; ok {
+				return nil, fmt.Errorf("%s %s is defined by both the SDK and the plugin framework", kind, name)
+			}
+			r, err := frameworkResource(s)
+			if err != nil {
+				return nil, fmt.Errorf("converting the schema of %s %s: %w", kind, name, err)
+			}
+			ps[kind][name] = r
+		}
+	}
+	for name, f := range fw.Functions {
+		r, err := frameworkFunction(f)
+		if err != nil {
+			return nil, fmt.Errorf("converting the signature of function %s: %w", name, err)
+		}
+		ps[KindFunction][name] = r
+	}
+	return ps, nil
+}
+
+// ComputeProviderSchemaDiff computes the SchemaDiff of each kind.
+func ComputeProviderSchemaDiff(oldSchema, newSchema ProviderSchema) ProviderSchemaDiff {
+	psd := make(ProviderSchemaDiff)
+	for _, k := range Kinds {
+		psd[k] = ComputeSchemaDiff(oldSchema[k], newSchema[k])
+	}
+	return psd
+}
+
+func frameworkResource(s *tfprotov5.Schema) (*schema.Resource, error) {
+	if s == nil || s.Block == nil {
+		return &schema.Resource{Schema: map[string]*schema.Schema{}}, nil
+	}
+	r, err := frameworkBlock(s.Block)
+	if err != nil {
+		return nil, err
+	}
+	r.SchemaVersion = int(s.Version)
+	return r, nil
+}
+
+func frameworkBlock(b *tfprotov5.SchemaBlock) (*schema.Resource, error) {
+	fields := make(map[string]*schema.Schema)
+	for _, a := range b.Attributes {
+		field, err := frameworkType(a.Type)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", a.Name, err)
+		}
+		field.Required = a.Required
+		field.Optional = a.Optional
+		field.Computed = a.Computed
+		field.Sensitive = a.Sensitive
+		field.Description = a.Description
+		if a.Deprecated {
+			field.Deprecated = "deprecated"
+		}
+		fields[a.Name] = field
+	}
+	for _, nb := range b.BlockTypes {
+		elem, err := frameworkBlock(nb.Block)
+		if err != nil {
+			return nil, fmt.Errorf("block %s: %w", nb.TypeName, err)
+		}
+		field := &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: nb.MinItems == 0,
+			Required: nb.MinItems > 0,
+			MinItems: int(nb.MinItems),
+			MaxItems: int(nb.MaxItems),
+			Elem:     elem,
+		}
+		switch nb.Nesting {
+		case tfprotov5.SchemaNestedBlockNestingModeSingle, tfprotov5.SchemaNestedBlockNestingModeGroup:
+			field.MaxItems = 1
+		case tfprotov5.SchemaNestedBlockNestingModeSet:
+			field.Type = schema.TypeSet
+		case tfprotov5.SchemaNestedBlockNestingModeMap:
+			field.Type = schema.TypeMap
+		}
+		if nb.Block != nil {
+			field.Description = nb.Block.Description
+			if nb.Block.Deprecated {
+				field.Deprecated = "deprecated"
+			}
+		}
+		fields[nb.TypeName] = field
+	}
+	return &schema.Resource{Schema: fields}, nil
+}
+
+// frameworkType returns a field of the SDK type closest to t. Numbers become
+// floats, as the protocol doesn't distinguish integers, and objects become
+// lists of at most one nested resource, as in SDK schemas.
+func frameworkType(t tftypes.Type) (*schema.Schema, error) {
+	switch {
+	case t == nil:
+		return nil, fmt.Errorf("missing type")
+	case t.Is(tftypes.String), t.Is(tftypes.DynamicPseudoType):
+		return &schema.Schema{Type: schema.TypeString}, nil
+	case t.Is(tftypes.Bool):
+		return &schema.Schema{Type: schema.TypeBool}, nil
+	case t.Is(tftypes.Number):
+		return &schema.Schema{Type: schema.TypeFloat}, nil
+	case t.Is(tftypes.List{}), t.Is(tftypes.Set{}), t.Is(tftypes.Map{}):
+		var elemType tftypes.Type
+		field := &schema.Schema{}
+		switch tt := t.(type) {
+		case tftypes.List:
+			field.Type, elemType = schema.TypeList, tt.ElementType
+		case tftypes.Set:
+			field.Type, elemType = schema.TypeSet, tt.ElementType
+		case tftypes.Map:
+			field.Type, elemType = schema.TypeMap, tt.ElementType
+		}
+		elem, err := frameworkType(elemType)
+		if err != nil {
+			return nil, err
+		}
+		if r, ok := elem.Elem.(*schema.Resource); ok && elem.MaxItems == 1 {
+			field.Elem = r
+		} else {
+			field.Elem = elem
+		}
+		return field, nil
+	case t.Is(tftypes.Object{}):
+		fields := make(map[string]*schema.Schema)
+		for name, at := range t.(tftypes.Object).AttributeTypes {
+			field, err := frameworkType(at)
+			if err != nil {
+				return nil, fmt.Errorf("attribute %s: %w", name, err)
+			}
+			field.Optional = true
+			fields[name] = field
+		}
+		return &schema.Schema{Type: schema.TypeList, MaxItems: 1, Elem: &schema.Resource{Schema: fields}}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// frameworkFunction represents the signature of f as a resource, with a field
+// per parameter. Parameters are passed by position, so they are keyed by
+// position only: renaming one isn't a change, while changing the type of a
+// position is.
+func frameworkFunction(f *tfprotov5.Function) (*schema.Resource, error) {
+	fields := make(map[string]*schema.Schema)
+	for i, p := range f.Parameters {
+		field, err := frameworkType(p.Type)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", p.Name, err)
+		}
+		field.Required = true
+		field.Description = p.Description
+		fields[fmt.Sprintf("parameter_%d", i)] = field
+	}
+	if p := f.VariadicParameter; p != nil {
+		elem, err := frameworkType(p.Type)
+		if err != nil {
+			return nil, fmt.Errorf("variadic parameter %s: %w", p.Name, err)
+		}
+		fields["variadic_parameter"] = &schema.Schema{Type: schema.TypeList, Optional: true, Elem: elem, Description: p.Description}
+	}
+	if f.Return != nil {
+		field, err := frameworkType(f.Return.Type)
+		if err != nil {
+			return nil, fmt.Errorf("return: %w", err)
+		}
+		field.Computed = true
+		fields["return"] = field
+	}
+	r := &schema.Resource{Schema: fields, Description: f.Description}
+	if f.DeprecationMessage != "" {
+		r.DeprecationMessage = f.DeprecationMessage
+	}
+	return r, nil
+}
@@ -0,0 +1,186 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestNewProviderSchema(t *testing.T) {
+	sdk := &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"project": {Type: schema.TypeString, Optional: true},
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"google_x": {Schema: map[string]*schema.Schema{"name": {Type: schema.TypeString, Required: true}}},
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"google_x": {Schema: map[string]*schema.Schema{"name": {Type: schema.TypeString, Required: true}}},
+		},
+	}
+	fw := &tfprotov5.GetProviderSchemaResponse{
+		DataSourceSchemas: map[string]*tfprotov5.Schema{
+			"google_client_config": {
+				Block: &tfprotov5.SchemaBlock{
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{Name: "access_token", Type: tftypes.String, Computed: true, Sensitive: true},
+						{Name: "labels", Type: tftypes.Map{ElementType: tftypes.String}, Optional: true, Deprecated: true},
+						{Name: "ports", Type: tftypes.List{ElementType: tftypes.Number}, Optional: true},
+						{Name: "rules", Type: tftypes.Set{ElementType: tftypes.Object{AttributeTypes: map[string]tftypes.Type{"enabled": tftypes.Bool}}}, Optional: true},
+					},
+					BlockTypes: []*tfprotov5.SchemaNestedBlock{
+						{
+							TypeName: "timeouts",
+							Nesting:  tfprotov5.SchemaNestedBlockNestingModeSingle,
+							Block: &tfprotov5.SchemaBlock{
+								Attributes: []*tfprotov5.SchemaAttribute{{Name: "read", Type: tftypes.String, Optional: true}},
+							},
+						},
+					},
+				},
+			},
+		},
+		EphemeralResourceSchemas: map[string]*tfprotov5.Schema{
+			"google_x_token": {
+				Version: 1,
+				Block: &tfprotov5.SchemaBlock{
+					BlockTypes: []*tfprotov5.SchemaNestedBlock{
+						{
+							TypeName: "scope",
+							Nesting:  tfprotov5.SchemaNestedBlockNestingModeSet,
+							MinItems: 1,
+							Block:    &tfprotov5.SchemaBlock{},
+						},
+					},
+				},
+			},
+		},
+		Functions: map[string]*tfprotov5.Function{
+			"name_from_id": {
+				Parameters:        []*tfprotov5.FunctionParameter{{Name: "id", Type: tftypes.String}},
+				VariadicParameter: &tfprotov5.FunctionParameter{Name: "more", Type: tftypes.Bool},
+				Return:            &tfprotov5.FunctionReturn{Type: tftypes.String},
+			},
+		},
+	}
+
+	got, err := NewProviderSchema(sdk, fw)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := ProviderSchema{
+		KindResource: {
+			"google_x": sdk.ResourcesMap["google_x"],
+		},
+		KindDataSource: {
+			"google_x": sdk.DataSourcesMap["google_x"],
+			"google_client_config": {
+				Schema: map[string]*schema.Schema{
+					"access_token": {Type: schema.TypeString, Computed: true, Sensitive: true},
+					"labels":       {Type: schema.TypeMap, Optional: true, Deprecated: "deprecated", Elem: &schema.Schema{Type: schema.TypeString}},
+					"ports":        {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeFloat}},
+					"rules": {
+						Type:     schema.TypeSet,
+						Optional: true,
+						Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+							"enabled": {Type: schema.TypeBool, Optional: true},
+						}},
+					},
+					"timeouts": {
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+							"read": {Type: schema.TypeString, Optional: true},
+						}},
+					},
+				},
+			},
+		},
+		KindEphemeralResource: {
+			"google_x_token": {
+				SchemaVersion: 1,
+				Schema: map[string]*schema.Schema{
+					"scope": {Type: schema.TypeSet, Required: true, MinItems: 1, Elem: &schema.Resource{Schema: map[string]*schema.Schema{}}},
+				},
+			},
+		},
+		KindFunction: {
+			"name_from_id": {
+				Schema: map[string]*schema.Schema{
+					"parameter_0":        {Type: schema.TypeString, Required: true},
+					"variadic_parameter": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeBool}},
+					"return":             {Type: schema.TypeString, Computed: true},
+				},
+			},
+		},
+		KindProvider: {
+			"google": {Schema: sdk.Schema},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(schema.Resource{})); diff != "" {
+		t.Errorf("NewProviderSchema() diff(-want, +got) = %s", diff)
+	}
+}
+
+func TestNewProviderSchema_conflict(t *testing.T) {
+	sdk := &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{"google_x": {}},
+	}
+	fw := &tfprotov5.GetProviderSchemaResponse{
+		ResourceSchemas: map[string]*tfprotov5.Schema{"google_x": {}},
+	}
+	if _, err := NewProviderSchema(sdk, fw); err == nil {
+		t.Errorf("expected an error for a resource defined twice")
+	}
+}
+
+func TestComputeProviderSchemaDiff(t *testing.T) {
+	function := func(name string, typ tftypes.Type) *schema.Resource {
+		r, err := frameworkFunction(&tfprotov5.Function{
+			Parameters: []*tfprotov5.FunctionParameter{{Name: name, Type: typ}},
+		})
+		if err != nil {
+			t.Fatalf("frameworkFunction() = %v", err)
+		}
+		return r
+	}
+	oldSchema := ProviderSchema{
+		KindFunction: {
+			"name_from_id":   function("id", tftypes.String),
+			"region_from_id": function("id", tftypes.String),
+		},
+	}
+	newSchema := ProviderSchema{
+		KindFunction: {
+			"name_from_id":   function("self_link", tftypes.String),
+			"region_from_id": function("id", tftypes.Number),
+		},
+		KindDataSource: {
+			"google_x": {Schema: map[string]*schema.Schema{}},
+		},
+	}
+	got := ComputeProviderSchemaDiff(oldSchema, newSchema)
+	for _, kind := range Kinds {
+		if _, ok := got[kind]; !ok {
+			t.Errorf("expected a diff for kind %s", kind)
+		}
+	}
+	if _, ok := got[KindFunction]["name_from_id"]; ok {
+		t.Errorf("expected renaming a parameter not to be a change, got %v", got[KindFunction]["name_from_id"])
+	}
+	fieldDiff, ok := got[KindFunction]["region_from_id"].Fields["parameter_0"]
+	if !ok || fieldDiff.Old.Type != schema.TypeString || fieldDiff.New.Type != schema.TypeFloat {
+		t.Errorf("expected the parameter's type to change, got %v", got[KindFunction]["region_from_id"])
+	}
+	if !got[KindDataSource]["google_x"].IsNewResource() {
+		t.Errorf("expected google_x to be a new data source")
+	}
+	if len(got[KindResource]) != 0 {
+		t.Errorf("expected no resource changes, got %v", got[KindResource])
+	}
+}
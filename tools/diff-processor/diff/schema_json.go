@@ -0,0 +1,384 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// SchemaJSONFile is the name of the file holding the output of
+// `terraform providers schema -json` in a schema directory.
+const SchemaJSONFile = "schema.json"
+
+// ComputeProviderSchemaDiffFromDirs computes the diff between two schema
+// directories, without building either provider. Each directory holds a
+// SchemaJSONFile and the provider's metadata files, in any subdirectory.
+//
+// The JSON schema written by Terraform only has what Terraform core needs, so
+// unlike the schema of a built provider it doesn't record ForceNew, defaults,
+// validation, diff suppression or importers, and numbers are all TypeFloat.
+// The schema written by MarshalProviderSchemaJSON records them in an "sdk"
+// object of each attribute, block and schema. Enum values and ID formats are
+// read from the metadata files.
+//
+// sdkDetails reports whether both schemas record the SDK details.
+func ComputeProviderSchemaDiffFromDirs(oldDir, newDir string) (psd ProviderSchemaDiff, sdkDetails bool, err error) {
+	oldSchema, oldMetadata, oldDetails, err := LoadSchemaDir(oldDir)
+	if err != nil {
+		return nil, false, err
+	}
+	newSchema, newMetadata, newDetails, err := LoadSchemaDir(newDir)
+	if err != nil {
+		return nil, false, err
+	}
+	psd = ComputeProviderSchemaDiff(oldSchema, newSchema)
+	psd[KindResource].AddMetadata(oldMetadata, newMetadata)
+	return psd, oldDetails && newDetails, nil
+}
+
+// LoadSchemaDir reads the provider schema and resource metadata of a schema
+// directory, as described in ComputeProviderSchemaDiffFromDirs.
+func LoadSchemaDir(dir string) (ProviderSchema, map[string]*ResourceMetadata, bool, error) {
+	ps, sdkDetails, err := LoadProviderSchemaJSON(filepath.Join(dir, SchemaJSONFile))
+	if err != nil {
+		return nil, nil, false, err
+	}
+	metadata, err := LoadResourceMetadata(dir)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return ps, metadata, sdkDetails, nil
+}
+
+// LoadProviderSchemaJSON reads the schema of the google or google-beta
+// provider from the output of `terraform providers schema -json` or of
+// MarshalProviderSchemaJSON, reporting whether it records the SDK details.
+func LoadProviderSchemaJSON(filename string) (ProviderSchema, bool, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, false, err
+	}
+	ps, sdkDetails, err := ParseProviderSchemaJSON(b)
+	if err != nil {
+		return nil, false, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return ps, sdkDetails, nil
+}
+
+// ParseProviderSchemaJSON is LoadProviderSchemaJSON for the content of a file.
+func ParseProviderSchemaJSON(b []byte) (ProviderSchema, bool, error) {
+	var f jsonProviderSchemas
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, false, err
+	}
+	var names []string
+	for name := range f.ProviderSchemas {
+		if base := path.Base(name); base == "google" || base == "google-beta" {
+			names = append(names, name)
+		}
+	}
+	if len(names) != 1 {
+		sort.Strings(names)
+		return nil, false, fmt.Errorf("expected the schema of one google or google-beta provider, found %v", names)
+	}
+	p := f.ProviderSchemas[names[0]]
+	ps, err := p.providerSchema()
+	if err != nil {
+		return nil, false, err
+	}
+	return ps, p.SDKDetails, nil
+}
+
+type jsonProviderSchemas struct {
+	FormatVersion   string                         `json:"format_version,omitempty"`
+	ProviderSchemas map[string]*jsonProviderSchema `json:"provider_schemas"`
+}
+
+type jsonProviderSchema struct {
+	Provider                 *jsonSchema              `json:"provider,omitempty"`
+	ResourceSchemas          map[string]*jsonSchema   `json:"resource_schemas,omitempty"`
+	DataSourceSchemas        map[string]*jsonSchema   `json:"data_source_schemas,omitempty"`
+	EphemeralResourceSchemas map[string]*jsonSchema   `json:"ephemeral_resource_schemas,omitempty"`
+	Functions                map[string]*jsonFunction `json:"functions,omitempty"`
+	// SDKDetails is set when the "sdk" objects are recorded, so that their
+	// absence means the attributes aren't set rather than unknown.
+	SDKDetails bool `json:"sdk_details,omitempty"`
+}
+
+type jsonSchema struct {
+	Version int64            `json:"version"`
+	Block   *jsonBlock       `json:"block,omitempty"`
+	SDK     *jsonSDKResource `json:"sdk,omitempty"`
+}
+
+type jsonBlock struct {
+	Attributes  map[string]*jsonAttribute   `json:"attributes,omitempty"`
+	BlockTypes  map[string]*jsonNestedBlock `json:"block_types,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+}
+
+type jsonAttribute struct {
+	Type        json.RawMessage `json:"type"`
+	Description string          `json:"description,omitempty"`
+	Required    bool            `json:"required,omitempty"`
+	Optional    bool            `json:"optional,omitempty"`
+	Computed    bool            `json:"computed,omitempty"`
+	Sensitive   bool            `json:"sensitive,omitempty"`
+	Deprecated  bool            `json:"deprecated,omitempty"`
+	SDK         *jsonSDKField   `json:"sdk,omitempty"`
+}
+
+type jsonNestedBlock struct {
+	NestingMode string        `json:"nesting_mode"`
+	Block       *jsonBlock    `json:"block,omitempty"`
+	MinItems    int64         `json:"min_items,omitempty"`
+	MaxItems    int64         `json:"max_items,omitempty"`
+	SDK         *jsonSDKField `json:"sdk,omitempty"`
+}
+
+// jsonSDKField holds the attributes of an SDK field that Terraform doesn't
+// write in the JSON schema. Validation and diff suppression functions can't
+// be compared, so only their presence is recorded.
+type jsonSDKField struct {
+	ForceNew       bool        `json:"force_new,omitempty"`
+	Default        interface{} `json:"default,omitempty"`
+	Validated      bool        `json:"validated,omitempty"`
+	ElemValidated  bool        `json:"elem_validated,omitempty"`
+	DiffSuppressed bool        `json:"diff_suppressed,omitempty"`
+}
+
+// jsonSDKResource holds the attributes of an SDK resource that Terraform
+// doesn't write in the JSON schema.
+type jsonSDKResource struct {
+	Importable bool `json:"importable,omitempty"`
+}
+
+type jsonFunction struct {
+	Description        string                   `json:"description,omitempty"`
+	DeprecationMessage string                   `json:"deprecation_message,omitempty"`
+	ReturnType         json.RawMessage          `json:"return_type,omitempty"`
+	Parameters         []*jsonFunctionParameter `json:"parameters,omitempty"`
+	VariadicParameter  *jsonFunctionParameter   `json:"variadic_parameter,omitempty"`
+}
+
+type jsonFunctionParameter struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Type        json.RawMessage `json:"type"`
+}
+
+var jsonNestingModes = map[string]tfprotov5.SchemaNestedBlockNestingMode{
+	"single": tfprotov5.SchemaNestedBlockNestingModeSingle,
+	"group":  tfprotov5.SchemaNestedBlockNestingModeGroup,
+	"list":   tfprotov5.SchemaNestedBlockNestingModeList,
+	"set":    tfprotov5.SchemaNestedBlockNestingModeSet,
+	"map":    tfprotov5.SchemaNestedBlockNestingModeMap,
+}
+
+// providerSchema converts the JSON schema to a protocol 5 schema response, so
+// that it is converted to SDK schemas like the plugin framework's.
+func (p *jsonProviderSchema) providerSchema() (ProviderSchema, error) {
+	resp := &tfprotov5.GetProviderSchemaResponse{
+		ResourceSchemas:          make(map[string]*tfprotov5.Schema),
+		DataSourceSchemas:        make(map[string]*tfprotov5.Schema),
+		EphemeralResourceSchemas: make(map[string]*tfprotov5.Schema),
+		Functions:                make(map[string]*tfprotov5.Function),
+	}
+	kindSchemas := map[Kind]map[string]*jsonSchema{
+		KindResource:          p.ResourceSchemas,
+		KindDataSource:        p.DataSourceSchemas,
+		KindEphemeralResource: p.EphemeralResourceSchemas,
+	}
+	for schemas, jsonSchemas := range map[*map[string]*tfprotov5.Schema]map[string]*jsonSchema{
+		&resp.ResourceSchemas:          p.ResourceSchemas,
+		&resp.DataSourceSchemas:        p.DataSourceSchemas,
+		&resp.EphemeralResourceSchemas: p.EphemeralResourceSchemas,
+	} {
+		for name, s := range jsonSchemas {
+			converted, err := s.schema()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			(*schemas)[name] = converted
+		}
+	}
+	for name, f := range p.Functions {
+		converted, err := f.function()
+		if err != nil {
+			return nil, fmt.Errorf("function %s: %w", name, err)
+		}
+		resp.Functions[name] = converted
+	}
+
+	ps, err := NewProviderSchema(nil, resp)
+	if err != nil {
+		return nil, err
+	}
+	for kind, jsonSchemas := range kindSchemas {
+		for name, s := range jsonSchemas {
+			s.applySDK(ps[kind][name])
+		}
+	}
+	if p.Provider != nil {
+		s, err := p.Provider.schema()
+		if err != nil {
+			return nil, fmt.Errorf("provider: %w", err)
+		}
+		r, err := frameworkResource(s)
+		if err != nil {
+			return nil, fmt.Errorf("provider: %w", err)
+		}
+		p.Provider.applySDK(r)
+		ps[KindProvider]["google"] = r
+	}
+	return ps, nil
+}
+
+// applySDK sets the SDK attributes recorded by s on r, its converted schema.
+// Recorded validation and diff suppression functions are set to functions
+// doing nothing.
+func (s *jsonSchema) applySDK(r *schema.Resource) {
+	if s.SDK != nil && s.SDK.Importable {
+		r.Importer = &schema.ResourceImporter{}
+	}
+	if s.Block != nil {
+		s.Block.applySDK(r)
+	}
+}
+
+func (b *jsonBlock) applySDK(r *schema.Resource) {
+	for name, a := range b.Attributes {
+		if field, ok := r.Schema[name]; ok {
+			a.SDK.apply(field)
+		}
+	}
+	for name, nb := range b.BlockTypes {
+		field, ok := r.Schema[name]
+		if !ok {
+			continue
+		}
+		nb.SDK.apply(field)
+		if elem, ok := field.Elem.(*schema.Resource); ok && nb.Block != nil {
+			nb.Block.applySDK(elem)
+		}
+	}
+}
+
+func noValidation(interface{}, string) ([]string, []error) { return nil, nil }
+
+func (f *jsonSDKField) apply(field *schema.Schema) {
+	if f == nil {
+		return
+	}
+	field.ForceNew = f.ForceNew
+	field.Default = f.Default
+	if f.Validated {
+		field.ValidateFunc = noValidation
+	}
+	if elem, ok := field.Elem.(*schema.Schema); ok && f.ElemValidated {
+		elem.ValidateFunc = noValidation
+	}
+	if f.DiffSuppressed {
+		field.DiffSuppressFunc = func(string, string, string, *schema.ResourceData) bool { return false }
+	}
+}
+
+func (s *jsonSchema) schema() (*tfprotov5.Schema, error) {
+	if s.Block == nil {
+		return &tfprotov5.Schema{Version: s.Version}, nil
+	}
+	b, err := s.Block.block()
+	if err != nil {
+		return nil, err
+	}
+	return &tfprotov5.Schema{Version: s.Version, Block: b}, nil
+}
+
+func (b *jsonBlock) block() (*tfprotov5.SchemaBlock, error) {
+	block := &tfprotov5.SchemaBlock{
+		Description: b.Description,
+		Deprecated:  b.Deprecated,
+	}
+	for name, a := range b.Attributes {
+		t, err := tftypes.ParseJSONType(a.Type)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", name, err)
+		}
+		block.Attributes = append(block.Attributes, &tfprotov5.SchemaAttribute{
+			Name:        name,
+			Type:        t,
+			Description: a.Description,
+			Required:    a.Required,
+			Optional:    a.Optional,
+			Computed:    a.Computed,
+			Sensitive:   a.Sensitive,
+			Deprecated:  a.Deprecated,
+		})
+	}
+	for name, nb := range b.BlockTypes {
+		nesting, ok := jsonNestingModes[nb.NestingMode]
+		if !ok {
+			return nil, fmt.Errorf("block %s: unknown nesting mode %q", name, nb.NestingMode)
+		}
+		nested := &tfprotov5.SchemaBlock{}
+		if nb.Block != nil {
+			var err error
+			if nested, err = nb.Block.block(); err != nil {
+				return nil, fmt.Errorf("block %s: %w", name, err)
+			}
+		}
+		block.BlockTypes = append(block.BlockTypes, &tfprotov5.SchemaNestedBlock{
+			TypeName: name,
+			Block:    nested,
+			Nesting:  nesting,
+			MinItems: nb.MinItems,
+			MaxItems: nb.MaxItems,
+		})
+	}
+	return block, nil
+}
+
+func (f *jsonFunction) function() (*tfprotov5.Function, error) {
+	function := &tfprotov5.Function{
+		Description:        f.Description,
+		DeprecationMessage: f.DeprecationMessage,
+	}
+	for _, p := range f.Parameters {
+		converted, err := p.parameter()
+		if err != nil {
+			return nil, err
+		}
+		function.Parameters = append(function.Parameters, converted)
+	}
+	if f.VariadicParameter != nil {
+		converted, err := f.VariadicParameter.parameter()
+		if err != nil {
+			return nil, err
+		}
+		function.VariadicParameter = converted
+	}
+	if len(f.ReturnType) > 0 {
+		t, err := tftypes.ParseJSONType(f.ReturnType)
+		if err != nil {
+			return nil, fmt.Errorf("return: %w", err)
+		}
+		function.Return = &tfprotov5.FunctionReturn{Type: t}
+	}
+	return function, nil
+}
+
+func (p *jsonFunctionParameter) parameter() (*tfprotov5.FunctionParameter, error) {
+	t, err := tftypes.ParseJSONType(p.Type)
+	if err != nil {
+		return nil, fmt.Errorf("parameter %s: %w", p.Name, err)
+	}
+	return &tfprotov5.FunctionParameter{Name: p.Name, Description: p.Description, Type: t}, nil
+}
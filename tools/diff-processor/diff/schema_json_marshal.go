@@ -0,0 +1,250 @@
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// MarshalProviderSchemaJSON writes the schema of a provider muxing an SDK
+// provider and a plugin framework provider in the format of
+// `terraform providers schema -json`, under the given provider address such
+// as registry.terraform.io/hashicorp/google. Unlike Terraform, it records the
+// ForceNew, default, validation, diff suppression and importer of SDK fields
+// and resources, so that diffing the written schemas finds the same changes as
+// diffing the built providers. fw may be nil for providers without a
+// framework implementation.
+func MarshalProviderSchemaJSON(address string, sdk *schema.Provider, fw *tfprotov5.GetProviderSchemaResponse) ([]byte, error) {
+	p := &jsonProviderSchema{
+		ResourceSchemas:          make(map[string]*jsonSchema),
+		DataSourceSchemas:        make(map[string]*jsonSchema),
+		EphemeralResourceSchemas: make(map[string]*jsonSchema),
+		Functions:                make(map[string]*jsonFunction),
+		SDKDetails:               true,
+	}
+	for _, resp := range []*tfprotov5.GetProviderSchemaResponse{sdkProviderSchema(sdk), fw} {
+		if resp == nil {
+			continue
+		}
+		if err := p.addSchemas(resp); err != nil {
+			return nil, err
+		}
+	}
+	if sdk != nil {
+		if p.Provider != nil && p.Provider.Block != nil {
+			p.Provider.Block.addSDK(sdk.Schema)
+		}
+		for name, r := range sdk.ResourcesMap {
+			p.ResourceSchemas[name].addSDK(r)
+		}
+		for name, r := range sdk.DataSourcesMap {
+			p.DataSourceSchemas[name].addSDK(r)
+		}
+	}
+	return json.MarshalIndent(jsonProviderSchemas{
+		FormatVersion:   "1.0",
+		ProviderSchemas: map[string]*jsonProviderSchema{address: p},
+	}, "", "  ")
+}
+
+// sdkProviderSchema returns the schema response of an SDK provider, which
+// converts its fields to Terraform types like Terraform reads them.
+func sdkProviderSchema(sdk *schema.Provider) *tfprotov5.GetProviderSchemaResponse {
+	if sdk == nil {
+		return nil
+	}
+	resp, _ := schema.NewGRPCProviderServer(sdk).GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	return resp
+}
+
+func (p *jsonProviderSchema) addSchemas(resp *tfprotov5.GetProviderSchemaResponse) error {
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			return fmt.Errorf("%s: %s", d.Summary, d.Detail)
+		}
+	}
+	if resp.Provider != nil && p.Provider == nil {
+		s, err := newJSONSchema(resp.Provider)
+		if err != nil {
+			return fmt.Errorf("provider: %w", err)
+		}
+		p.Provider = s
+	}
+	for jsonSchemas, schemas := range map[*map[string]*jsonSchema]map[string]*tfprotov5.Schema{
+		&p.ResourceSchemas:          resp.ResourceSchemas,
+		&p.DataSourceSchemas:        resp.DataSourceSchemas,
+		&p.EphemeralResourceSchemas: resp.EphemeralResourceSchemas,
+	} {
+		for name, s := range schemas {
+			if _, ok := (*jsonSchemas)[name]; ok {
+				return fmt.Errorf("%s is defined by both the SDK and the plugin framework", name)
+			}
+			converted, err := newJSONSchema(s)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			(*jsonSchemas)[name] = converted
+		}
+	}
+	for name, f := range resp.Functions {
+		converted, err := newJSONFunction(f)
+		if err != nil {
+			return fmt.Errorf("function %s: %w", name, err)
+		}
+		p.Functions[name] = converted
+	}
+	return nil
+}
+
+func newJSONSchema(s *tfprotov5.Schema) (*jsonSchema, error) {
+	if s.Block == nil {
+		return &jsonSchema{Version: s.Version}, nil
+	}
+	b, err := newJSONBlock(s.Block)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonSchema{Version: s.Version, Block: b}, nil
+}
+
+func newJSONBlock(b *tfprotov5.SchemaBlock) (*jsonBlock, error) {
+	block := &jsonBlock{
+		Attributes:  make(map[string]*jsonAttribute),
+		BlockTypes:  make(map[string]*jsonNestedBlock),
+		Description: b.Description,
+		Deprecated:  b.Deprecated,
+	}
+	for _, a := range b.Attributes {
+		t, err := marshalType(a.Type)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", a.Name, err)
+		}
+		block.Attributes[a.Name] = &jsonAttribute{
+			Type:        t,
+			Description: a.Description,
+			Required:    a.Required,
+			Optional:    a.Optional,
+			Computed:    a.Computed,
+			Sensitive:   a.Sensitive,
+			Deprecated:  a.Deprecated,
+		}
+	}
+	for _, nb := range b.BlockTypes {
+		nestingMode := ""
+		for mode, nesting := range jsonNestingModes {
+			if nesting == nb.Nesting {
+				nestingMode = mode
+			}
+		}
+		if nestingMode == "" {
+			return nil, fmt.Errorf("block %s: unknown nesting mode %s", nb.TypeName, nb.Nesting)
+		}
+		nested, err := newJSONBlock(nb.Block)
+		if err != nil {
+			return nil, fmt.Errorf("block %s: %w", nb.TypeName, err)
+		}
+		block.BlockTypes[nb.TypeName] = &jsonNestedBlock{
+			NestingMode: nestingMode,
+			Block:       nested,
+			MinItems:    nb.MinItems,
+			MaxItems:    nb.MaxItems,
+		}
+	}
+	return block, nil
+}
+
+func newJSONFunction(f *tfprotov5.Function) (*jsonFunction, error) {
+	function := &jsonFunction{
+		Description:        f.Description,
+		DeprecationMessage: f.DeprecationMessage,
+	}
+	for _, p := range f.Parameters {
+		converted, err := newJSONFunctionParameter(p)
+		if err != nil {
+			return nil, err
+		}
+		function.Parameters = append(function.Parameters, converted)
+	}
+	if f.VariadicParameter != nil {
+		converted, err := newJSONFunctionParameter(f.VariadicParameter)
+		if err != nil {
+			return nil, err
+		}
+		function.VariadicParameter = converted
+	}
+	if f.Return != nil {
+		t, err := marshalType(f.Return.Type)
+		if err != nil {
+			return nil, fmt.Errorf("return: %w", err)
+		}
+		function.ReturnType = t
+	}
+	return function, nil
+}
+
+func newJSONFunctionParameter(p *tfprotov5.FunctionParameter) (*jsonFunctionParameter, error) {
+	t, err := marshalType(p.Type)
+	if err != nil {
+		return nil, fmt.Errorf("parameter %s: %w", p.Name, err)
+	}
+	return &jsonFunctionParameter{Name: p.Name, Description: p.Description, Type: t}, nil
+}
+
+func marshalType(t tftypes.Type) (json.RawMessage, error) {
+	if t == nil {
+		return nil, fmt.Errorf("missing type")
+	}
+	return t.MarshalJSON()
+}
+
+// addSDK records the SDK attributes of r, the resource s was converted from.
+func (s *jsonSchema) addSDK(r *schema.Resource) {
+	if s == nil {
+		return
+	}
+	if r.Importer != nil {
+		s.SDK = &jsonSDKResource{Importable: true}
+	}
+	if s.Block != nil {
+		s.Block.addSDK(r.Schema)
+	}
+}
+
+func (b *jsonBlock) addSDK(fields map[string]*schema.Schema) {
+	for name, a := range b.Attributes {
+		if field, ok := fields[name]; ok {
+			a.SDK = newJSONSDKField(field)
+		}
+	}
+	for name, nb := range b.BlockTypes {
+		field, ok := fields[name]
+		if !ok {
+			continue
+		}
+		nb.SDK = newJSONSDKField(field)
+		if elem, ok := field.Elem.(*schema.Resource); ok && nb.Block != nil {
+			nb.Block.addSDK(elem.Schema)
+		}
+	}
+}
+
+// newJSONSDKField returns the SDK attributes of field, or nil if none is set.
+func newJSONSDKField(field *schema.Schema) *jsonSDKField {
+	f := &jsonSDKField{
+		ForceNew:       field.ForceNew,
+		Default:        field.Default,
+		Validated:      field.ValidateFunc != nil || field.ValidateDiagFunc != nil,
+		DiffSuppressed: field.DiffSuppressFunc != nil,
+	}
+	if elem, ok := field.Elem.(*schema.Schema); ok {
+		f.ElemValidated = elem.ValidateFunc != nil || elem.ValidateDiagFunc != nil
+	}
+	if !f.ForceNew && f.Default == nil && !f.Validated && !f.DiffSuppressed && !f.ElemValidated {
+		return nil
+	}
+	return f
+}
@@ -0,0 +1,108 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func TestMarshalProviderSchemaJSON(t *testing.T) {
+	sdk := &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"project": {Type: schema.TypeString, Optional: true, ValidateFunc: validation.NoZeroValues},
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"google_x": {
+				SchemaVersion: 1,
+				Importer:      &schema.ResourceImporter{},
+				Schema: map[string]*schema.Schema{
+					"name":  {Type: schema.TypeString, Required: true, ForceNew: true},
+					"size":  {Type: schema.TypeInt, Optional: true, Default: 10},
+					"mode":  {Type: schema.TypeString, Optional: true, DiffSuppressFunc: func(string, string, string, *schema.ResourceData) bool { return false }},
+					"zones": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.NoZeroValues}},
+					"rule": {
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						ForceNew: true,
+						Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+							"port": {Type: schema.TypeInt, Required: true, ForceNew: true},
+						}},
+					},
+				},
+			},
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"google_x": {Schema: map[string]*schema.Schema{
+				"name": {Type: schema.TypeString, Required: true},
+			}},
+		},
+	}
+	fw := &tfprotov5.GetProviderSchemaResponse{
+		ResourceSchemas: map[string]*tfprotov5.Schema{
+			"google_fw": {Block: &tfprotov5.SchemaBlock{Attributes: []*tfprotov5.SchemaAttribute{
+				{Name: "name", Type: tftypes.String, Required: true},
+			}}},
+		},
+		Functions: map[string]*tfprotov5.Function{
+			"name_from_id": {
+				Parameters: []*tfprotov5.FunctionParameter{{Name: "id", Type: tftypes.String}},
+				Return:     &tfprotov5.FunctionReturn{Type: tftypes.String},
+			},
+		},
+	}
+
+	b, err := MarshalProviderSchemaJSON("registry.terraform.io/hashicorp/google", sdk, fw)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ps, sdkDetails, err := ParseProviderSchemaJSON(b)
+	if err != nil {
+		t.Fatalf("error parsing the written schema: %s", err)
+	}
+	if !sdkDetails {
+		t.Error("ParseProviderSchemaJSON() didn't report the SDK details of the written schema")
+	}
+
+	x := ps[KindResource]["google_x"]
+	if x.Importer == nil || x.SchemaVersion != 1 {
+		t.Errorf("google_x = %+v, want an importable resource of version 1", x)
+	}
+	fields := flattenSchema("", x.Schema)
+	for name, want := range map[string]bool{"name": true, "size": false, "rule": true, "rule.port": true} {
+		if got := fields[name].ForceNew; got != want {
+			t.Errorf("google_x.%s ForceNew = %t, want %t", name, got, want)
+		}
+	}
+	if got := fields["size"].Default; got != float64(10) {
+		t.Errorf("google_x.size Default = %v, want 10", got)
+	}
+	if fields["mode"].DiffSuppressFunc == nil {
+		t.Error("google_x.mode lost its diff suppress function")
+	}
+	if fields["name"].ValidateFunc != nil || fields["zones"].Elem.(*schema.Schema).ValidateFunc == nil {
+		t.Error("google_x.zones elements lost their validation, or google_x.name gained some")
+	}
+	if ps[KindProvider]["google"].Schema["project"].ValidateFunc == nil {
+		t.Error("the provider's project field lost its validation")
+	}
+
+	for kind, names := range map[Kind][]string{
+		KindResource:   {"google_fw", "google_x"},
+		KindDataSource: {"google_x"},
+		KindFunction:   {"name_from_id"},
+	} {
+		var got []string
+		for name := range ps[kind] {
+			got = append(got, name)
+		}
+		if diff := cmp.Diff(names, got, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+			t.Errorf("%s names diff(-want, +got) = %s", kind, diff)
+		}
+	}
+}
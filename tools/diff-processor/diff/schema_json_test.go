@@ -0,0 +1,177 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestParseProviderSchemaJSON(t *testing.T) {
+	b := []byte(`{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/google": {
+      "provider": {
+        "version": 0,
+        "block": {"attributes": {"project": {"type": "string", "optional": true}}}
+      },
+      "resource_schemas": {
+        "google_x": {
+          "version": 1,
+          "block": {
+            "attributes": {
+              "name": {"type": "string", "required": true},
+              "labels": {"type": ["map", "string"], "optional": true, "computed": true},
+              "secret": {"type": "string", "optional": true, "sensitive": true, "deprecated": true}
+            },
+            "block_types": {
+              "rule": {
+                "nesting_mode": "list",
+                "max_items": 2,
+                "block": {"attributes": {"port": {"type": "number", "optional": true}}}
+              },
+              "scopes": {"nesting_mode": "set", "min_items": 1, "block": {}}
+            }
+          }
+        }
+      },
+      "data_source_schemas": {
+        "google_x": {"version": 0, "block": {"attributes": {"name": {"type": "string", "required": true}}}}
+      },
+      "functions": {
+        "name_from_id": {
+          "return_type": "string",
+          "parameters": [{"name": "id", "type": "string"}]
+        }
+      }
+    }
+  }
+}`)
+	got, sdkDetails, err := ParseProviderSchemaJSON(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if sdkDetails {
+		t.Error("ParseProviderSchemaJSON() reported SDK details for a schema written by Terraform")
+	}
+	want := ProviderSchema{
+		KindResource: {
+			"google_x": {
+				SchemaVersion: 1,
+				Schema: map[string]*schema.Schema{
+					"name":   {Type: schema.TypeString, Required: true},
+					"labels": {Type: schema.TypeMap, Optional: true, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+					"secret": {Type: schema.TypeString, Optional: true, Sensitive: true, Deprecated: "deprecated"},
+					"rule": {
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 2,
+						Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+							"port": {Type: schema.TypeFloat, Optional: true},
+						}},
+					},
+					"scopes": {
+						Type:     schema.TypeSet,
+						Required: true,
+						MinItems: 1,
+						Elem:     &schema.Resource{Schema: map[string]*schema.Schema{}},
+					},
+				},
+			},
+		},
+		KindDataSource: {
+			"google_x": {Schema: map[string]*schema.Schema{"name": {Type: schema.TypeString, Required: true}}},
+		},
+		KindEphemeralResource: {},
+		KindFunction: {
+			"name_from_id": {Schema: map[string]*schema.Schema{
+				"parameter_0": {Type: schema.TypeString, Required: true},
+				"return":      {Type: schema.TypeString, Computed: true},
+			}},
+		},
+		KindProvider: {
+			"google": {Schema: map[string]*schema.Schema{"project": {Type: schema.TypeString, Optional: true}}},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(schema.Resource{}, schema.Schema{})); diff != "" {
+		t.Errorf("ParseProviderSchemaJSON() diff(-want, +got) = %s", diff)
+	}
+}
+
+func TestParseProviderSchemaJSON_errors(t *testing.T) {
+	cases := map[string]string{
+		"no google provider": `{"provider_schemas": {"registry.terraform.io/hashicorp/random": {}}}`,
+		"two google providers": `{"provider_schemas": {
+  "registry.terraform.io/hashicorp/google": {},
+  "registry.terraform.io/hashicorp/google-beta": {}
+}}`,
+		"unknown type": `{"provider_schemas": {"registry.terraform.io/hashicorp/google": {
+  "resource_schemas": {"google_x": {"block": {"attributes": {"name": {"type": "text"}}}}}
+}}}`,
+		"unknown nesting mode": `{"provider_schemas": {"registry.terraform.io/hashicorp/google": {
+  "resource_schemas": {"google_x": {"block": {"block_types": {"rule": {"nesting_mode": "tuple"}}}}}
+}}}`,
+	}
+	for name, b := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := ParseProviderSchemaJSON([]byte(b)); err == nil {
+				t.Error("ParseProviderSchemaJSON() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestComputeProviderSchemaDiffFromDirs(t *testing.T) {
+	writeDir := func(schemaJSON, meta string) string {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, SchemaJSONFile), []byte(schemaJSON), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "resource_x_generated_meta.yaml"), []byte(meta), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	oldDir := writeDir(`{"provider_schemas": {"registry.terraform.io/hashicorp/google-beta": {"resource_schemas": {
+  "google_x": {"block": {"attributes": {"name": {"type": "string", "required": true}, "mode": {"type": "string", "optional": true}}}},
+  "google_y": {"block": {"attributes": {"name": {"type": "string", "required": true}}}}
+}}}}`, `resource: 'google_y'
+fields:
+  - field: 'name'
+    enum_values:
+      - 'A'
+      - 'B'
+`)
+	newDir := writeDir(`{"provider_schemas": {"registry.terraform.io/hashicorp/google-beta": {"resource_schemas": {
+  "google_x": {"block": {"attributes": {"name": {"type": "string", "required": true}}}},
+  "google_y": {"block": {"attributes": {"name": {"type": "string", "required": true}}}}
+}}}}`, `resource: 'google_y'
+fields:
+  - field: 'name'
+    enum_values:
+      - 'A'
+`)
+
+	psd, sdkDetails, err := ComputeProviderSchemaDiffFromDirs(oldDir, newDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if sdkDetails {
+		t.Error("ComputeProviderSchemaDiffFromDirs() reported SDK details for schemas written by Terraform")
+	}
+	resources := psd[KindResource]
+	if got := resources["google_x"].Fields["mode"]; got.Old == nil || got.New != nil {
+		t.Errorf("google_x.mode diff = %+v, want a removed field", got)
+	}
+	if got := resources["google_y"].Metadata.New.EnumValues("name"); !cmp.Equal(got, []string{"A"}) {
+		t.Errorf("google_y new enum values = %v, want [A]", got)
+	}
+
+	if _, _, err := ComputeProviderSchemaDiffFromDirs(oldDir, t.TempDir()); err == nil {
+		t.Error("ComputeProviderSchemaDiffFromDirs() succeeded without a schema file, want error")
+	}
+}
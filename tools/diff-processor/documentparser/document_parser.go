@@ -13,6 +13,14 @@ var (
 	nestedHashTagRegex  = regexp.MustCompile(`\(#(nested_[a-z0-9_]+)\)`)      // #(nested_xxx)
 	horizontalLineRegex = regexp.MustCompile("- - -|-{3,}")                   // - - - or ---
 
+	// * `xxx` - (Required
+	markerRegex = regexp.MustCompile("^[\\*|-]\\s+`[a-z0-9_\\./]+`\\s*-\\s*\\((Required|Optional|Output)\\b")
+	// Default value is `xxx` or Defaults to `xxx`
+	defaultRegex = regexp.MustCompile("Default(?: value is|s to) `([^`]*)`")
+	// Possible values are: `A`, `B`, and `C` or Each value may be one of: `A`, `B`
+	possibleValuesRegex = regexp.MustCompile("(?:Possible values are|Each value may be one of):((?:(?:,|\\s|and|or)*`[^`]*`)+)")
+	backtickedRegex     = regexp.MustCompile("`([^`]*)`")
+
 	sectionSeparator = "## "
 )
 
@@ -26,6 +34,28 @@ type node struct {
 	name     string
 	children []*node
 	text     string
+
+	argument       bool
+	marker         string
+	defaultValue   string
+	possibleValues []string
+}
+
+// Field is what the document says about a field.
+type Field struct {
+	// Path of the field, in the same format as FlattenFields.
+	Path string
+	// Argument is true for fields of the Argument Reference section.
+	Argument bool
+	// Marker is Required, Optional or Output when the description starts with
+	// one of them, and empty otherwise.
+	Marker string
+	// Default is the documented default value, if any.
+	Default string
+	// PossibleValues are the documented values of enum fields.
+	PossibleValues []string
+	// Nested is true when the field's nested fields are documented.
+	Nested bool
 }
 
 func NewParser() *DocumentParser {
@@ -45,6 +75,38 @@ func (d *DocumentParser) FlattenFields() []string {
 	return paths
 }
 
+// Fields returns the documented fields, sorted by path. Fields documented in
+// both the arguments and attributes sections are returned twice.
+func (d *DocumentParser) Fields() []Field {
+	var fields []Field
+	traverseFields(&fields, "", d.root)
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+	return fields
+}
+
+func traverseFields(fields *[]Field, path string, n *node) {
+	if n == nil {
+		return
+	}
+	curPath := n.name
+	if path != "" {
+		curPath = path + "." + n.name
+	}
+	if curPath != "" {
+		*fields = append(*fields, Field{
+			Path:           curPath,
+			Argument:       n.argument,
+			Marker:         n.marker,
+			Default:        n.defaultValue,
+			PossibleValues: n.possibleValues,
+			Nested:         len(n.children) > 0,
+		})
+	}
+	for _, c := range n.children {
+		traverseFields(fields, curPath, c)
+	}
+}
+
 func traverse(paths *[]string, path string, n *node) {
 	if n == nil {
 		return
@@ -92,6 +154,9 @@ func (d *DocumentParser) Parse(src []byte) error {
 			if err := d.bfs(root, d.nestedBlock); err != nil {
 				return err
 			}
+			if text == argument {
+				markArguments(root)
+			}
 			if d.root == nil {
 				d.root = root
 			} else {
@@ -102,6 +167,13 @@ func (d *DocumentParser) Parse(src []byte) error {
 	return nil
 }
 
+func markArguments(n *node) {
+	for _, c := range n.children {
+		c.argument = true
+		markArguments(c)
+	}
+}
+
 func (d *DocumentParser) extractNestedObject(input string) (string, error) {
 	parts := splitWithRegexp(input, nestedObjectRegex)
 	for _, p := range parts[1:] {
@@ -134,7 +206,14 @@ func (d *DocumentParser) bfs(root *node, nestedBlock map[string]string) error {
 				// There is a special case in some hand written resource eg. in compute_instance, where its attributes is in a.0.b.0.c format.
 				fieldName = strings.ReplaceAll(fieldName, ".0.", ".")
 				newNode := &node{
-					name: fieldName,
+					name:         fieldName,
+					marker:       findPattern(p, markerRegex),
+					defaultValue: findPattern(p, defaultRegex),
+				}
+				if values := findPattern(p, possibleValuesRegex); values != "" {
+					for _, match := range backtickedRegex.FindAllStringSubmatch(values, -1) {
+						newNode.possibleValues = append(newNode.possibleValues, match[1])
+					}
 				}
 				cur.children = append(cur.children, newNode)
 
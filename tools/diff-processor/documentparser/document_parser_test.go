@@ -89,6 +89,51 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestFields(t *testing.T) {
+	doc := strings.Join([]string{
+		"# google_x",
+		"",
+		"## Argument Reference",
+		"",
+		"* `name` - (Required) Name of the resource.",
+		"",
+		"* `tier` - (Optional, [Beta](https://terraform.io/docs/providers/google/guides/provider_versions.html))",
+		"  Tier of the resource.",
+		"  Default value is `STANDARD`.",
+		"  Possible values are: `BASIC`, `STANDARD`, and `PREMIUM`.",
+		"",
+		"* `config` - (Optional) Configuration.",
+		"  Structure is [documented below](#nested_config).",
+		"",
+		"<a name=\"nested_config\"></a>The `config` block supports:",
+		"",
+		"* `modes` - (Optional) Modes. Defaults to `[]`.",
+		"  Each value may be one of: `A`, `B`.",
+		"",
+		"* `size` - Size of the config.",
+		"",
+		"## Attributes Reference",
+		"",
+		"* `state` - (Output) State of the resource.",
+		"",
+	}, "\n")
+	parser := NewParser()
+	if err := parser.Parse([]byte(doc)); err != nil {
+		t.Fatal(err)
+	}
+	want := []Field{
+		{Path: "config", Argument: true, Marker: "Optional", Nested: true},
+		{Path: "config.modes", Argument: true, Marker: "Optional", Default: "[]", PossibleValues: []string{"A", "B"}},
+		{Path: "config.size", Argument: true},
+		{Path: "name", Argument: true, Marker: "Required"},
+		{Path: "state", Marker: "Output"},
+		{Path: "tier", Argument: true, Marker: "Optional", Default: "STANDARD", PossibleValues: []string{"BASIC", "STANDARD", "PREMIUM"}},
+	}
+	if diff := cmp.Diff(want, parser.Fields()); diff != "" {
+		t.Errorf("Fields() returned diff(-want, +got): %s", diff)
+	}
+}
+
 func TestTraverse(t *testing.T) {
 	n1 := &node{name: "n1"}
 	n2 := &node{name: "n2"}
@@ -0,0 +1,139 @@
+package upgrade_impact
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Instance is a managed resource instance recorded in Terraform state.
+type Instance struct {
+	// Address is the instance's address, such as
+	// `module.network.google_compute_network.default[0]`.
+	Address string
+	// Type is the resource type, such as `google_compute_network`.
+	Type       string
+	Attributes map[string]interface{}
+}
+
+// LoadInstances reads the managed resource instances in a state file, either
+// a `terraform.tfstate` file or the output of `terraform show -json`, which may
+// be for a state or a plan.
+func LoadInstances(filename string) ([]Instance, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	instances, err := ParseInstances(b)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return instances, nil
+}
+
+// ParseInstances is LoadInstances for the content of a state file.
+func ParseInstances(b []byte) ([]Instance, error) {
+	var f struct {
+		Version int `json:"version"`
+		// terraform.tfstate
+		Resources []tfstateResource `json:"resources"`
+		// terraform show -json
+		FormatVersion string      `json:"format_version"`
+		Values        *showValues `json:"values"`
+		PriorState    *struct {
+			Values *showValues `json:"values"`
+		} `json:"prior_state"`
+	}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, err
+	}
+	switch {
+	case f.FormatVersion != "":
+		values := f.Values
+		if f.PriorState != nil {
+			values = f.PriorState.Values
+		}
+		if values == nil {
+			return nil, nil
+		}
+		return showInstances(values.RootModule), nil
+	case f.Version == 4:
+		return tfstateInstances(f.Resources), nil
+	}
+	return nil, fmt.Errorf("unsupported state format, expected a version 4 state file or the output of `terraform show -json`")
+}
+
+type tfstateResource struct {
+	Module    string `json:"module"`
+	Mode      string `json:"mode"`
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	Instances []struct {
+		IndexKey   interface{}            `json:"index_key"`
+		Attributes map[string]interface{} `json:"attributes"`
+	} `json:"instances"`
+}
+
+func tfstateInstances(resources []tfstateResource) []Instance {
+	var instances []Instance
+	for _, r := range resources {
+		if r.Mode != "managed" {
+			continue
+		}
+		address := r.Type + "." + r.Name
+		if r.Module != "" {
+			address = r.Module + "." + address
+		}
+		for _, i := range r.Instances {
+			instances = append(instances, Instance{
+				Address:    address + indexSuffix(i.IndexKey),
+				Type:       r.Type,
+				Attributes: i.Attributes,
+			})
+		}
+	}
+	return instances
+}
+
+func indexSuffix(key interface{}) string {
+	switch k := key.(type) {
+	case float64:
+		return fmt.Sprintf("[%d]", int(k))
+	case string:
+		return fmt.Sprintf("[%s]", strconv.Quote(k))
+	}
+	return ""
+}
+
+type showValues struct {
+	RootModule showModule `json:"root_module"`
+}
+
+type showModule struct {
+	Resources []struct {
+		Address string                 `json:"address"`
+		Mode    string                 `json:"mode"`
+		Type    string                 `json:"type"`
+		Values  map[string]interface{} `json:"values"`
+	} `json:"resources"`
+	ChildModules []showModule `json:"child_modules"`
+}
+
+func showInstances(m showModule) []Instance {
+	var instances []Instance
+	for _, r := range m.Resources {
+		if r.Mode != "managed" {
+			continue
+		}
+		instances = append(instances, Instance{
+			Address:    r.Address,
+			Type:       r.Type,
+			Attributes: r.Values,
+		})
+	}
+	for _, child := range m.ChildModules {
+		instances = append(instances, showInstances(child)...)
+	}
+	return instances
+}
@@ -0,0 +1,101 @@
+package upgrade_impact
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseInstances(t *testing.T) {
+	cases := map[string]struct {
+		state   string
+		want    []Instance
+		wantErr bool
+	}{
+		"tfstate": {
+			state: `{
+  "version": 4,
+  "terraform_version": "1.9.0",
+  "resources": [
+    {"mode": "data", "type": "google_project", "name": "p", "instances": [{"attributes": {"id": "p"}}]},
+    {"mode": "managed", "type": "google_compute_network", "name": "n", "instances": [{"attributes": {"name": "n"}}]},
+    {
+      "module": "module.a",
+      "mode": "managed",
+      "type": "google_compute_subnetwork",
+      "name": "s",
+      "instances": [
+        {"index_key": 0, "attributes": {"name": "s0"}},
+        {"index_key": "b", "attributes": {"name": "sb"}}
+      ]
+    }
+  ]
+}`,
+			want: []Instance{
+				{Address: "google_compute_network.n", Type: "google_compute_network", Attributes: map[string]interface{}{"name": "n"}},
+				{Address: "module.a.google_compute_subnetwork.s[0]", Type: "google_compute_subnetwork", Attributes: map[string]interface{}{"name": "s0"}},
+				{Address: `module.a.google_compute_subnetwork.s["b"]`, Type: "google_compute_subnetwork", Attributes: map[string]interface{}{"name": "sb"}},
+			},
+		},
+		"show": {
+			state: `{
+  "format_version": "1.0",
+  "values": {
+    "root_module": {
+      "resources": [
+        {"address": "google_compute_network.n", "mode": "managed", "type": "google_compute_network", "values": {"name": "n"}}
+      ],
+      "child_modules": [
+        {
+          "address": "module.a",
+          "resources": [
+            {"address": "module.a.data.google_project.p", "mode": "data", "type": "google_project", "values": {"id": "p"}},
+            {"address": "module.a.google_compute_subnetwork.s[0]", "mode": "managed", "type": "google_compute_subnetwork", "values": {"name": "s0"}}
+          ]
+        }
+      ]
+    }
+  }
+}`,
+			want: []Instance{
+				{Address: "google_compute_network.n", Type: "google_compute_network", Attributes: map[string]interface{}{"name": "n"}},
+				{Address: "module.a.google_compute_subnetwork.s[0]", Type: "google_compute_subnetwork", Attributes: map[string]interface{}{"name": "s0"}},
+			},
+		},
+		"plan": {
+			state: `{
+  "format_version": "1.2",
+  "planned_values": {"root_module": {"resources": [{"address": "google_compute_network.planned", "mode": "managed", "type": "google_compute_network"}]}},
+  "prior_state": {
+    "format_version": "1.0",
+    "values": {"root_module": {"resources": [{"address": "google_compute_network.n", "mode": "managed", "type": "google_compute_network", "values": {"name": "n"}}]}}
+  }
+}`,
+			want: []Instance{
+				{Address: "google_compute_network.n", Type: "google_compute_network", Attributes: map[string]interface{}{"name": "n"}},
+			},
+		},
+		"empty show": {
+			state: `{"format_version": "1.0"}`,
+		},
+		"old tfstate": {
+			state:   `{"version": 3, "modules": []}`,
+			wantErr: true,
+		},
+		"not json": {
+			state:   `version = 4`,
+			wantErr: true,
+		},
+	}
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got, err := ParseInstances([]byte(tc.state))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseInstances() got error %v, want error %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ParseInstances() diff(-want, +got) = %s", diff)
+			}
+		})
+	}
+}
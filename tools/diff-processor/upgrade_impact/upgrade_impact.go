@@ -0,0 +1,293 @@
+package upgrade_impact
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Kind is the kind of impact an upgrade has on a resource instance.
+type Kind string
+
+const (
+	// KindRemovedResource instances are of a resource type that was removed.
+	KindRemovedResource Kind = "removed-resource"
+	// KindRemovedField instances set a field that was removed.
+	KindRemovedField Kind = "removed-field"
+	// KindInvalidValue instances have a value that the new validation rejects.
+	KindInvalidValue Kind = "invalid-value"
+	// KindReplacement instances will be replaced by the first apply after the
+	// upgrade, as a ForceNew field gets a new default.
+	KindReplacement Kind = "replacement"
+	// KindForceNew instances set a field that became ForceNew, so changing it
+	// will replace them.
+	KindForceNew Kind = "force-new"
+	// KindNewDefault instances will be updated by the first apply after the
+	// upgrade, as a field gets a new default.
+	KindNewDefault Kind = "new-default"
+)
+
+// Impact is the effect of an upgrade on a field of a resource instance.
+type Impact struct {
+	Address  string
+	Resource string
+	Field    string `json:",omitempty"`
+	Kind     Kind
+	Value    string `json:",omitempty"`
+	Message  string
+}
+
+// ComputeImpacts returns the impacts of the changes in schemaDiff on
+// instances. Values are compared to the state only: instances that leave a
+// field with a new default unset in their configuration can't be told apart
+// from those setting it to the old default.
+func ComputeImpacts(schemaDiff diff.SchemaDiff, instances []Instance) []Impact {
+	var impacts []Impact
+	for _, instance := range instances {
+		resourceDiff, ok := schemaDiff[instance.Type]
+		if !ok {
+			continue
+		}
+		if resourceDiff.ResourceConfig.New == nil {
+			impacts = append(impacts, Impact{
+				Address:  instance.Address,
+				Resource: instance.Type,
+				Kind:     KindRemovedResource,
+				Message:  fmt.Sprintf("`%s` is a `%s`, which was either removed or renamed", instance.Address, instance.Type),
+			})
+			continue
+		}
+		impacts = append(impacts, instanceImpacts(instance, resourceDiff)...)
+	}
+	sort.SliceStable(impacts, func(i, j int) bool {
+		if impacts[i].Address != impacts[j].Address {
+			return impacts[i].Address < impacts[j].Address
+		}
+		if impacts[i].Field != impacts[j].Field {
+			return impacts[i].Field < impacts[j].Field
+		}
+		return impacts[i].Kind < impacts[j].Kind
+	})
+	return impacts
+}
+
+func instanceImpacts(instance Instance, resourceDiff diff.ResourceDiff) []Impact {
+	var impacts []Impact
+	seen := make(map[string]bool)
+	add := func(field string, kind Kind, value interface{}, tmpl string, args ...interface{}) {
+		impact := Impact{
+			Address:  instance.Address,
+			Resource: instance.Type,
+			Field:    field,
+			Kind:     kind,
+			Message:  fmt.Sprintf(tmpl, args...),
+		}
+		if !isZero(value) {
+			impact.Value = formatValue(value)
+		}
+		key := strings.Join([]string{field, string(kind), impact.Value}, "\x00")
+		if !seen[key] {
+			seen[key] = true
+			impacts = append(impacts, impact)
+		}
+	}
+
+	for field, fieldDiff := range resourceDiff.Fields {
+		oldField, newField := fieldDiff.Old, fieldDiff.New
+		values := lookup(instance.Attributes, field)
+		switch {
+		case oldField != nil && newField == nil:
+			// Output-only fields are set by the server, not by configurations.
+			if oldField.Computed && !oldField.Optional {
+				continue
+			}
+			// Only report the outermost removed field.
+			if parent, ok := parentField(field); ok {
+				if parentDiff, ok := resourceDiff.Fields[parent]; ok && parentDiff.New == nil {
+					continue
+				}
+			}
+			for _, v := range values {
+				if !isZero(v) {
+					add(field, KindRemovedField, v, "`%s` sets `%s`, which was either removed or renamed", instance.Address, field)
+				}
+			}
+
+		case oldField == nil && newField != nil:
+			if newField.Default == nil || resourceDiff.IsFieldInNewNestedStructure(field) {
+				continue
+			}
+			for range values {
+				if newField.ForceNew {
+					add(field, KindReplacement, nil, "`%s` will be replaced, as `%s` was added with the default `%v` and ForceNew", instance.Address, field, newField.Default)
+				} else {
+					add(field, KindNewDefault, nil, "`%s` will be updated, as `%s` was added with the default `%v`", instance.Address, field, newField.Default)
+				}
+			}
+
+		default:
+			configurable := newField.Optional || newField.Required
+			for _, v := range values {
+				if configurable && !isZero(v) {
+					for _, err := range validate(newField, field, v) {
+						add(field, KindInvalidValue, v, "`%s` sets `%s` to a value that is no longer valid: %s", instance.Address, field, err)
+					}
+				}
+				if configurable && !oldField.ForceNew && newField.ForceNew && !isZero(v) {
+					add(field, KindForceNew, v, "`%s` will be replaced if `%s` changes, as it became ForceNew", instance.Address, field)
+				}
+				if newField.Default != nil && oldField.Default != newField.Default && isDefault(oldField, v) {
+					if newField.ForceNew {
+						add(field, KindReplacement, v, "`%s` will be replaced if `%s` is not set in its configuration, as its default changed from `%v` to `%v`", instance.Address, field, oldField.Default, newField.Default)
+					} else {
+						add(field, KindNewDefault, v, "`%s` will be updated if `%s` is not set in its configuration, as its default changed from `%v` to `%v`", instance.Address, field, oldField.Default, newField.Default)
+					}
+				}
+			}
+		}
+	}
+
+	// Enum values can be narrowed without any change to the schema, as
+	// validation functions can't be compared.
+	if md := resourceDiff.Metadata; md.Old != nil && md.New != nil {
+		for _, f := range md.Old.Fields {
+			newValues := md.New.EnumValues(f.Field)
+			if len(f.EnumValues) == 0 || len(newValues) == 0 {
+				continue
+			}
+			for _, v := range lookup(instance.Attributes, f.Field) {
+				for _, elem := range elements(v) {
+					if s, ok := elem.(string); ok && s != "" && slices.Contains(f.EnumValues, s) && !slices.Contains(newValues, s) {
+						add(f.Field, KindInvalidValue, s, "`%s` sets `%s` to `%s`, which is no longer accepted", instance.Address, f.Field, s)
+					}
+				}
+			}
+		}
+	}
+	return impacts
+}
+
+// lookup returns the values of field in attributes, one per element of the
+// blocks it is nested in. A field that isn't set in a block is returned as nil.
+func lookup(attributes map[string]interface{}, field string) []interface{} {
+	name, rest, nested := strings.Cut(field, ".")
+	v := attributes[name]
+	if !nested {
+		return []interface{}{v}
+	}
+	var values []interface{}
+	for _, elem := range elements(v) {
+		if block, ok := elem.(map[string]interface{}); ok {
+			values = append(values, lookup(block, rest)...)
+		}
+	}
+	return values
+}
+
+func parentField(field string) (string, bool) {
+	i := strings.LastIndex(field, ".")
+	if i < 0 {
+		return "", false
+	}
+	return field[:i], true
+}
+
+// elements returns the elements of a list or set value, or the value itself.
+func elements(v interface{}) []interface{} {
+	if l, ok := v.([]interface{}); ok {
+		return l
+	}
+	return []interface{}{v}
+}
+
+// validate runs the validation of s on a value from state, converted to the
+// type the SDK would pass. Validation of list and set elements is run for
+// each element.
+func validate(s *schema.Schema, key string, v interface{}) []error {
+	switch s.Type {
+	case schema.TypeList, schema.TypeSet:
+		elem, ok := s.Elem.(*schema.Schema)
+		if !ok {
+			return nil
+		}
+		var errs []error
+		for _, e := range elements(v) {
+			if !isZero(e) {
+				errs = append(errs, validate(elem, key, e)...)
+			}
+		}
+		return errs
+	case schema.TypeMap:
+		return nil
+	}
+	v = typed(s.Type, v)
+	if s.ValidateFunc != nil {
+		_, errs := s.ValidateFunc(v, key)
+		return errs
+	}
+	if s.ValidateDiagFunc != nil {
+		var errs []error
+		for _, d := range s.ValidateDiagFunc(v, cty.GetAttrPath(key)) {
+			if d.Severity == diag.Error {
+				errs = append(errs, fmt.Errorf("%s", d.Summary))
+			}
+		}
+		return errs
+	}
+	return nil
+}
+
+// typed converts a JSON value from state to the type used by the SDK.
+func typed(t schema.ValueType, v interface{}) interface{} {
+	if f, ok := v.(float64); ok && t == schema.TypeInt {
+		return int(f)
+	}
+	return v
+}
+
+// isDefault reports whether a state value is the default of s, or unset if s
+// has no default.
+func isDefault(s *schema.Schema, v interface{}) bool {
+	if s.Default == nil {
+		return isZero(v)
+	}
+	return fmt.Sprint(typed(s.Type, v)) == fmt.Sprint(s.Default)
+}
+
+// isZero reports whether a state value is unset. The SDK stores unset
+// primitives as their zero value.
+func isZero(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case float64:
+		return t == 0
+	case bool:
+		return !t
+	case []interface{}:
+		return len(t) == 0
+	case map[string]interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+func formatValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,198 @@
+package upgrade_impact
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func TestComputeImpacts(t *testing.T) {
+	cases := map[string]struct {
+		oldSchema  map[string]*schema.Schema
+		newSchema  map[string]*schema.Schema
+		attributes map[string]interface{}
+		want       []Impact
+	}{
+		"unchanged": {
+			oldSchema:  map[string]*schema.Schema{"name": {Type: schema.TypeString, Required: true}},
+			newSchema:  map[string]*schema.Schema{"name": {Type: schema.TypeString, Required: true}},
+			attributes: map[string]interface{}{"name": "a"},
+		},
+		"removed field set": {
+			oldSchema: map[string]*schema.Schema{
+				"name":  {Type: schema.TypeString, Required: true},
+				"alias": {Type: schema.TypeString, Optional: true},
+			},
+			newSchema:  map[string]*schema.Schema{"name": {Type: schema.TypeString, Required: true}},
+			attributes: map[string]interface{}{"name": "a", "alias": "b"},
+			want:       []Impact{{Field: "alias", Kind: KindRemovedField, Value: "b"}},
+		},
+		"removed field unset": {
+			oldSchema: map[string]*schema.Schema{
+				"name":  {Type: schema.TypeString, Required: true},
+				"alias": {Type: schema.TypeString, Optional: true},
+			},
+			newSchema:  map[string]*schema.Schema{"name": {Type: schema.TypeString, Required: true}},
+			attributes: map[string]interface{}{"name": "a", "alias": ""},
+		},
+		"removed output-only field set": {
+			oldSchema: map[string]*schema.Schema{
+				"name":        {Type: schema.TypeString, Required: true},
+				"create_time": {Type: schema.TypeString, Computed: true},
+			},
+			newSchema:  map[string]*schema.Schema{"name": {Type: schema.TypeString, Required: true}},
+			attributes: map[string]interface{}{"name": "a", "create_time": "2024-01-01T00:00:00Z"},
+		},
+		"removed optional computed field set": {
+			oldSchema: map[string]*schema.Schema{
+				"name":  {Type: schema.TypeString, Required: true},
+				"alias": {Type: schema.TypeString, Optional: true, Computed: true},
+			},
+			newSchema:  map[string]*schema.Schema{"name": {Type: schema.TypeString, Required: true}},
+			attributes: map[string]interface{}{"name": "a", "alias": "b"},
+			want:       []Impact{{Field: "alias", Kind: KindRemovedField, Value: "b"}},
+		},
+		"removed block": {
+			oldSchema: map[string]*schema.Schema{
+				"config": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+					"size": {Type: schema.TypeInt, Optional: true},
+				}}},
+			},
+			newSchema:  map[string]*schema.Schema{},
+			attributes: map[string]interface{}{"config": []interface{}{map[string]interface{}{"size": float64(3)}}},
+			want:       []Impact{{Field: "config", Kind: KindRemovedField, Value: `[{"size":3}]`}},
+		},
+		"narrowed validation": {
+			oldSchema: map[string]*schema.Schema{
+				"size": {Type: schema.TypeInt, Optional: true},
+				"tags": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			},
+			newSchema: map[string]*schema.Schema{
+				"size": {Type: schema.TypeInt, Optional: true, ValidateFunc: validation.IntBetween(1, 10)},
+				"tags": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringInSlice([]string{"a"}, false)}},
+			},
+			attributes: map[string]interface{}{"size": float64(20), "tags": []interface{}{"a", "b"}},
+			want: []Impact{
+				{Field: "size", Kind: KindInvalidValue, Value: "20"},
+				{Field: "tags", Kind: KindInvalidValue, Value: `["a","b"]`},
+			},
+		},
+		"nested field becoming force new": {
+			oldSchema: map[string]*schema.Schema{
+				"disk": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+					"type": {Type: schema.TypeString, Optional: true},
+				}}},
+			},
+			newSchema: map[string]*schema.Schema{
+				"disk": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+					"type": {Type: schema.TypeString, Optional: true, ForceNew: true},
+				}}},
+			},
+			attributes: map[string]interface{}{"disk": []interface{}{
+				map[string]interface{}{"type": "ssd"},
+				map[string]interface{}{"type": ""},
+			}},
+			want: []Impact{{Field: "disk.type", Kind: KindForceNew, Value: "ssd"}},
+		},
+		"new default": {
+			oldSchema: map[string]*schema.Schema{
+				"tier":    {Type: schema.TypeString, Optional: true, Default: "BASIC"},
+				"retries": {Type: schema.TypeInt, Optional: true, Default: 3},
+			},
+			newSchema: map[string]*schema.Schema{
+				"tier":    {Type: schema.TypeString, Optional: true, Default: "STANDARD"},
+				"retries": {Type: schema.TypeInt, Optional: true, Default: 5},
+			},
+			attributes: map[string]interface{}{"tier": "BASIC", "retries": float64(4)},
+			want:       []Impact{{Field: "tier", Kind: KindNewDefault, Value: "BASIC"}},
+		},
+		"new default on force new field": {
+			oldSchema:  map[string]*schema.Schema{"zone": {Type: schema.TypeString, Optional: true, ForceNew: true}},
+			newSchema:  map[string]*schema.Schema{"zone": {Type: schema.TypeString, Optional: true, ForceNew: true, Default: "a"}},
+			attributes: map[string]interface{}{},
+			want:       []Impact{{Field: "zone", Kind: KindReplacement}},
+		},
+		"added fields with defaults": {
+			oldSchema: map[string]*schema.Schema{"name": {Type: schema.TypeString, Required: true}},
+			newSchema: map[string]*schema.Schema{
+				"name":    {Type: schema.TypeString, Required: true},
+				"mode":    {Type: schema.TypeString, Optional: true, Default: "AUTO"},
+				"network": {Type: schema.TypeString, Optional: true, ForceNew: true, Default: "default"},
+				"label":   {Type: schema.TypeString, Optional: true},
+			},
+			attributes: map[string]interface{}{"name": "a"},
+			want: []Impact{
+				{Field: "mode", Kind: KindNewDefault},
+				{Field: "network", Kind: KindReplacement},
+			},
+		},
+	}
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			sd := diff.ComputeSchemaDiff(
+				map[string]*schema.Resource{"google_x": {Schema: tc.oldSchema}},
+				map[string]*schema.Resource{"google_x": {Schema: tc.newSchema}},
+			)
+			got := ComputeImpacts(sd, []Instance{{Address: "google_x.a", Type: "google_x", Attributes: tc.attributes}})
+			for i := range tc.want {
+				tc.want[i].Address = "google_x.a"
+				tc.want[i].Resource = "google_x"
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(Impact{}, "Message")); diff != "" {
+				t.Errorf("ComputeImpacts() diff(-want, +got) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestComputeImpacts_removedResource(t *testing.T) {
+	sd := diff.ComputeSchemaDiff(map[string]*schema.Resource{"google_x": {}}, map[string]*schema.Resource{})
+	got := ComputeImpacts(sd, []Instance{
+		{Address: "google_x.a", Type: "google_x"},
+		{Address: "google_y.a", Type: "google_y"},
+	})
+	want := []Impact{{
+		Address:  "google_x.a",
+		Resource: "google_x",
+		Kind:     KindRemovedResource,
+		Message:  "`google_x.a` is a `google_x`, which was either removed or renamed",
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ComputeImpacts() diff(-want, +got) = %s", diff)
+	}
+}
+
+func TestComputeImpacts_enumValues(t *testing.T) {
+	sd := diff.SchemaDiff{}
+	sd.AddMetadata(
+		map[string]*diff.ResourceMetadata{"google_x": {Resource: "google_x", Fields: []diff.FieldMetadata{
+			{Field: "config.type", EnumValues: []string{"A", "B", "C"}},
+		}}},
+		map[string]*diff.ResourceMetadata{"google_x": {Resource: "google_x", Fields: []diff.FieldMetadata{
+			{Field: "config.type", EnumValues: []string{"A"}},
+		}}},
+	)
+	got := ComputeImpacts(sd, []Instance{{
+		Address: "google_x.a",
+		Type:    "google_x",
+		Attributes: map[string]interface{}{"config": []interface{}{
+			map[string]interface{}{"type": "A"},
+			map[string]interface{}{"type": "B"},
+		}},
+	}})
+	want := []Impact{{
+		Address:  "google_x.a",
+		Resource: "google_x",
+		Field:    "config.type",
+		Kind:     KindInvalidValue,
+		Value:    "B",
+		Message:  "`google_x.a` sets `config.type` to `B`, which is no longer accepted",
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ComputeImpacts() diff(-want, +got) = %s", diff)
+	}
+}
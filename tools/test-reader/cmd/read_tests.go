@@ -55,6 +55,9 @@ func (o *readTestsOptions) run(args []string) error {
 				}
 			}
 		}
+		for _, reason := range test.Partial {
+			fmt.Printf("  Partially read: %s\n", reason)
+		}
 		fmt.Println("")
 		total += 1
 	}
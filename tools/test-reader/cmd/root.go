@@ -22,6 +22,7 @@ func newRootCmd() (*cobra.Command, *rootOptions, error) {
 		SilenceErrors: true,
 	}
 	cmd.AddCommand(newReadTestsCmd(o))
+	cmd.AddCommand(newSelectTestsCmd(o))
 	return cmd, o, nil
 }
 
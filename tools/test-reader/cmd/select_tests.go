@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/test-reader/reader"
+	"github.com/spf13/cobra"
+)
+
+const selectTestsDesc = `Print a regular expression for go test -run matching the tests in the given services directory that use the given resources.
+
+Resources are given by type, or by the provider files that were changed, such as resource_compute_address.go. Tests using or referring to any of them are selected, as well as tests that couldn't be fully read and are declared in files mentioning any of them. Nothing is printed when no test is selected.
+
+When a changed file maps to no resource, such as a helper or transport file, every test is selected: the regular expression is ".*" and --list prints all the top-level tests.`
+
+type selectTestsOptions struct {
+	rootOptions  *rootOptions
+	resources    []string
+	changedFiles []string
+	list         bool
+}
+
+func newSelectTestsCmd(rootOptions *rootOptions) *cobra.Command {
+	o := &selectTestsOptions{
+		rootOptions: rootOptions,
+	}
+	cmd := &cobra.Command{
+		Use:   "select-tests SERVICES_DIR",
+		Short: "Select the tests using changed resources",
+		Long:  selectTestsDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.run(args)
+		},
+	}
+	cmd.Flags().StringSliceVar(&o.resources, "resources", nil, "changed resource types, such as google_compute_address")
+	cmd.Flags().StringSliceVar(&o.changedFiles, "changed-files", nil, "changed provider files, whose resources are added to the changed resources")
+	cmd.Flags().BoolVar(&o.list, "list", false, "print the test names one per line instead of a regular expression")
+	return cmd
+}
+
+func (o *selectTestsOptions) run(args []string) error {
+	resources := append([]string{}, o.resources...)
+	var unmapped []string
+	for _, file := range o.changedFiles {
+		resources = append(resources, reader.ResourcesForFile(file)...)
+		if reader.AffectsAllTests(file) {
+			unmapped = append(unmapped, file)
+		}
+	}
+	sort.Strings(resources)
+
+	allTests, errs := reader.ReadAllTests(args[0])
+	for path, err := range errs {
+		fmt.Fprintf(os.Stderr, "error reading path: %s, err: %v\n", path, err)
+	}
+	if len(unmapped) > 0 {
+		fmt.Fprintf(os.Stderr, "selecting all tests, as changed files map to no resource: %s\n", strings.Join(unmapped, ", "))
+		if o.list {
+			for _, name := range reader.TopLevelTests(allTests) {
+				fmt.Println(name)
+			}
+		} else {
+			fmt.Println(".*")
+		}
+		return nil
+	}
+
+	names := reader.SelectTests(allTests, resources)
+	unread, err := reader.SelectUnreadTests(args[0], allTests, errs, resources)
+	if err != nil {
+		return err
+	}
+	names = mergeSorted(names, unread)
+	if o.list {
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return nil
+	}
+	if regex := reader.RunRegex(names); regex != "" {
+		fmt.Println(regex)
+	}
+	return nil
+}
+
+func mergeSorted(a, b []string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, name := range append(append([]string{}, a...), b...) {
+		if !seen[name] {
+			seen[name] = true
+			merged = append(merged, name)
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
@@ -15,6 +15,11 @@ import (
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	"github.com/zclconf/go-cty/cty/gocty"
 )
 
 type Resource map[string]any // config of one resource in a test
@@ -24,8 +29,17 @@ type Resources map[string]Resource // map of resource names to resource configs
 type Step map[string]Resources // map of resource types to resources of that type
 
 type Test struct {
-	Name  string
-	Steps []Step
+	Name string
+	// Name of the top-level test function running this test as a subtest,
+	// for serial tests.
+	Parent string
+	Steps  []Step
+	// Reasons why steps could only be partially read, if any. Their configs
+	// may be missing resources or fields.
+	Partial []string
+	// VcrSkipped is true when the test calls acctest.SkipIfVcr, so it only runs
+	// in nightly tests and not in VCR tests of pull requests.
+	VcrSkipped bool
 }
 
 func (t *Test) String() string {
@@ -110,11 +124,17 @@ func ReadTestFiles(filenames []string) ([]*Test, map[string]error) {
 	return tests, nil
 }
 
+// Context maps of known values by variable name, as passed to acctest.Nprintf.
+// Values that aren't literals are left out.
+type contexts map[string]map[string]string
+
 func readTestFunc(testFunc *ast.FuncDecl, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit) ([]*Test, error) {
 	// This is an exported test function.
 	var tests []*Test
 	var errs []error
 	vars := make(map[string]*ast.CompositeLit, len(testFunc.Body.List)) // map of variable names to composite literal values in function body
+	ctxs := make(contexts)
+	vcrSkipped := false
 	for _, stmt := range testFunc.Body.List {
 		if exprStmt, ok := stmt.(*ast.ExprStmt); ok {
 			if callExpr, ok := exprStmt.X.(*ast.CallExpr); ok {
@@ -122,12 +142,15 @@ func readTestFunc(testFunc *ast.FuncDecl, funcDecls map[string]*ast.FuncDecl, va
 				ident, isIdent := callExpr.Fun.(*ast.Ident)
 				selExpr, isSelExpr := callExpr.Fun.(*ast.SelectorExpr)
 				if isIdent && ident.Name == "VcrTest" || isSelExpr && selExpr.Sel.Name == "VcrTest" {
-					test, err := readVcrTestCall(callExpr, funcDecls, varDecls)
+					test, err := readVcrTestCall(callExpr, funcDecls, varDecls, ctxs)
 					if err != nil {
 						errs = append(errs, err)
 					}
 					test.Name = testFunc.Name.Name
+					test.VcrSkipped = vcrSkipped
 					tests = append(tests, test)
+				} else if isIdent && ident.Name == "SkipIfVcr" || isSelExpr && selExpr.Sel.Name == "SkipIfVcr" {
+					vcrSkipped = true
 				}
 			}
 		} else if assignStmt, ok := stmt.(*ast.AssignStmt); ok {
@@ -140,11 +163,15 @@ func readTestFunc(testFunc *ast.FuncDecl, funcDecls map[string]*ast.FuncDecl, va
 					}
 				}
 			}
+			ctxs.assign(assignStmt)
 		} else if rangeStmt, ok := stmt.(*ast.RangeStmt); ok {
 			if ident, ok := rangeStmt.X.(*ast.Ident); ok {
 				if varCompLit, ok := vars[ident.Name]; ok {
 					serialTests, serialErrs := readSerialTestCompLit(varCompLit, funcDecls, varDecls)
 					errs = append(errs, serialErrs...)
+					for _, test := range serialTests {
+						test.Parent = testFunc.Name.Name
+					}
 					tests = append(tests, serialTests...)
 				}
 			}
@@ -156,6 +183,86 @@ func readTestFunc(testFunc *ast.FuncDecl, funcDecls map[string]*ast.FuncDecl, va
 	return tests, nil
 }
 
+// Record the context maps assigned or modified by an assignment statement.
+// e.g. context := map[string]interface{}{"name": "value"} or context["name"] = "value"
+func (ctxs contexts) assign(assignStmt *ast.AssignStmt) {
+	if len(assignStmt.Lhs) != 1 || len(assignStmt.Rhs) != 1 {
+		return
+	}
+	switch lhs := assignStmt.Lhs[0].(type) {
+	case *ast.Ident:
+		if context, ok := readContextCompLit(assignStmt.Rhs[0]); ok {
+			ctxs[lhs.Name] = context
+		} else if ident, ok := assignStmt.Rhs[0].(*ast.Ident); ok && ctxs[ident.Name] != nil {
+			ctxs[lhs.Name] = ctxs[ident.Name]
+		}
+	case *ast.IndexExpr:
+		ident, ok := lhs.X.(*ast.Ident)
+		if !ok || ctxs[ident.Name] == nil {
+			return
+		}
+		key, ok := readLiteral(lhs.Index)
+		if !ok {
+			return
+		}
+		if value, ok := readLiteral(assignStmt.Rhs[0]); ok {
+			ctxs[ident.Name][key] = value
+		} else {
+			delete(ctxs[ident.Name], key)
+		}
+	}
+}
+
+// Read a context map from a map composite literal, keeping the literal values.
+func readContextCompLit(expr ast.Expr) (map[string]string, bool) {
+	compLit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+	if _, ok := compLit.Type.(*ast.MapType); !ok {
+		return nil, false
+	}
+	context := make(map[string]string, len(compLit.Elts))
+	for _, elt := range compLit.Elts {
+		if keyValueExpr, ok := elt.(*ast.KeyValueExpr); ok {
+			key, ok := readLiteral(keyValueExpr.Key)
+			if !ok {
+				continue
+			}
+			if value, ok := readLiteral(keyValueExpr.Value); ok {
+				context[key] = value
+			}
+		}
+	}
+	return context, true
+}
+
+// Read a literal the way acctest.Nprintf formats it.
+func readLiteral(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind == token.STRING {
+			value, err := strconv.Unquote(e.Value)
+			return value, err == nil
+		}
+		return e.Value, true
+	case *ast.Ident:
+		if e.Name == "true" || e.Name == "false" {
+			return e.Name, true
+		}
+	}
+	return "", false
+}
+
+// Read a context map passed as an argument.
+func (ctxs contexts) read(arg ast.Expr) (map[string]string, bool) {
+	if ident, ok := arg.(*ast.Ident); ok {
+		context, ok := ctxs[ident.Name]
+		return context, ok
+	}
+	return readContextCompLit(arg)
+}
+
 // Reads a composite literal which is either a slice or a map of serialized test functions.
 func readSerialTestCompLit(varCompLit *ast.CompositeLit, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit) ([]*Test, []error) {
 	var tests []*Test
@@ -182,12 +289,12 @@ func readSerialTestEltKeyValueExpr(eltKeyValueExpr *ast.KeyValueExpr, funcDecls
 	return nil, fmt.Errorf("element key value expression with key %+v had non-ident value %+v", eltKeyValueExpr.Key, eltKeyValueExpr.Value)
 }
 
-func readVcrTestCall(vcrTestCall *ast.CallExpr, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit) (*Test, error) {
+func readVcrTestCall(vcrTestCall *ast.CallExpr, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit, ctxs contexts) (*Test, error) {
 	for _, arg := range vcrTestCall.Args {
 		if vcrTestArgCompLit, ok := arg.(*ast.CompositeLit); ok {
 			if selExpr, ok := vcrTestArgCompLit.Type.(*ast.SelectorExpr); ok {
 				if ident, ok := selExpr.X.(*ast.Ident); ok && ident.Name == "resource" && selExpr.Sel.Name == "TestCase" {
-					return readTestCaseCompLit(vcrTestArgCompLit, funcDecls, varDecls)
+					return readTestCaseCompLit(vcrTestArgCompLit, funcDecls, varDecls, ctxs)
 				}
 			}
 		}
@@ -195,12 +302,12 @@ func readVcrTestCall(vcrTestCall *ast.CallExpr, funcDecls map[string]*ast.FuncDe
 	return nil, fmt.Errorf("failed to find TestCase in %v", vcrTestCall.Args)
 }
 
-func readTestCaseCompLit(testCaseCompLit *ast.CompositeLit, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit) (*Test, error) {
+func readTestCaseCompLit(testCaseCompLit *ast.CompositeLit, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit, ctxs contexts) (*Test, error) {
 	for _, elt := range testCaseCompLit.Elts {
 		if keyValueExpr, ok := elt.(*ast.KeyValueExpr); ok {
 			if ident, ok := keyValueExpr.Key.(*ast.Ident); ok && ident.Name == "Steps" {
 				if stepsCompLit, ok := keyValueExpr.Value.(*ast.CompositeLit); ok {
-					return readStepsCompLit(stepsCompLit, funcDecls, varDecls)
+					return readStepsCompLit(stepsCompLit, funcDecls, varDecls, ctxs)
 				}
 			}
 		}
@@ -208,7 +315,7 @@ func readTestCaseCompLit(testCaseCompLit *ast.CompositeLit, funcDecls map[string
 	return nil, fmt.Errorf("failed to find Steps in %v", testCaseCompLit.Elts)
 }
 
-func readStepsCompLit(stepsCompLit *ast.CompositeLit, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit) (*Test, error) {
+func readStepsCompLit(stepsCompLit *ast.CompositeLit, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit, ctxs contexts) (*Test, error) {
 	test := &Test{}
 	errs := make([]error, 0)
 	for _, elt := range stepsCompLit.Elts {
@@ -219,18 +326,24 @@ func readStepsCompLit(stepsCompLit *ast.CompositeLit, funcDecls map[string]*ast.
 						var configStr string
 						var err error
 						if configCallExpr, ok := keyValueExpr.Value.(*ast.CallExpr); ok {
-							configStr, err = readConfigCallExpr(configCallExpr, funcDecls, varDecls)
+							configStr, err = readConfigCallExpr(configCallExpr, funcDecls, varDecls, ctxs)
 						} else if ident, ok := keyValueExpr.Value.(*ast.Ident); ok {
 							if configVar, ok := varDecls[ident.Name]; ok {
 								configStr, err = strconv.Unquote(configVar.Value)
+							} else {
+								err = fmt.Errorf("failed to find config variable %s", ident.Name)
 							}
+						} else {
+							err = fmt.Errorf("unknown config %T", keyValueExpr.Value)
 						}
 						if err != nil {
 							errs = append(errs, err)
+							test.Partial = append(test.Partial, fmt.Sprintf("step %d: %v", len(test.Steps)+1, err))
 						}
 						step, err := readConfigStr(configStr)
 						if err != nil {
 							errs = append(errs, err)
+							test.Partial = append(test.Partial, fmt.Sprintf("step %d: %v", len(test.Steps)+1, err))
 						}
 						test.Steps = append(test.Steps, step)
 					}
@@ -245,21 +358,39 @@ func readStepsCompLit(stepsCompLit *ast.CompositeLit, funcDecls map[string]*ast.
 }
 
 // Read the call expression in the public test function that returns the config.
-func readConfigCallExpr(configCallExpr *ast.CallExpr, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit) (string, error) {
+// Context maps passed as arguments are bound to the config function's parameters.
+func readConfigCallExpr(configCallExpr *ast.CallExpr, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit, ctxs contexts) (string, error) {
 	if ident, ok := configCallExpr.Fun.(*ast.Ident); ok {
 		if configFunc, ok := funcDecls[ident.Name]; ok {
-			return readConfigFunc(configFunc, funcDecls, varDecls)
+			params := make(contexts)
+			var names []string
+			for _, field := range configFunc.Type.Params.List {
+				for _, name := range field.Names {
+					names = append(names, name.Name)
+				}
+			}
+			for i, arg := range configCallExpr.Args {
+				if i >= len(names) {
+					break
+				}
+				if context, ok := ctxs.read(arg); ok {
+					params[names[i]] = context
+				}
+			}
+			return readConfigFunc(configFunc, funcDecls, varDecls, params)
 		}
 		return "", fmt.Errorf("failed to find function declaration %s", ident.Name)
 	}
 	return "", fmt.Errorf("failed to get ident for %v", configCallExpr.Fun)
 }
 
-func readConfigFunc(configFunc *ast.FuncDecl, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit) (string, error) {
+func readConfigFunc(configFunc *ast.FuncDecl, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit, ctxs contexts) (string, error) {
 	for _, stmt := range configFunc.Body.List {
-		if returnStmt, ok := stmt.(*ast.ReturnStmt); ok {
+		if assignStmt, ok := stmt.(*ast.AssignStmt); ok {
+			ctxs.assign(assignStmt)
+		} else if returnStmt, ok := stmt.(*ast.ReturnStmt); ok {
 			if len(returnStmt.Results) > 0 {
-				return readConfigFuncResult(returnStmt.Results[0], funcDecls, varDecls)
+				return readConfigFuncResult(returnStmt.Results[0], funcDecls, varDecls, ctxs)
 			}
 			return "", fmt.Errorf("failed to find a config string in results %v", returnStmt.Results)
 		}
@@ -268,17 +399,17 @@ func readConfigFunc(configFunc *ast.FuncDecl, funcDecls map[string]*ast.FuncDecl
 }
 
 // Read the return result of a config func and return the config string.
-func readConfigFuncResult(result ast.Expr, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit) (string, error) {
+func readConfigFuncResult(result ast.Expr, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit, ctxs contexts) (string, error) {
 	if basicLit, ok := result.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
 		return strconv.Unquote(basicLit.Value)
 	} else if callExpr, ok := result.(*ast.CallExpr); ok {
-		return readConfigFuncCallExpr(callExpr, funcDecls, varDecls)
+		return readConfigFuncCallExpr(callExpr, funcDecls, varDecls, ctxs)
 	} else if binaryExpr, ok := result.(*ast.BinaryExpr); ok {
-		xConfigStr, err := readConfigFuncResult(binaryExpr.X, funcDecls, varDecls)
+		xConfigStr, err := readConfigFuncResult(binaryExpr.X, funcDecls, varDecls, ctxs)
 		if err != nil {
 			return "", err
 		}
-		yConfigStr, err := readConfigFuncResult(binaryExpr.Y, funcDecls, varDecls)
+		yConfigStr, err := readConfigFuncResult(binaryExpr.Y, funcDecls, varDecls, ctxs)
 		if err != nil {
 			return "", err
 		}
@@ -289,24 +420,68 @@ func readConfigFuncResult(result ast.Expr, funcDecls map[string]*ast.FuncDecl, v
 
 // Read the call expression in the config function that returns the config string.
 // The call expression can contain a nested call expression.
-// Return the config string.
-func readConfigFuncCallExpr(configFuncCallExpr *ast.CallExpr, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit) (string, error) {
+// Return the config string, with the known values of the context map passed
+// to acctest.Nprintf substituted.
+func readConfigFuncCallExpr(configFuncCallExpr *ast.CallExpr, funcDecls map[string]*ast.FuncDecl, varDecls map[string]*ast.BasicLit, ctxs contexts) (string, error) {
 	if len(configFuncCallExpr.Args) > 0 {
+		var configStr string
+		var err error
 		if basicLit, ok := configFuncCallExpr.Args[0].(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
-			return strconv.Unquote(basicLit.Value)
+			configStr, err = strconv.Unquote(basicLit.Value)
 		} else if nestedCallExpr, ok := configFuncCallExpr.Args[0].(*ast.CallExpr); ok {
-			return readConfigFuncCallExpr(nestedCallExpr, funcDecls, varDecls)
+			configStr, err = readConfigFuncCallExpr(nestedCallExpr, funcDecls, varDecls, ctxs)
+		} else {
+			// Config string not readable from args, attempt to read call expression as a helper function.
+			return readConfigCallExpr(configFuncCallExpr, funcDecls, varDecls, ctxs)
+		}
+		if err != nil || !isNprintfCall(configFuncCallExpr) || len(configFuncCallExpr.Args) < 2 {
+			return configStr, err
 		}
+		if context, ok := ctxs.read(configFuncCallExpr.Args[1]); ok {
+			for key, value := range context {
+				configStr = strings.ReplaceAll(configStr, "%{"+key+"}", value)
+			}
+		}
+		return configStr, nil
 	}
 	// Config string not readable from args, attempt to read call expression as a helper function.
-	return readConfigCallExpr(configFuncCallExpr, funcDecls, varDecls)
+	return readConfigCallExpr(configFuncCallExpr, funcDecls, varDecls, ctxs)
+}
+
+func isNprintfCall(callExpr *ast.CallExpr) bool {
+	if ident, ok := callExpr.Fun.(*ast.Ident); ok {
+		return ident.Name == "Nprintf"
+	}
+	if selExpr, ok := callExpr.Fun.(*ast.SelectorExpr); ok {
+		return selExpr.Sel.Name == "Nprintf"
+	}
+	return false
 }
 
 var subPattern = regexp.MustCompile("%({[^{}]*}|[vTtbcspqxXUeEfFgGdo])")
 
+// Meta-arguments that Terraform reserves in resource blocks, which aren't
+// resource fields.
+var metaArguments = []string{"count", "for_each", "depends_on", "provider", "lifecycle", "connection", "provisioner"}
+
+// Functions that may be called in literal count and for_each meta-arguments.
+var metaArgumentFunctions = map[string]function.Function{
+	"toset": stdlib.MakeToFunc(cty.Set(cty.DynamicPseudoType)),
+	"tomap": stdlib.MakeToFunc(cty.Map(cty.DynamicPseudoType)),
+}
+
+var (
+	countIndexPattern = regexp.MustCompile(`\bcount\.index\b`)
+	eachKeyPattern    = regexp.MustCompile(`\beach\.key\b`)
+	eachValuePattern  = regexp.MustCompile(`\beach\.value\b`)
+)
+
 // Read the config string and return a test step.
+// Resources with a literal count or for_each are expanded into their instances.
+// Modules and other counts and for_each aren't read, and are reported in the
+// error with the other parts of the config that couldn't be read.
 func readConfigStr(configStr string) (Step, error) {
-	// Remove fmt substitutions because they interfere with hcl parsing.
+	// Remove the remaining fmt substitutions because they interfere with hcl parsing.
 	// Replace with a value that can be parsed outside quotation marks.
 	configStr = subPattern.ReplaceAllString(configStr, "true")
 	parser := hclparse.NewParser()
@@ -314,7 +489,7 @@ func readConfigStr(configStr string) (Step, error) {
 	if diagnostics.HasErrors() {
 		return nil, fmt.Errorf("errors parsing hcl: %v", diagnostics.Errs())
 	}
-	content, diagnostics := file.Body.Content(&hcl.BodySchema{
+	content, _, diagnostics := file.Body.PartialContent(&hcl.BodySchema{
 		Blocks: []hcl.BlockHeaderSchema{
 			{
 				Type:       "resource",
@@ -332,6 +507,10 @@ func readConfigStr(configStr string) (Step, error) {
 				Type:       "provider",
 				LabelNames: []string{"name"},
 			},
+			{
+				Type:       "module",
+				LabelNames: []string{"name"},
+			},
 			{
 				Type: "locals",
 			},
@@ -342,6 +521,18 @@ func readConfigStr(configStr string) (Step, error) {
 	}
 	m := make(map[string]Resources)
 	errs := make([]error, 0)
+	locals := make(map[string]hcl.Expression)
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "locals":
+			attrs, _ := block.Body.JustAttributes()
+			for name, attr := range attrs {
+				locals[name] = attr.Expr
+			}
+		case "module":
+			errs = append(errs, fmt.Errorf("module %s isn't read", block.Labels[0]))
+		}
+	}
 	for _, block := range content.Blocks {
 		if len(block.Labels) != 2 {
 			continue
@@ -351,12 +542,21 @@ func readConfigStr(configStr string) (Step, error) {
 			m[block.Labels[0]] = make(Resources)
 		}
 		// Use the resource name as a key.
-		resourceConfig, err := readHCLBlockBody(block.Body, file.Bytes)
+		resourceConfig, err := readHCLBlockBody(block.Body, file.Bytes, locals)
 		if err != nil {
 			errs = append(errs, err)
 		}
+		for _, metaArgument := range metaArguments {
+			delete(resourceConfig, metaArgument)
+		}
 		resourceConfig = flattenResource(resourceConfig, "")
-		m[block.Labels[0]][block.Labels[1]] = resourceConfig
+		instances, err := expandInstances(block.Labels[0], block.Labels[1], block.Body, resourceConfig)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for name, instanceConfig := range instances {
+			m[block.Labels[0]][name] = instanceConfig
+		}
 	}
 	if len(errs) > 0 {
 		return m, fmt.Errorf("errors reading hcl blocks: %v", errs)
@@ -364,31 +564,112 @@ func readConfigStr(configStr string) (Step, error) {
 	return m, nil
 }
 
-func readHCLBlockBody(body hcl.Body, fileBytes []byte) (Resource, error) {
+// Expand the flattened config of a resource into the configs of its instances,
+// keyed by their name in Terraform addresses, when its count or for_each is a
+// literal. Otherwise the resource is read as a single instance named after it.
+func expandInstances(resourceType, name string, body hcl.Body, config Resource) (Resources, error) {
+	single := Resources{name: config}
+	syntaxBody, ok := body.(*hclsyntax.Body)
+	if !ok {
+		return single, nil
+	}
+	ctx := &hcl.EvalContext{Functions: metaArgumentFunctions}
+	if count, ok := syntaxBody.Attributes["count"]; ok {
+		value, diagnostics := count.Expr.Value(ctx)
+		var n int
+		if diagnostics.HasErrors() || value.IsNull() || !value.IsKnown() || value.Type() != cty.Number || gocty.FromCtyValue(value, &n) != nil {
+			return single, fmt.Errorf("count of %s.%s isn't read, it isn't a literal number", resourceType, name)
+		}
+		instances := make(Resources)
+		for i := 0; i < n; i++ {
+			instances[fmt.Sprintf("%s[%d]", name, i)] = replaceInResource(config, countIndexPattern, strconv.Itoa(i))
+		}
+		return instances, nil
+	}
+	if forEach, ok := syntaxBody.Attributes["for_each"]; ok {
+		value, diagnostics := forEach.Expr.Value(ctx)
+		ty := value.Type()
+		if diagnostics.HasErrors() || value.IsNull() || !value.IsWhollyKnown() || !(ty.IsSetType() || ty.IsMapType() || ty.IsObjectType()) {
+			return single, fmt.Errorf("for_each of %s.%s isn't read, it isn't a literal map or set", resourceType, name)
+		}
+		instances := make(Resources)
+		for it := value.ElementIterator(); it.Next(); {
+			key, element := it.Element()
+			if ty.IsSetType() {
+				key = element
+			}
+			if key.Type() != cty.String {
+				return single, fmt.Errorf("for_each of %s.%s isn't read, it isn't a set of strings", resourceType, name)
+			}
+			instance := replaceInResource(config, eachKeyPattern, hclSource(key))
+			instances[fmt.Sprintf("%s[%q]", name, key.AsString())] = replaceInResource(instance, eachValuePattern, hclSource(element))
+		}
+		return instances, nil
+	}
+	return single, nil
+}
+
+// Replace the matches of pattern in the field values of a flattened config.
+func replaceInResource(r Resource, pattern *regexp.Regexp, replacement string) Resource {
+	replaced := make(Resource, len(r))
+	for fieldName, fieldValue := range r {
+		if s, ok := fieldValue.(string); ok {
+			fieldValue = pattern.ReplaceAllLiteralString(s, replacement)
+		}
+		replaced[fieldName] = fieldValue
+	}
+	return replaced
+}
+
+// Return the HCL source of a value.
+func hclSource(value cty.Value) string {
+	return string(hclwrite.TokensForValue(value).Bytes())
+}
+
+// Read the fields of a block, resolving references to locals and expanding
+// dynamic blocks into their content.
+func readHCLBlockBody(body hcl.Body, fileBytes []byte, locals map[string]hcl.Expression) (Resource, error) {
 	var m Resource
 	gohcl.DecodeBody(body, nil, &m)
+	errs := make([]error, 0)
 	for k, v := range m {
 		if attr, ok := v.(*hcl.Attribute); ok {
-			m[k] = string(attr.Expr.Range().SliceBytes(fileBytes))
+			value, err := readHCLExpr(attr.Expr, fileBytes, locals, 0)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			m[k] = value
 		}
 	}
 	syntaxBody, ok := body.(*hclsyntax.Body)
 	if !ok {
 		return m, fmt.Errorf("couldn't get hclsyntax body from %v", body)
 	}
-	errs := make([]error, 0)
 	for _, block := range syntaxBody.Blocks {
-		blockConfig, err := readHCLBlockBody(block.Body, fileBytes)
+		blockType, blockBody := block.Type, hcl.Body(block.Body)
+		if block.Type == "dynamic" && len(block.Labels) == 1 {
+			blockType, blockBody = block.Labels[0], nil
+			for _, nestedBlock := range block.Body.Blocks {
+				if nestedBlock.Type == "content" {
+					blockBody = nestedBlock.Body
+				}
+			}
+			if blockBody == nil {
+				errs = append(errs, fmt.Errorf("dynamic block %s has no content", blockType))
+				continue
+			}
+		}
+		blockConfig, err := readHCLBlockBody(blockBody, fileBytes, locals)
 		if err != nil {
 			errs = append(errs, err)
 		}
-		if existing, ok := m[block.Type]; ok {
+		if existing, ok := m[blockType]; ok {
 			// Merge the fields from the current block into the existing resource config.
 			if existingResource, ok := existing.(Resource); ok {
 				mergeResources(existingResource, blockConfig)
 			}
 		} else {
-			m[block.Type] = blockConfig
+			m[blockType] = blockConfig
 		}
 	}
 	if len(errs) > 0 {
@@ -397,6 +678,26 @@ func readHCLBlockBody(body hcl.Body, fileBytes []byte) (Resource, error) {
 	return m, nil
 }
 
+// Read the source of an expression, or of the local it refers to.
+func readHCLExpr(expr hcl.Expression, fileBytes []byte, locals map[string]hcl.Expression, depth int) (string, error) {
+	traversalExpr, ok := expr.(*hclsyntax.ScopeTraversalExpr)
+	if !ok || traversalExpr.Traversal.RootName() != "local" || len(traversalExpr.Traversal) != 2 {
+		return string(expr.Range().SliceBytes(fileBytes)), nil
+	}
+	attr, ok := traversalExpr.Traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return string(expr.Range().SliceBytes(fileBytes)), nil
+	}
+	local, ok := locals[attr.Name]
+	if !ok {
+		return string(expr.Range().SliceBytes(fileBytes)), fmt.Errorf("failed to find local %s", attr.Name)
+	}
+	if depth >= len(locals) {
+		return string(expr.Range().SliceBytes(fileBytes)), fmt.Errorf("local %s refers to itself", attr.Name)
+	}
+	return readHCLExpr(local, fileBytes, locals, depth+1)
+}
+
 // Perform a recursive one-way merge of b into a.
 func mergeResources(a, b Resource) {
 	for k, bv := range b {
@@ -1,8 +1,12 @@
 package reader
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"os"
 	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -106,7 +110,8 @@ func TestReadSerialResourceTestFile(t *testing.T) {
 	}
 	if expectedTests := []*Test{
 		{
-			Name: "testAccSerialResource1",
+			Name:   "testAccSerialResource1",
+			Parent: "TestAccSerialResource",
 			Steps: []Step{
 				{
 					"serial_resource": {
@@ -116,7 +121,8 @@ func TestReadSerialResourceTestFile(t *testing.T) {
 			},
 		},
 		{
-			Name: "testAccSerialResource2",
+			Name:   "testAccSerialResource2",
+			Parent: "TestAccSerialResource",
 			Steps: []Step{
 				{
 					"serial_resource": {
@@ -141,7 +147,8 @@ func TestReadCrossFileTests(t *testing.T) {
 
 	expectedTests := []*Test{
 		{
-			Name: "testAccCrossFile1",
+			Name:   "testAccCrossFile1",
+			Parent: "TestAccCrossFile",
 			Steps: []Step{
 				{
 					"serial_resource": {
@@ -151,7 +158,8 @@ func TestReadCrossFileTests(t *testing.T) {
 			},
 		},
 		{
-			Name: "testAccCrossFile2",
+			Name:   "testAccCrossFile2",
+			Parent: "TestAccCrossFile",
 			Steps: []Step{
 				{
 					"serial_resource": {
@@ -204,6 +212,215 @@ func TestReadHelperFunctionCall(t *testing.T) {
 	}
 }
 
+func TestReadEvaluatedResourceTestFile(t *testing.T) {
+	tests, err := ReadTestFiles([]string{"testdata/service/evaluated_resource_test.go"})
+	if err != nil {
+		t.Fatalf("error reading evaluated resource test file: %v", err)
+	}
+	if len(tests) != 1 {
+		t.Fatalf("unexpected number of tests: %d, expected 1", len(tests))
+	}
+	expectedTest := &Test{
+		Name: "TestAccEvaluatedResource",
+		Steps: []Step{
+			{
+				"evaluated_resource": Resources{
+					"resource[0]": Resource{
+						"name":        "\"value-one-${random_suffix}\"",
+						"size":        "10",
+						"enabled":     "true",
+						"suffix":      "\"true\"",
+						"rule.action": "rule.value",
+					},
+					"resource[1]": Resource{
+						"name":        "\"value-one-${random_suffix}\"",
+						"size":        "10",
+						"enabled":     "true",
+						"suffix":      "\"true\"",
+						"rule.action": "rule.value",
+					},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(tests[0], expectedTest) {
+		t.Errorf("found unexpected evaluated test: %#v, expected %#v", tests[0], expectedTest)
+	}
+}
+
+func TestReadVcrSkippedTestFile(t *testing.T) {
+	tests, err := ReadTestFiles([]string{"testdata/service/vcr_skipped_resource_test.go", "testdata/service/covered_resource_test.go"})
+	if err != nil {
+		t.Fatalf("error reading vcr skipped test file: %v", err)
+	}
+	skipped := make(map[string]bool)
+	for _, test := range tests {
+		skipped[test.Name] = test.VcrSkipped
+	}
+	if expected := map[string]bool{"TestAccVcrSkippedResource": true, "TestAccCoveredResource": false}; !reflect.DeepEqual(skipped, expected) {
+		t.Errorf("found unexpected skipped tests: %v, expected %v", skipped, expected)
+	}
+}
+
+func TestReadPartialConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   string
+		expected Step
+		partial  []string
+	}{
+		{
+			name: "module",
+			config: `
+module "network" {
+  source = "./network"
+}
+resource "partial_resource" "resource" {
+  name = "value-one"
+}
+`,
+			expected: Step{"partial_resource": {"resource": {"name": "\"value-one\""}}},
+			partial:  []string{"step 1: errors reading hcl blocks: [module network isn't read]"},
+		},
+		{
+			name: "missing-local",
+			config: `
+resource "partial_resource" "resource" {
+  name = local.missing
+}
+`,
+			expected: Step{"partial_resource": {"resource": {"name": "local.missing"}}},
+			partial:  []string{"step 1: errors reading hcl blocks: [errors reading hcl blocks: [failed to find local missing]]"},
+		},
+		{
+			name: "dynamic-without-content",
+			config: `
+resource "partial_resource" "resource" {
+  dynamic "rule" {
+    for_each = ["a"]
+  }
+}
+`,
+			expected: Step{"partial_resource": {"resource": {}}},
+			partial:  []string{"step 1: errors reading hcl blocks: [errors reading hcl blocks: [dynamic block rule has no content]]"},
+		},
+		{
+			name: "count-reference",
+			config: `
+resource "partial_resource" "resource" {
+  count = var.instances
+  name  = "value-${count.index}"
+}
+`,
+			expected: Step{"partial_resource": {"resource": {"name": "\"value-${count.index}\""}}},
+			partial:  []string{"step 1: errors reading hcl blocks: [count of partial_resource.resource isn't read, it isn't a literal number]"},
+		},
+		{
+			name: "for-each-list",
+			config: `
+resource "partial_resource" "resource" {
+  for_each = ["a"]
+  name     = each.key
+}
+`,
+			expected: Step{"partial_resource": {"resource": {"name": "each.key"}}},
+			partial:  []string{"step 1: errors reading hcl blocks: [for_each of partial_resource.resource isn't read, it isn't a literal map or set]"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stepsCompLit, err := parser.ParseExpr("[]resource.TestStep{{Config: testConfig}}")
+			if err != nil {
+				t.Fatal(err)
+			}
+			varDecls := map[string]*ast.BasicLit{
+				"testConfig": {Kind: token.STRING, Value: strconv.Quote(tc.config)},
+			}
+			test, err := readStepsCompLit(stepsCompLit.(*ast.CompositeLit), nil, varDecls, nil)
+			if err == nil {
+				t.Error("reading partial config succeeded, expected error")
+			}
+			if !reflect.DeepEqual(test.Steps, []Step{tc.expected}) {
+				t.Errorf("found unexpected steps: %#v, expected %#v", test.Steps, []Step{tc.expected})
+			}
+			if !reflect.DeepEqual(test.Partial, tc.partial) {
+				t.Errorf("found unexpected partial reasons: %q, expected %q", test.Partial, tc.partial)
+			}
+		})
+	}
+}
+
+func TestReadConfigInstances(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   string
+		expected Step
+	}{
+		{
+			name: "count",
+			config: `
+resource "counted_resource" "resource" {
+  count = 2
+  name  = "value-${count.index}"
+}
+`,
+			expected: Step{"counted_resource": {
+				"resource[0]": {"name": "\"value-${0}\""},
+				"resource[1]": {"name": "\"value-${1}\""},
+			}},
+		},
+		{
+			name: "zero-count",
+			config: `
+resource "counted_resource" "resource" {
+  count = 0
+  name  = "value"
+}
+`,
+			expected: Step{"counted_resource": {}},
+		},
+		{
+			name: "for-each-map",
+			config: `
+resource "each_resource" "resource" {
+  for_each = {
+    one = "value-one"
+  }
+  name = each.key
+  rule {
+    description = each.value
+  }
+}
+`,
+			expected: Step{"each_resource": {
+				"resource[\"one\"]": {"name": "\"one\"", "rule.description": "\"value-one\""},
+			}},
+		},
+		{
+			name: "for-each-set",
+			config: `
+resource "each_resource" "resource" {
+  for_each = toset(["a", "b"])
+  name     = "value-${each.value}"
+}
+`,
+			expected: Step{"each_resource": {
+				"resource[\"a\"]": {"name": "\"value-${\"a\"}\""},
+				"resource[\"b\"]": {"name": "\"value-${\"b\"}\""},
+			}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			step, err := readConfigStr(tc.config)
+			if err != nil {
+				t.Fatalf("error reading config: %v", err)
+			}
+			if !reflect.DeepEqual(step, tc.expected) {
+				t.Errorf("found unexpected step: %#v, expected %#v", step, tc.expected)
+			}
+		})
+	}
+}
+
 func TestFlattenResource(t *testing.T) {
 	for _, tc := range []struct {
 		name        string
@@ -0,0 +1,206 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Return the sorted names of the top-level tests that use or refer to any of
+// the given resource types, so that running them covers the resources.
+// A test refers to a resource type when a field of another resource in its
+// config depends on it, as in `network = google_compute_network.default.id`,
+// which catches resources that are declared in parts of the config that
+// couldn't be read. Serial tests are selected by their parent test.
+func SelectTests(tests []*Test, resourceTypes []string) []string {
+	if len(resourceTypes) == 0 {
+		return nil
+	}
+	quoted := make([]string, len(resourceTypes))
+	for i, resourceType := range resourceTypes {
+		quoted[i] = regexp.QuoteMeta(resourceType)
+	}
+	// References start with the resource type or data., and aren't part of a
+	// longer identifier.
+	reference := regexp.MustCompile(`(^|[^\w.-]|data\.)(` + strings.Join(quoted, "|") + `)\.[\w-]+`)
+	types := make(map[string]bool, len(resourceTypes))
+	for _, resourceType := range resourceTypes {
+		types[resourceType] = true
+	}
+
+	selected := make(map[string]bool)
+	for _, test := range tests {
+		if testUses(test, types, reference) {
+			name := test.Name
+			if test.Parent != "" {
+				name = test.Parent
+			}
+			selected[name] = true
+		}
+	}
+	names := make([]string, 0, len(selected))
+	for name := range selected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func testUses(test *Test, types map[string]bool, reference *regexp.Regexp) bool {
+	for _, step := range test.Steps {
+		for resourceType, resources := range step {
+			if types[resourceType] {
+				return true
+			}
+			for _, resource := range resources {
+				for _, value := range resource {
+					if s, ok := value.(string); ok && reference.MatchString(s) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+var testFuncRegex = regexp.MustCompile(`(?m)^func (Test\w+)\(`)
+
+// Return the sorted names of the top-level tests that couldn't be fully read
+// and are declared in a file of the services directory mentioning any of the
+// given resource types, as their configs may use the resources without it
+// showing in their steps. These are tests that were partially read, tests
+// that failed to be read and tests in files that failed to parse, as given by
+// ReadAllTests. Selecting them errs on the side of running too many tests.
+func SelectUnreadTests(servicesDir string, tests []*Test, errs map[string]error, resourceTypes []string) ([]string, error) {
+	if len(resourceTypes) == 0 {
+		return nil, nil
+	}
+	quoted := make([]string, len(resourceTypes))
+	for i, resourceType := range resourceTypes {
+		quoted[i] = regexp.QuoteMeta(resourceType)
+	}
+	mention := regexp.MustCompile(`(^|\W)(` + strings.Join(quoted, "|") + `)(\W|$)`)
+
+	unread := make(map[string]bool)
+	for _, test := range tests {
+		if len(test.Partial) > 0 {
+			unread[test.Name] = true
+			if test.Parent != "" {
+				unread[test.Parent] = true
+			}
+		}
+	}
+	unparsed := make(map[string]bool)
+	for name := range errs {
+		if strings.HasSuffix(name, ".go") {
+			unparsed[filepath.Clean(name)] = true
+		} else {
+			unread[name] = true
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(servicesDir, "*", "*_test.go"))
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]bool)
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if !mention.Match(b) {
+			continue
+		}
+		for _, m := range testFuncRegex.FindAllSubmatch(b, -1) {
+			name := string(m[1])
+			if unparsed[filepath.Clean(file)] || unread[name] {
+				selected[name] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(selected))
+	for name := range selected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Return a regular expression for go test -run matching exactly the given
+// top-level tests, or an empty string if there are none.
+func RunRegex(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	return "^(" + strings.Join(quoted, "|") + ")$"
+}
+
+var iamResourceSuffixes = []string{"_iam_binding", "_iam_member", "_iam_policy"}
+
+// Return the resource types implemented, tested or described by a file of the
+// provider, based on its name, such as google_compute_address for
+// resource_compute_address.go, resource_compute_address_generated_test.go or
+// resource_compute_address_generated_meta.yaml. Returns nil for other files.
+func ResourcesForFile(path string) []string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	for _, suffix := range []string{"_test", "_internal", "_meta", "_generated", "_sweeper"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	var prefix string
+	for _, p := range []string{"resource_", "data_source_", "iam_"} {
+		if strings.HasPrefix(name, p) {
+			prefix = p
+			break
+		}
+	}
+	if prefix == "" {
+		return nil
+	}
+	resourceType := "google_" + strings.TrimPrefix(strings.TrimPrefix(name, prefix), "google_")
+	if prefix != "iam_" {
+		return []string{resourceType}
+	}
+	resourceTypes := make([]string, len(iamResourceSuffixes))
+	for i, suffix := range iamResourceSuffixes {
+		resourceTypes[i] = resourceType + suffix
+	}
+	return resourceTypes
+}
+
+// Return whether a change to the given file of the provider may affect tests
+// of any resource, because the file maps to no resource, such as helpers,
+// transports and *_utils.go files. Documentation files never do.
+func AffectsAllTests(path string) bool {
+	switch filepath.Ext(path) {
+	case ".markdown", ".md":
+		return false
+	}
+	return ResourcesForFile(path) == nil
+}
+
+// Return the sorted names of the given tests that are top-level, for running
+// every test.
+func TopLevelTests(tests []*Test) []string {
+	seen := make(map[string]bool)
+	for _, test := range tests {
+		if test.Parent != "" {
+			seen[test.Parent] = true
+		} else {
+			seen[test.Name] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
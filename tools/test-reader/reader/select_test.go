@@ -0,0 +1,188 @@
+package reader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSelectTests(t *testing.T) {
+	tests, errs := ReadAllTests("testdata")
+	if len(errs) > 0 {
+		t.Fatalf("errors reading tests: %v", errs)
+	}
+	tests = append(tests,
+		&Test{
+			Name: "TestAccDependentResource",
+			Steps: []Step{
+				{"dependent_resource": {"primary": {"network": "referenced_resource.default.id"}}},
+			},
+		},
+		&Test{
+			Name: "TestAccDataSource",
+			Steps: []Step{
+				{"dependent_resource": {"primary": {"zone": "data.referenced_resource.default.zone"}}},
+			},
+		},
+		&Test{
+			Name: "TestAccSimilarResource",
+			Steps: []Step{
+				{"dependent_resource": {"primary": {"network": "other_referenced_resource.default.id"}}},
+			},
+		},
+	)
+	for _, tc := range []struct {
+		name          string
+		resourceTypes []string
+		expected      []string
+	}{
+		{
+			name:          "no-resources",
+			resourceTypes: nil,
+			expected:      nil,
+		},
+		{
+			name:          "used-resources",
+			resourceTypes: []string{"covered_resource", "helper_resource"},
+			expected:      []string{"TestAccCoveredResource", "TestAccFunctionCallResource"},
+		},
+		{
+			name:          "serial-resource",
+			resourceTypes: []string{"serial_resource"},
+			expected:      []string{"TestAccCrossFile", "TestAccSerialResource"},
+		},
+		{
+			name:          "referenced-resource",
+			resourceTypes: []string{"referenced_resource"},
+			expected:      []string{"TestAccDataSource", "TestAccDependentResource"},
+		},
+		{
+			name:          "unused-resource",
+			resourceTypes: []string{"unused_resource"},
+			expected:      []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := SelectTests(tests, tc.resourceTypes); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("unexpected selected tests: %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSelectUnreadTests(t *testing.T) {
+	servicesDir := t.TempDir()
+	serviceDir := filepath.Join(servicesDir, "service")
+	if err := os.Mkdir(serviceDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"broken_test.go":  "package service\n\nfunc TestAccBroken(t *testing.T) {\n\tconfig := `resource \"covered_resource\" \"default\" {`\n",
+		"partial_test.go": "package service\n\nfunc TestAccPartial(t *testing.T) {}\n\nfunc TestAccFailed(t *testing.T) {}\n\nfunc TestAccFull(t *testing.T) {}\n\nfunc testAccPartialConfig() string {\n\treturn `resource \"covered_resource\" \"default\" {}`\n}\n",
+		"other_test.go":   "package service\n\nfunc TestAccOtherPartial(t *testing.T) {}\n\nfunc TestAccOtherFailed(t *testing.T) {}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(serviceDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []*Test{
+		{Name: "TestAccPartial/step", Parent: "TestAccPartial", Partial: []string{"step 1: module"}},
+		{Name: "TestAccFull"},
+		{Name: "TestAccOtherPartial", Partial: []string{"step 1: module"}},
+	}
+	errs := map[string]error{
+		filepath.Join(serviceDir, "broken_test.go"): errors.New("expected '}', found 'EOF'"),
+		"TestAccFailed":      errors.New("unknown config function"),
+		"TestAccOtherFailed": errors.New("unknown config function"),
+	}
+	for _, tc := range []struct {
+		name          string
+		resourceTypes []string
+		expected      []string
+	}{
+		{
+			name:          "no-resources",
+			resourceTypes: nil,
+			expected:      nil,
+		},
+		{
+			name:          "mentioned-resource",
+			resourceTypes: []string{"covered_resource"},
+			expected:      []string{"TestAccBroken", "TestAccFailed", "TestAccPartial"},
+		},
+		{
+			name:          "similar-resource",
+			resourceTypes: []string{"covered"},
+			expected:      []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SelectUnreadTests(servicesDir, tests, errs, tc.resourceTypes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("unexpected selected tests: %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRunRegex(t *testing.T) {
+	for names, expected := range map[*[]string]string{
+		{}:                         "",
+		{"TestAccA"}:               "^(TestAccA)$",
+		{"TestAccA", "TestAccB_x"}: "^(TestAccA|TestAccB_x)$",
+	} {
+		if got := RunRegex(*names); got != expected {
+			t.Errorf("unexpected regex for %v: %q, expected %q", *names, got, expected)
+		}
+	}
+}
+
+func TestResourcesForFile(t *testing.T) {
+	for path, expected := range map[string][]string{
+		"google/services/compute/resource_compute_address.go":                  {"google_compute_address"},
+		"google/services/compute/resource_compute_address_generated_test.go":   {"google_compute_address"},
+		"google/services/compute/resource_compute_address_generated_meta.yaml": {"google_compute_address"},
+		"google/services/compute/resource_compute_address_sweeper.go":          {"google_compute_address"},
+		"google/services/resourcemanager/resource_google_project.go":           {"google_project"},
+		"google/services/compute/data_source_google_compute_address.go":        {"google_compute_address"},
+		"google/services/compute/iam_compute_instance.go":                      {"google_compute_instance_iam_binding", "google_compute_instance_iam_member", "google_compute_instance_iam_policy"},
+		"google/services/compute/compute_instance_helpers.go":                  nil,
+		"website/docs/r/compute_address.html.markdown":                         nil,
+	} {
+		if got := ResourcesForFile(path); !reflect.DeepEqual(got, expected) {
+			t.Errorf("unexpected resources for %s: %v, expected %v", path, got, expected)
+		}
+	}
+}
+
+func TestAffectsAllTests(t *testing.T) {
+	for path, expected := range map[string]bool{
+		"google/services/compute/resource_compute_address.go": false,
+		"google/services/compute/compute_instance_helpers.go": true,
+		"google/services/compute/compute_backend_utils.go":    true,
+		"google/transport/retry_transport.go":                 true,
+		"website/docs/r/compute_address.html.markdown":        false,
+		"CHANGELOG.md": false,
+	} {
+		if got := AffectsAllTests(path); got != expected {
+			t.Errorf("unexpected result for %s: %v, expected %v", path, got, expected)
+		}
+	}
+}
+
+func TestTopLevelTests(t *testing.T) {
+	tests := []*Test{
+		{Name: "TestAccB"},
+		{Name: "TestAccA/one", Parent: "TestAccA"},
+		{Name: "TestAccA/two", Parent: "TestAccA"},
+	}
+	if got, expected := TopLevelTests(tests), []string{"TestAccA", "TestAccB"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected top-level tests: %v, expected %v", got, expected)
+	}
+}
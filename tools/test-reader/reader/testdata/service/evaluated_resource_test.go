@@ -0,0 +1,53 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/acctest"
+)
+
+func TestAccEvaluatedResource(t *testing.T) {
+	context := map[string]interface{}{
+		"name":          "value-one",
+		"enabled":       true,
+		"random_suffix": acctest.RandString(t, 10),
+	}
+	context["size"] = 10
+
+	acctest.VcrTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEvaluatedResource(context),
+			},
+		},
+	})
+}
+
+func testAccEvaluatedResource(context map[string]interface{}) string {
+	return acctest.Nprintf(`
+locals {
+  name = "%{name}-${random_suffix}"
+  alias = local.name
+}
+
+resource "evaluated_resource" "resource" {
+  count   = 2
+  name    = local.alias
+  size    = %{size}
+  enabled = %{enabled}
+  suffix  = "%{random_suffix}"
+
+  dynamic "rule" {
+    for_each = ["a", "b"]
+    content {
+      action = rule.value
+    }
+  }
+
+  lifecycle {
+    prevent_destroy = true
+  }
+}
+`, context)
+}
@@ -0,0 +1,28 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/acctest"
+)
+
+func TestAccVcrSkippedResource(t *testing.T) {
+	// Uses an external service that VCR can't record.
+	acctest.SkipIfVcr(t)
+	acctest.VcrTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVcrSkippedResource(),
+			},
+		},
+	})
+}
+
+func testAccVcrSkippedResource() string {
+	return `
+resource "vcr_skipped_resource" "resource" {
+  field_one = "value-one"
+}
+`
+}